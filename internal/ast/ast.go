@@ -3,6 +3,7 @@ package ast
 import (
 	"bytes"
 	"fmt"
+	"strconv"
 	"strings"
 
 	"github.com/0xmukesh/coco/internal/tokens"
@@ -52,6 +53,11 @@ type IdentifierExpression struct {
 	Token   tokens.Token
 	Literal string
 	Type    cotypes.Type
+	// set by the type checker on the identifier which declares a variable, if the variable is captured by a closure
+	Captured bool
+	// set by the type checker on uses of an optional variable which is known to hold a value at that point,
+	// such uses refer to the value itself rather than the optional
+	Narrowed bool
 }
 
 func (ie *IdentifierExpression) expressionNode() {}
@@ -90,6 +96,64 @@ func (se *StringExpression) SetType(t cotypes.Type) cotypes.Type {
 	return t
 }
 
+// string literal with embedded expressions, eg. "x = ${x + 1}". parts holds the string literals between the
+// embedded expressions and the expressions themselves, in the order they appear in. empty literals are left out
+type InterpolationExpression struct {
+	Token tokens.Token // the STRING_HEAD token
+	Parts []Expression
+	Type  cotypes.Type
+}
+
+func (ie *InterpolationExpression) expressionNode() {}
+func (ie *InterpolationExpression) TokenLiteral() string {
+	return ie.Token.Literal
+}
+func (ie *InterpolationExpression) String() string {
+	var out bytes.Buffer
+
+	out.WriteString("\"")
+	for _, part := range ie.Parts {
+		// string literals keep their surrounding quotes
+		if str, ok := part.(*StringExpression); ok {
+			out.WriteString(strings.ReplaceAll(str.Value[1:len(str.Value)-1], "${", "\\${"))
+		} else {
+			out.WriteString("${" + part.String() + "}")
+		}
+	}
+	out.WriteString("\"")
+
+	return out.String()
+}
+func (ie *InterpolationExpression) GetType() cotypes.Type {
+	return ie.Type
+}
+func (ie *InterpolationExpression) SetType(t cotypes.Type) cotypes.Type {
+	ie.Type = t
+	return t
+}
+
+// single unicode scalar value, eg. 'a' or '\n'
+type CharExpression struct {
+	Token tokens.Token
+	Value rune
+	Type  cotypes.Type
+}
+
+func (ce *CharExpression) expressionNode() {}
+func (ce *CharExpression) TokenLiteral() string {
+	return ce.Token.Literal
+}
+func (ce *CharExpression) String() string {
+	return strconv.QuoteRune(ce.Value)
+}
+func (ce *CharExpression) GetType() cotypes.Type {
+	return ce.Type
+}
+func (ce *CharExpression) SetType(t cotypes.Type) cotypes.Type {
+	ce.Type = t
+	return t
+}
+
 type BooleanExpression struct {
 	Token tokens.Token
 	Value bool
@@ -111,6 +175,26 @@ func (be *BooleanExpression) SetType(t cotypes.Type) cotypes.Type {
 	return t
 }
 
+type NoneExpression struct {
+	Token tokens.Token
+	Type  cotypes.Type
+}
+
+func (ne *NoneExpression) expressionNode() {}
+func (ne *NoneExpression) TokenLiteral() string {
+	return ne.Token.Literal
+}
+func (ne *NoneExpression) String() string {
+	return "none"
+}
+func (ne *NoneExpression) GetType() cotypes.Type {
+	return ne.Type
+}
+func (ne *NoneExpression) SetType(t cotypes.Type) cotypes.Type {
+	ne.Type = t
+	return t
+}
+
 type IntegerExpression struct {
 	Token tokens.Token
 	Value int64
@@ -153,11 +237,13 @@ func (fe *FloatExpression) SetType(t cotypes.Type) cotypes.Type {
 	return t
 }
 
-// <prefix><expression>
+// <prefix><expression> or <expression><postfix>
+// only ++ and -- can be used as postfix operators
 type UnaryExpression struct {
-	Token tokens.Token
-	Expr  Expression
-	Type  cotypes.Type
+	Token     tokens.Token
+	Expr      Expression
+	IsPostfix bool
+	Type      cotypes.Type
 }
 
 func (ue *UnaryExpression) expressionNode() {}
@@ -167,8 +253,13 @@ func (ue *UnaryExpression) TokenLiteral() string {
 func (ue *UnaryExpression) String() string {
 	var out bytes.Buffer
 
-	out.WriteString(ue.TokenLiteral())
-	out.WriteString(ue.Expr.String())
+	if ue.IsPostfix {
+		out.WriteString(ue.Expr.String())
+		out.WriteString(ue.TokenLiteral())
+	} else {
+		out.WriteString(ue.TokenLiteral())
+		out.WriteString(ue.Expr.String())
+	}
 
 	return out.String()
 }
@@ -180,6 +271,31 @@ func (ue *UnaryExpression) SetType(t cotypes.Type) cotypes.Type {
 	return t
 }
 
+// <expression>?
+// evaluates to the value of an ok result, an err result is returned from the enclosing function right away
+type TryExpression struct {
+	Token tokens.Token
+	Expr  Expression
+	Type  cotypes.Type
+	// result type of the enclosing function, which the error is returned as
+	FunctionResult cotypes.Type
+}
+
+func (te *TryExpression) expressionNode() {}
+func (te *TryExpression) TokenLiteral() string {
+	return te.Token.Literal
+}
+func (te *TryExpression) String() string {
+	return te.Expr.String() + "?"
+}
+func (te *TryExpression) GetType() cotypes.Type {
+	return te.Type
+}
+func (te *TryExpression) SetType(t cotypes.Type) cotypes.Type {
+	te.Type = t
+	return t
+}
+
 // <left><operator><right>
 type BinaryExpression struct {
 	Left     Expression
@@ -237,11 +353,42 @@ func (ge *GroupedExpression) SetType(t cotypes.Type) cotypes.Type {
 	return t
 }
 
+// (<expression>, <expression>, ...)
+// tuples have at least two elements, a single parenthesized expression is a grouped expression
+type TupleExpression struct {
+	Token    tokens.Token
+	Elements []Expression
+	Type     cotypes.Type
+}
+
+func (te *TupleExpression) expressionNode() {}
+func (te *TupleExpression) TokenLiteral() string {
+	return te.Token.Literal
+}
+func (te *TupleExpression) String() string {
+	elements := []string{}
+	for _, element := range te.Elements {
+		elements = append(elements, element.String())
+	}
+
+	return "(" + strings.Join(elements, ", ") + ")"
+}
+func (te *TupleExpression) GetType() cotypes.Type {
+	return te.Type
+}
+func (te *TupleExpression) SetType(t cotypes.Type) cotypes.Type {
+	te.Type = t
+	return t
+}
+
 // if (<condition>) { <consequence> } ?(else { <alternative> })
+// if (let <binding> = <optional>) { <consequence> } ?(else { <alternative> })
 // ?(...) = optional
 type IfExpression struct {
-	Token       tokens.Token
+	Token tokens.Token
+	// optional value which is unwrapped into the binding, if the binding is set
 	Condition   Expression
+	Binding     *IdentifierExpression
 	Consequence *BlockStatement
 	Alternative *BlockStatement
 	Type        cotypes.Type
@@ -255,7 +402,13 @@ func (ie *IfExpression) String() string {
 	var out bytes.Buffer
 
 	out.WriteString("if ")
-	out.WriteString(ie.Condition.String())
+
+	if ie.Binding != nil {
+		out.WriteString("(let " + ie.Binding.String() + " = " + ie.Condition.String() + ")")
+	} else {
+		out.WriteString(ie.Condition.String())
+	}
+
 	out.WriteString(" ")
 	out.WriteString(ie.Consequence.String())
 
@@ -274,12 +427,398 @@ func (ie *IfExpression) SetType(t cotypes.Type) cotypes.Type {
 	return t
 }
 
-// fn (<parameters>) { <body> }
+// [<expression>, <expression>, ...]
+type ArrayExpression struct {
+	Token    tokens.Token
+	Elements []Expression
+	Type     cotypes.Type
+}
+
+func (ae *ArrayExpression) expressionNode() {}
+func (ae *ArrayExpression) TokenLiteral() string {
+	return ae.Token.Literal
+}
+func (ae *ArrayExpression) String() string {
+	elements := []string{}
+	for _, e := range ae.Elements {
+		elements = append(elements, e.String())
+	}
+
+	return "[" + strings.Join(elements, ", ") + "]"
+}
+func (ae *ArrayExpression) GetType() cotypes.Type {
+	return ae.Type
+}
+func (ae *ArrayExpression) SetType(t cotypes.Type) cotypes.Type {
+	ae.Type = t
+	return t
+}
+
+// { <key>: <value>, <key>: <value>, ... }
+type MapExpression struct {
+	Token  tokens.Token
+	Keys   []Expression
+	Values []Expression
+	Type   cotypes.Type
+}
+
+func (me *MapExpression) expressionNode() {}
+func (me *MapExpression) TokenLiteral() string {
+	return me.Token.Literal
+}
+func (me *MapExpression) String() string {
+	pairs := []string{}
+	for i := range me.Keys {
+		pairs = append(pairs, me.Keys[i].String()+": "+me.Values[i].String())
+	}
+
+	return "{" + strings.Join(pairs, ", ") + "}"
+}
+func (me *MapExpression) GetType() cotypes.Type {
+	return me.Type
+}
+func (me *MapExpression) SetType(t cotypes.Type) cotypes.Type {
+	me.Type = t
+	return t
+}
+
+// <expression>[<index>]
+type IndexExpression struct {
+	Token tokens.Token
+	Left  Expression
+	Index Expression
+	Type  cotypes.Type
+}
+
+func (ie *IndexExpression) expressionNode() {}
+func (ie *IndexExpression) TokenLiteral() string {
+	return ie.Token.Literal
+}
+func (ie *IndexExpression) String() string {
+	return ie.Left.String() + "[" + ie.Index.String() + "]"
+}
+func (ie *IndexExpression) GetType() cotypes.Type {
+	return ie.Type
+}
+func (ie *IndexExpression) SetType(t cotypes.Type) cotypes.Type {
+	ie.Type = t
+	return t
+}
+
+// <field>: <value>
+type StructFieldValue struct {
+	Identifier *IdentifierExpression
+	Value      Expression
+}
+
+func (sfv *StructFieldValue) String() string {
+	return sfv.Identifier.String() + ": " + sfv.Value.String()
+}
+
+// <identifier> { <field>: <value>, <field>: <value>, ... }
+type StructExpression struct {
+	Token  tokens.Token
+	Name   *IdentifierExpression
+	Fields []*StructFieldValue
+	Type   cotypes.Type
+}
+
+func (se *StructExpression) expressionNode() {}
+func (se *StructExpression) TokenLiteral() string {
+	return se.Token.Literal
+}
+func (se *StructExpression) String() string {
+	fields := []string{}
+	for _, f := range se.Fields {
+		fields = append(fields, f.String())
+	}
+
+	return se.Name.String() + " {" + strings.Join(fields, ", ") + "}"
+}
+func (se *StructExpression) GetType() cotypes.Type {
+	return se.Type
+}
+func (se *StructExpression) SetType(t cotypes.Type) cotypes.Type {
+	se.Type = t
+	return t
+}
+
+// <enum>::<variant>(<value>, <value>, ...)
+// values are left out for variants without payload, <enum>::<variant>
+type VariantExpression struct {
+	Token     tokens.Token
+	Enum      *IdentifierExpression
+	Variant   *IdentifierExpression
+	Arguments []Expression
+	Type      cotypes.Type
+}
+
+func (ve *VariantExpression) expressionNode() {}
+func (ve *VariantExpression) TokenLiteral() string {
+	return ve.Token.Literal
+}
+func (ve *VariantExpression) String() string {
+	out := ve.Enum.String() + "::" + ve.Variant.String()
+	if len(ve.Arguments) == 0 {
+		return out
+	}
+
+	args := []string{}
+	for _, a := range ve.Arguments {
+		args = append(args, a.String())
+	}
+
+	return out + "(" + strings.Join(args, ", ") + ")"
+}
+func (ve *VariantExpression) GetType() cotypes.Type {
+	return ve.Type
+}
+func (ve *VariantExpression) SetType(t cotypes.Type) cotypes.Type {
+	ve.Type = t
+	return t
+}
+
+// <variant>(<binding>, <binding>, ...) => <body>
+// _ => <body>
+// bodies which are a single expression are wrapped into a block
+type MatchArm struct {
+	Token    tokens.Token
+	Variant  *IdentifierExpression
+	Bindings []*IdentifierExpression
+	Body     *BlockStatement
+}
+
+// the wildcard arm matches all of the variants which are not matched by the other arms
+func (ma *MatchArm) IsWildcard() bool {
+	return ma.Variant.Literal == "_"
+}
+
+func (ma *MatchArm) String() string {
+	out := ma.Variant.String()
+	if len(ma.Bindings) > 0 {
+		bindings := []string{}
+		for _, b := range ma.Bindings {
+			bindings = append(bindings, b.String())
+		}
+
+		out += "(" + strings.Join(bindings, ", ") + ")"
+	}
+
+	return out + " => " + ma.Body.String()
+}
+
+// match (<subject>) { <arm>, <arm>, ... }
+type MatchExpression struct {
+	Token   tokens.Token
+	Subject Expression
+	Arms    []*MatchArm
+	Type    cotypes.Type
+}
+
+func (me *MatchExpression) expressionNode() {}
+func (me *MatchExpression) TokenLiteral() string {
+	return me.Token.Literal
+}
+func (me *MatchExpression) String() string {
+	arms := []string{}
+	for _, a := range me.Arms {
+		arms = append(arms, a.String())
+	}
+
+	return "match (" + me.Subject.String() + ") {" + strings.Join(arms, ", ") + "}"
+}
+func (me *MatchExpression) GetType() cotypes.Type {
+	return me.Type
+}
+func (me *MatchExpression) SetType(t cotypes.Type) cotypes.Type {
+	me.Type = t
+	return t
+}
+
+// <expression>.<field>
+type FieldExpression struct {
+	Token tokens.Token
+	Left  Expression
+	Field *IdentifierExpression
+	Type  cotypes.Type
+}
+
+func (fe *FieldExpression) expressionNode() {}
+func (fe *FieldExpression) TokenLiteral() string {
+	return fe.Token.Literal
+}
+func (fe *FieldExpression) String() string {
+	return fe.Left.String() + "." + fe.Field.String()
+}
+func (fe *FieldExpression) GetType() cotypes.Type {
+	return fe.Type
+}
+func (fe *FieldExpression) SetType(t cotypes.Type) cotypes.Type {
+	fe.Type = t
+	return t
+}
+
+// <type>
+type TypeAnnotation interface {
+	Node
+	typeAnnotationNode()
+}
+
+// int, float, bool, string, void
+type NamedTypeAnnotation struct {
+	Token tokens.Token
+	Name  string
+}
+
+func (nta *NamedTypeAnnotation) typeAnnotationNode() {}
+func (nta *NamedTypeAnnotation) TokenLiteral() string {
+	return nta.Token.Literal
+}
+func (nta *NamedTypeAnnotation) String() string {
+	return nta.Name
+}
+
+// <type>?
+type OptionalTypeAnnotation struct {
+	Token tokens.Token
+	Inner TypeAnnotation
+}
+
+func (ota *OptionalTypeAnnotation) typeAnnotationNode() {}
+func (ota *OptionalTypeAnnotation) TokenLiteral() string {
+	return ota.Token.Literal
+}
+func (ota *OptionalTypeAnnotation) String() string {
+	return ota.Inner.String() + "?"
+}
+
+// Result<<value type>, <error type>>
+type ResultTypeAnnotation struct {
+	Token tokens.Token
+	Value TypeAnnotation
+	Error TypeAnnotation
+}
+
+func (rta *ResultTypeAnnotation) typeAnnotationNode() {}
+func (rta *ResultTypeAnnotation) TokenLiteral() string {
+	return rta.Token.Literal
+}
+func (rta *ResultTypeAnnotation) String() string {
+	return "Result<" + rta.Value.String() + ", " + rta.Error.String() + ">"
+}
+
+// [<type>; <size>]
+type ArrayTypeAnnotation struct {
+	Token   tokens.Token
+	Element TypeAnnotation
+	Size    int64
+}
+
+func (ata *ArrayTypeAnnotation) typeAnnotationNode() {}
+func (ata *ArrayTypeAnnotation) TokenLiteral() string {
+	return ata.Token.Literal
+}
+func (ata *ArrayTypeAnnotation) String() string {
+	return fmt.Sprintf("[%s; %d]", ata.Element.String(), ata.Size)
+}
+
+// (<type>, <type>, ...)
+type TupleTypeAnnotation struct {
+	Token    tokens.Token
+	Elements []TypeAnnotation
+}
+
+func (tta *TupleTypeAnnotation) typeAnnotationNode() {}
+func (tta *TupleTypeAnnotation) TokenLiteral() string {
+	return tta.Token.Literal
+}
+func (tta *TupleTypeAnnotation) String() string {
+	elements := []string{}
+	for _, element := range tta.Elements {
+		elements = append(elements, element.String())
+	}
+
+	return "(" + strings.Join(elements, ", ") + ")"
+}
+
+// []<type>
+type ListTypeAnnotation struct {
+	Token   tokens.Token
+	Element TypeAnnotation
+}
+
+func (lta *ListTypeAnnotation) typeAnnotationNode() {}
+func (lta *ListTypeAnnotation) TokenLiteral() string {
+	return lta.Token.Literal
+}
+func (lta *ListTypeAnnotation) String() string {
+	return "[]" + lta.Element.String()
+}
+
+// map[<key type>]<value type>
+type MapTypeAnnotation struct {
+	Token tokens.Token
+	Key   TypeAnnotation
+	Value TypeAnnotation
+}
+
+func (mta *MapTypeAnnotation) typeAnnotationNode() {}
+func (mta *MapTypeAnnotation) TokenLiteral() string {
+	return mta.Token.Literal
+}
+func (mta *MapTypeAnnotation) String() string {
+	return "map[" + mta.Key.String() + "]" + mta.Value.String()
+}
+
+// fn(<parameter types>) ?(: <return type>)
+type FunctionTypeAnnotation struct {
+	Token  tokens.Token
+	Params []TypeAnnotation
+	Return TypeAnnotation
+}
+
+func (fta *FunctionTypeAnnotation) typeAnnotationNode() {}
+func (fta *FunctionTypeAnnotation) TokenLiteral() string {
+	return fta.Token.Literal
+}
+func (fta *FunctionTypeAnnotation) String() string {
+	params := []string{}
+	for _, p := range fta.Params {
+		params = append(params, p.String())
+	}
+
+	if fta.Return == nil {
+		return "fn(" + strings.Join(params, ", ") + ")"
+	}
+
+	return "fn(" + strings.Join(params, ", ") + "): " + fta.Return.String()
+}
+
+// <identifier>: <type>
+type FunctionParameter struct {
+	Identifier *IdentifierExpression
+	Annotation TypeAnnotation
+}
+
+func (fp *FunctionParameter) String() string {
+	if fp.Annotation == nil {
+		return fp.Identifier.String()
+	}
+
+	return fp.Identifier.String() + ": " + fp.Annotation.String()
+}
+
+// fn (<parameters>) ?(: <return type>) { <body> }
+// ?(...) = optional
 type FunctionExpression struct {
-	Token      tokens.Token
-	Parameters []*IdentifierExpression
-	Body       *BlockStatement
-	Type       cotypes.Type
+	Token            tokens.Token
+	Parameters       []*FunctionParameter
+	ReturnAnnotation TypeAnnotation
+	ReturnType       cotypes.Type
+	Body             *BlockStatement
+	Type             cotypes.Type
+	// names of the variables of the enclosing scopes which are used by the function, set by the type checker
+	Captures []string
 }
 
 func (fe *FunctionExpression) expressionNode() {}
@@ -298,6 +837,12 @@ func (fe *FunctionExpression) String() string {
 	out.WriteString("(")
 	out.WriteString(strings.Join(params, ", "))
 	out.WriteString(")")
+
+	if fe.ReturnAnnotation != nil {
+		out.WriteString(": ")
+		out.WriteString(fe.ReturnAnnotation.String())
+	}
+
 	out.WriteString(" ")
 	out.WriteString(fe.Body.String())
 
@@ -311,14 +856,19 @@ func (fe *FunctionExpression) SetType(t cotypes.Type) cotypes.Type {
 	return t
 }
 
-// <identifier>(<arguments>)
+// <callee>(<arguments>)
 type CallExpression struct {
-	Token       tokens.Token
-	Identifier  *IdentifierExpression
+	Token      tokens.Token
+	Identifier *IdentifierExpression
+	// function value which is called, set instead of Identifier if the callee is not an identifier. the type checker
+	// also sets it for identifiers which refer to variables rather than builtins and functions
+	Callee      Expression
 	Arguments   []Expression
 	Type        cotypes.Type
 	IsBuiltin   bool
 	BuiltinKind *BuiltinsKind
+	// type arguments of calls to generic functions, inferred from the arguments by the type checker
+	TypeArguments []cotypes.Type
 }
 
 func (ce *CallExpression) expressionNode() {}
@@ -333,7 +883,11 @@ func (ce *CallExpression) String() string {
 		args = append(args, a.String())
 	}
 
-	out.WriteString(ce.Identifier.String())
+	if ce.Identifier != nil {
+		out.WriteString(ce.Identifier.String())
+	} else {
+		out.WriteString(ce.Callee.String())
+	}
 	out.WriteString("(")
 	out.WriteString(strings.Join(args, ", "))
 	out.WriteString(")")
@@ -348,10 +902,33 @@ func (ce *CallExpression) SetType(t cotypes.Type) cotypes.Type {
 	return t
 }
 
-// let <identifier> = <value>
+// implicit conversion of <expression> to <type>, inserted by the typechecker (eg. int to float widening)
+type CastExpression struct {
+	Expr Expression
+	Type cotypes.Type
+}
+
+func (ce *CastExpression) expressionNode() {}
+func (ce *CastExpression) TokenLiteral() string {
+	return ce.Expr.TokenLiteral()
+}
+func (ce *CastExpression) String() string {
+	return ce.Expr.String()
+}
+func (ce *CastExpression) GetType() cotypes.Type {
+	return ce.Type
+}
+func (ce *CastExpression) SetType(t cotypes.Type) cotypes.Type {
+	ce.Type = t
+	return t
+}
+
+// let <identifier> ?(: <type>) ?(= <value>)
+// ?(...) = optional, atleast one of them must be present
 type LetStatement struct {
 	Token      tokens.Token
 	Identifier *IdentifierExpression
+	Annotation TypeAnnotation
 	Value      Expression
 }
 
@@ -365,6 +942,11 @@ func (ls *LetStatement) String() string {
 	out.WriteString(ls.TokenLiteral() + " ")
 	out.WriteString(ls.Identifier.String())
 
+	if ls.Annotation != nil {
+		out.WriteString(": ")
+		out.WriteString(ls.Annotation.String())
+	}
+
 	if ls.Value != nil {
 		out.WriteString(" = ")
 		out.WriteString(ls.Value.String())
@@ -373,13 +955,166 @@ func (ls *LetStatement) String() string {
 	return out.String()
 }
 
+// let (<identifier>, <identifier>, ...) ?(: <type>) = <value>
+// declares a variable for each element of a tuple, elements bound to _ are ignored
+type DestructuringStatement struct {
+	Token       tokens.Token
+	Identifiers []*IdentifierExpression
+	Annotation  TypeAnnotation
+	Value       Expression
+}
+
+func (ds *DestructuringStatement) statementNode() {}
+func (ds *DestructuringStatement) TokenLiteral() string {
+	return ds.Token.Literal
+}
+func (ds *DestructuringStatement) String() string {
+	var out bytes.Buffer
+
+	identifiers := []string{}
+	for _, identifier := range ds.Identifiers {
+		identifiers = append(identifiers, identifier.String())
+	}
+
+	out.WriteString(ds.TokenLiteral() + " (")
+	out.WriteString(strings.Join(identifiers, ", "))
+	out.WriteString(")")
+
+	if ds.Annotation != nil {
+		out.WriteString(": ")
+		out.WriteString(ds.Annotation.String())
+	}
+
+	out.WriteString(" = ")
+	out.WriteString(ds.Value.String())
+
+	return out.String()
+}
+
+// <identifier> ?(: <constraint>)
+// ?(...) = optional
+type TypeParameter struct {
+	Identifier *IdentifierExpression
+	Constraint *IdentifierExpression
+}
+
+func (tp *TypeParameter) String() string {
+	if tp.Constraint == nil {
+		return tp.Identifier.String()
+	}
+
+	return tp.Identifier.String() + ": " + tp.Constraint.String()
+}
+
+// fn <identifier> ?(<<type parameters>>) (<parameters>) ?(: <return type>) { <body> }
+// ?(...) = optional
+type FunctionStatement struct {
+	Token          tokens.Token
+	Identifier     *IdentifierExpression
+	TypeParameters []*TypeParameter
+	Function       *FunctionExpression
+	// instances of a generic function, one for each distinct set of type arguments it is called with. set by the type checker
+	Instances []*FunctionStatement
+}
+
+func (fs *FunctionStatement) statementNode() {}
+func (fs *FunctionStatement) TokenLiteral() string {
+	return fs.Token.Literal
+}
+func (fs *FunctionStatement) String() string {
+	var out bytes.Buffer
+
+	params := []string{}
+	for _, p := range fs.Function.Parameters {
+		params = append(params, p.String())
+	}
+
+	out.WriteString(fs.TokenLiteral() + " ")
+	out.WriteString(fs.Identifier.String())
+
+	if len(fs.TypeParameters) > 0 {
+		typeParams := []string{}
+		for _, tp := range fs.TypeParameters {
+			typeParams = append(typeParams, tp.String())
+		}
+
+		out.WriteString("<" + strings.Join(typeParams, ", ") + ">")
+	}
+
+	out.WriteString("(")
+	out.WriteString(strings.Join(params, ", "))
+	out.WriteString(")")
+
+	if fs.Function.ReturnAnnotation != nil {
+		out.WriteString(": ")
+		out.WriteString(fs.Function.ReturnAnnotation.String())
+	}
+
+	out.WriteString(" ")
+	out.WriteString(fs.Function.Body.String())
+
+	return out.String()
+}
+
+// name of the instance of a generic function for the given type arguments, eg. max<int>
+func InstanceName(name string, typeArgs []cotypes.Type) string {
+	args := []string{}
+	for _, t := range typeArgs {
+		args = append(args, t.String())
+	}
+
+	return name + "<" + strings.Join(args, ", ") + ">"
+}
+
+// const <identifier> ?(: <type>) = <value>
+// ?(...) = optional
+type ConstStatement struct {
+	Token      tokens.Token
+	Identifier *IdentifierExpression
+	Annotation TypeAnnotation
+	Value      Expression
+}
+
+func (cs *ConstStatement) statementNode() {}
+func (cs *ConstStatement) TokenLiteral() string {
+	return cs.Token.Literal
+}
+func (cs *ConstStatement) String() string {
+	var out bytes.Buffer
+
+	out.WriteString(cs.TokenLiteral() + " ")
+	out.WriteString(cs.Identifier.String())
+
+	if cs.Annotation != nil {
+		out.WriteString(": ")
+		out.WriteString(cs.Annotation.String())
+	}
+
+	out.WriteString(" = ")
+	out.WriteString(cs.Value.String())
+
+	return out.String()
+}
+
 // <identifier> = <value>
+// <identifier> (+= | -= | *= | /=) <value>
 type AssignmentStatement struct {
 	Token      tokens.Token
 	Identifier *IdentifierExpression
+	Operator   tokens.Token
 	Value      Expression
 }
 
+// returns the binary operator applied by a compound assignment operator (eg. + for +=), false for plain assignments
+func CompoundOperator(operator tokens.Token) (tokens.TokenType, bool) {
+	op, ok := tokens.COMPOUND_ASSIGNMENT_OPERATORS[operator.Type]
+	return op, ok
+}
+
+func (as *AssignmentStatement) CompoundOperator() (tokens.TokenType, bool) {
+	return CompoundOperator(as.Operator)
+}
+
 func (as *AssignmentStatement) statementNode() {}
 func (as *AssignmentStatement) TokenLiteral() string {
 	return as.Token.Literal
@@ -387,13 +1122,130 @@ func (as *AssignmentStatement) TokenLiteral() string {
 func (as *AssignmentStatement) String() string {
 	var out bytes.Buffer
 
+	operator := "="
+	if as.Operator.Literal != "" {
+		operator = as.Operator.Literal
+	}
+
 	out.WriteString(as.Identifier.Literal)
-	out.WriteString(" = ")
+	out.WriteString(" " + operator + " ")
 	out.WriteString(as.Value.String())
 
 	return out.String()
 }
 
+// <expression>[<index>] = <value>
+// <expression>[<index>] (+= | -= | *= | /=) <value>
+type IndexAssignmentStatement struct {
+	Token    tokens.Token
+	Target   *IndexExpression
+	Operator tokens.Token
+	Value    Expression
+}
+
+func (ias *IndexAssignmentStatement) CompoundOperator() (tokens.TokenType, bool) {
+	return CompoundOperator(ias.Operator)
+}
+
+func (ias *IndexAssignmentStatement) statementNode() {}
+func (ias *IndexAssignmentStatement) TokenLiteral() string {
+	return ias.Token.Literal
+}
+func (ias *IndexAssignmentStatement) String() string {
+	return ias.Target.String() + " " + ias.Operator.Literal + " " + ias.Value.String()
+}
+
+// <expression>.<field> = <value>
+// <expression>.<field> (+= | -= | *= | /=) <value>
+type FieldAssignmentStatement struct {
+	Token    tokens.Token
+	Target   *FieldExpression
+	Operator tokens.Token
+	Value    Expression
+}
+
+func (fas *FieldAssignmentStatement) CompoundOperator() (tokens.TokenType, bool) {
+	return CompoundOperator(fas.Operator)
+}
+
+func (fas *FieldAssignmentStatement) statementNode() {}
+func (fas *FieldAssignmentStatement) TokenLiteral() string {
+	return fas.Token.Literal
+}
+func (fas *FieldAssignmentStatement) String() string {
+	return fas.Target.String() + " " + fas.Operator.Literal + " " + fas.Value.String()
+}
+
+// <variant>(<type>, <type>, ...)
+// types are left out for variants without payload
+type EnumVariant struct {
+	Identifier *IdentifierExpression
+	Payload    []TypeAnnotation
+}
+
+func (ev *EnumVariant) String() string {
+	if len(ev.Payload) == 0 {
+		return ev.Identifier.String()
+	}
+
+	payload := []string{}
+	for _, p := range ev.Payload {
+		payload = append(payload, p.String())
+	}
+
+	return ev.Identifier.String() + "(" + strings.Join(payload, ", ") + ")"
+}
+
+// enum <identifier> { <variant>, <variant>, ... }
+type EnumStatement struct {
+	Token      tokens.Token
+	Identifier *IdentifierExpression
+	Variants   []*EnumVariant
+}
+
+func (es *EnumStatement) statementNode() {}
+func (es *EnumStatement) TokenLiteral() string {
+	return es.Token.Literal
+}
+func (es *EnumStatement) String() string {
+	variants := []string{}
+	for _, v := range es.Variants {
+		variants = append(variants, v.String())
+	}
+
+	return es.TokenLiteral() + " " + es.Identifier.String() + " {" + strings.Join(variants, ", ") + "}"
+}
+
+// <field>: <type>
+type StructField struct {
+	Identifier *IdentifierExpression
+	Annotation TypeAnnotation
+}
+
+func (sf *StructField) String() string {
+	return sf.Identifier.String() + ": " + sf.Annotation.String()
+}
+
+// struct <identifier> { <field>: <type>, <field>: <type>, ... }
+type StructStatement struct {
+	Token      tokens.Token
+	Identifier *IdentifierExpression
+	Fields     []*StructField
+}
+
+func (ss *StructStatement) statementNode() {}
+func (ss *StructStatement) TokenLiteral() string {
+	return ss.Token.Literal
+}
+func (ss *StructStatement) String() string {
+	fields := []string{}
+	for _, f := range ss.Fields {
+		fields = append(fields, f.String())
+	}
+
+	return ss.TokenLiteral() + " " + ss.Identifier.String() + " {" + strings.Join(fields, ", ") + "}"
+}
+
 // return <expr>
 type ReturnStatement struct {
 	Token tokens.Token
@@ -417,9 +1269,11 @@ func (rs *ReturnStatement) String() string {
 	return out.String()
 }
 
-// while ( <condition> ) { <body> }
+// ?(<label>:) while ( <condition> ) { <body> }
+// ?(...) = optional
 type WhileStatement struct {
 	Token     tokens.Token
+	Label     *IdentifierExpression
 	Condition Expression
 	Body      *BlockStatement
 }
@@ -431,6 +1285,10 @@ func (ws *WhileStatement) TokenLiteral() string {
 func (ws *WhileStatement) String() string {
 	var out bytes.Buffer
 
+	if ws.Label != nil {
+		out.WriteString(ws.Label.String() + ": ")
+	}
+
 	out.WriteString(ws.TokenLiteral())
 	out.WriteString(" ")
 	out.WriteString(ws.Condition.String())
@@ -440,13 +1298,46 @@ func (ws *WhileStatement) String() string {
 	return out.String()
 }
 
-// for ( ?(<initialization>); ?(<condition>); ?(<update>) ) { <body> }
+// ?(<label>:) for (<identifier> in <iterable>) { <body> }
+// ?(...) = optional
+type ForInStatement struct {
+	Token      tokens.Token
+	Label      *IdentifierExpression
+	Identifier *IdentifierExpression
+	Iterable   Expression
+	Body       *BlockStatement
+}
+
+func (fis *ForInStatement) statementNode() {}
+func (fis *ForInStatement) TokenLiteral() string {
+	return fis.Token.Literal
+}
+func (fis *ForInStatement) String() string {
+	var out bytes.Buffer
+
+	if fis.Label != nil {
+		out.WriteString(fis.Label.String() + ": ")
+	}
+
+	out.WriteString(fis.TokenLiteral())
+	out.WriteString(" (")
+	out.WriteString(fis.Identifier.String())
+	out.WriteString(" in ")
+	out.WriteString(fis.Iterable.String())
+	out.WriteString(") ")
+	out.WriteString(fis.Body.String())
+
+	return out.String()
+}
+
+// ?(<label>:) for ( ?(<initialization>); ?(<condition>); ?(<update>) ) { <body> }
 // ?(...) = optional
 type ForStatement struct {
 	Token          tokens.Token
+	Label          *IdentifierExpression
 	Initialization Statement
 	Condition      Expression
-	Update         Expression
+	Update         Statement
 	Body           *BlockStatement
 }
 
@@ -457,6 +1348,10 @@ func (fs *ForStatement) TokenLiteral() string {
 func (fs *ForStatement) String() string {
 	var out bytes.Buffer
 
+	if fs.Label != nil {
+		out.WriteString(fs.Label.String() + ": ")
+	}
+
 	out.WriteString(fs.TokenLiteral())
 	out.WriteString(" ")
 	out.WriteString("(")
@@ -484,6 +1379,44 @@ func (fs *ForStatement) String() string {
 	return out.String()
 }
 
+// break ?(<label>)
+// ?(...) = optional
+type BreakStatement struct {
+	Token tokens.Token
+	Label *IdentifierExpression
+}
+
+func (bs *BreakStatement) statementNode() {}
+func (bs *BreakStatement) TokenLiteral() string {
+	return bs.Token.Literal
+}
+func (bs *BreakStatement) String() string {
+	if bs.Label != nil {
+		return bs.TokenLiteral() + " " + bs.Label.String()
+	}
+
+	return bs.TokenLiteral()
+}
+
+// continue ?(<label>)
+// ?(...) = optional
+type ContinueStatement struct {
+	Token tokens.Token
+	Label *IdentifierExpression
+}
+
+func (cs *ContinueStatement) statementNode() {}
+func (cs *ContinueStatement) TokenLiteral() string {
+	return cs.Token.Literal
+}
+func (cs *ContinueStatement) String() string {
+	if cs.Label != nil {
+		return cs.TokenLiteral() + " " + cs.Label.String()
+	}
+
+	return cs.TokenLiteral()
+}
+
 type BlockStatement struct {
 	Token      tokens.Token
 	Statements []Statement
@@ -0,0 +1,56 @@
+package ast
+
+import (
+	"reflect"
+
+	cotypes "github.com/0xmukesh/coco/internal/types"
+)
+
+var typeInterface = reflect.TypeFor[cotypes.Type]()
+
+// returns a deep copy of the node. the type checker annotates and rewrites nodes in place, so generic functions
+// are copied before being type checked for each set of type arguments. types are immutable and shared between copies
+func Clone[T Node](node T) T {
+	return cloneValue(reflect.ValueOf(node)).Interface().(T)
+}
+
+func cloneValue(v reflect.Value) reflect.Value {
+	switch v.Kind() {
+	case reflect.Pointer:
+		if v.IsNil() {
+			return v
+		}
+
+		c := reflect.New(v.Type().Elem())
+		c.Elem().Set(cloneValue(v.Elem()))
+		return c
+	case reflect.Interface:
+		if v.IsNil() || v.Type() == typeInterface {
+			return v
+		}
+
+		c := reflect.New(v.Type()).Elem()
+		c.Set(cloneValue(v.Elem()))
+		return c
+	case reflect.Struct:
+		c := reflect.New(v.Type()).Elem()
+		for i := range v.NumField() {
+			c.Field(i).Set(cloneValue(v.Field(i)))
+		}
+
+		return c
+	case reflect.Slice:
+		if v.IsNil() {
+			return v
+		}
+
+		c := reflect.MakeSlice(v.Type(), v.Len(), v.Len())
+		for i := range v.Len() {
+			c.Index(i).Set(cloneValue(v.Index(i)))
+		}
+
+		return c
+	default:
+		return v
+	}
+}
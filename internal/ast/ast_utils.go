@@ -7,8 +7,16 @@ type BuiltinsKind int
 const (
 	BuiltinFuncPrint BuiltinsKind = iota
 	BuiltinFuncExit
-	BuiltinFuncInt
-	BuiltinFuncFloat
+	BuiltinFuncConvert
+	BuiltinFuncLen
+	BuiltinFuncPush
+	BuiltinFuncPop
+	BuiltinFuncGet
+	BuiltinFuncSet
+	BuiltinFuncHas
+	BuiltinFuncDelete
+	BuiltinFuncOk
+	BuiltinFuncErr
 )
 
 func NewIntegerExpr(value int64) Expression {
@@ -35,12 +43,126 @@ func NewStringExpr(value string) Expression {
 	}
 }
 
+func NewInterpolationExpr(parts ...Expression) Expression {
+	return &InterpolationExpression{
+		Parts: parts,
+	}
+}
+
+func NewCharExpr(value rune) Expression {
+	return &CharExpression{
+		Value: value,
+	}
+}
+
+func NewNoneExpr() Expression {
+	return &NoneExpression{}
+}
+
 func NewIdentifierExpr(literal string) Expression {
 	return &IdentifierExpression{
 		Literal: literal,
 	}
 }
 
+func NewArrayExpr(elements ...Expression) Expression {
+	return &ArrayExpression{
+		Elements: elements,
+	}
+}
+
+func NewTupleExpr(elements ...Expression) Expression {
+	return &TupleExpression{
+		Elements: elements,
+	}
+}
+
+func NewStructExpr(name string, fields ...*StructFieldValue) Expression {
+	return &StructExpression{
+		Name: &IdentifierExpression{
+			Literal: name,
+		},
+		Fields: fields,
+	}
+}
+
+func NewStructFieldValue(name string, value Expression) *StructFieldValue {
+	return &StructFieldValue{
+		Identifier: &IdentifierExpression{
+			Literal: name,
+		},
+		Value: value,
+	}
+}
+
+func NewVariantExpr(enum, variant string, arguments ...Expression) Expression {
+	return &VariantExpression{
+		Enum: &IdentifierExpression{
+			Literal: enum,
+		},
+		Variant: &IdentifierExpression{
+			Literal: variant,
+		},
+		Arguments: arguments,
+	}
+}
+
+func NewMatchExpr(subject Expression, arms ...*MatchArm) Expression {
+	return &MatchExpression{
+		Subject: subject,
+		Arms:    arms,
+	}
+}
+
+// arm whose body is a single expression
+func NewMatchArm(variant string, bindings []string, body Expression) *MatchArm {
+	arm := &MatchArm{
+		Variant: &IdentifierExpression{
+			Literal: variant,
+		},
+		Bindings: []*IdentifierExpression{},
+		Body: &BlockStatement{
+			Statements: []Statement{&ExpressionStatement{Expr: body}},
+		},
+	}
+
+	for _, binding := range bindings {
+		arm.Bindings = append(arm.Bindings, &IdentifierExpression{Literal: binding})
+	}
+
+	return arm
+}
+
+func NewFieldExpr(left Expression, field string) Expression {
+	return &FieldExpression{
+		Left: left,
+		Field: &IdentifierExpression{
+			Literal: field,
+		},
+	}
+}
+
+// keys and values are given as alternating key, value pairs
+func NewMapExpr(pairs ...Expression) Expression {
+	expr := &MapExpression{
+		Keys:   []Expression{},
+		Values: []Expression{},
+	}
+	for i := 0; i+1 < len(pairs); i += 2 {
+		expr.Keys = append(expr.Keys, pairs[i])
+		expr.Values = append(expr.Values, pairs[i+1])
+	}
+
+	return expr
+}
+
+func NewIndexExpr(left, index Expression) Expression {
+	return &IndexExpression{
+		Left:  left,
+		Index: index,
+	}
+}
+
 func NewUnaryExpr(operator tokens.Token, expr Expression) Expression {
 	return &UnaryExpression{
 		Token: operator,
@@ -48,6 +170,14 @@ func NewUnaryExpr(operator tokens.Token, expr Expression) Expression {
 	}
 }
 
+func NewPostfixExpr(operator tokens.Token, expr Expression) Expression {
+	return &UnaryExpression{
+		Token:     operator,
+		Expr:      expr,
+		IsPostfix: true,
+	}
+}
+
 func NewBinaryExpr(operator tokens.Token, left, right Expression) Expression {
 	return &BinaryExpression{
 		Operator: operator,
@@ -56,6 +186,12 @@ func NewBinaryExpr(operator tokens.Token, left, right Expression) Expression {
 	}
 }
 
+func NewTryExpr(expr Expression) Expression {
+	return &TryExpression{
+		Expr: expr,
+	}
+}
+
 func NewGroupedExpr(expr Expression) Expression {
 	return &GroupedExpression{
 		Expr: expr,
@@ -90,3 +226,299 @@ func NewIfExpr(condition Expression, consequence []Statement, alternative []Stat
 
 	return expr
 }
+
+func NewIfLetExpr(binding string, optional Expression, consequence []Statement, alternative []Statement) Expression {
+	expr := NewIfExpr(optional, consequence, alternative).(*IfExpression)
+	expr.Binding = &IdentifierExpression{
+		Literal: binding,
+	}
+
+	return expr
+}
+
+func NewResultTypeAnnotation(value, err TypeAnnotation) TypeAnnotation {
+	return &ResultTypeAnnotation{
+		Value: value,
+		Error: err,
+	}
+}
+
+func NewOptionalTypeAnnotation(inner TypeAnnotation) TypeAnnotation {
+	return &OptionalTypeAnnotation{
+		Inner: inner,
+	}
+}
+
+func NewNamedTypeAnnotation(name string) TypeAnnotation {
+	return &NamedTypeAnnotation{
+		Name: name,
+	}
+}
+
+func NewArrayTypeAnnotation(element TypeAnnotation, size int64) TypeAnnotation {
+	return &ArrayTypeAnnotation{
+		Element: element,
+		Size:    size,
+	}
+}
+
+func NewMapTypeAnnotation(key, value TypeAnnotation) TypeAnnotation {
+	return &MapTypeAnnotation{
+		Key:   key,
+		Value: value,
+	}
+}
+
+func NewTupleTypeAnnotation(elements ...TypeAnnotation) TypeAnnotation {
+	return &TupleTypeAnnotation{
+		Elements: elements,
+	}
+}
+
+func NewListTypeAnnotation(element TypeAnnotation) TypeAnnotation {
+	return &ListTypeAnnotation{
+		Element: element,
+	}
+}
+
+func NewFunctionTypeAnnotation(params []TypeAnnotation, returnAnnotation TypeAnnotation) TypeAnnotation {
+	return &FunctionTypeAnnotation{
+		Params: params,
+		Return: returnAnnotation,
+	}
+}
+
+func NewFunctionParam(name string, annotation TypeAnnotation) *FunctionParameter {
+	return &FunctionParameter{
+		Identifier: &IdentifierExpression{
+			Literal: name,
+		},
+		Annotation: annotation,
+	}
+}
+
+func NewFunctionStmt(name string, params []*FunctionParameter, returnAnnotation TypeAnnotation, body []Statement) Statement {
+	return &FunctionStatement{
+		Identifier: &IdentifierExpression{
+			Literal: name,
+		},
+		Function: &FunctionExpression{
+			Parameters:       params,
+			ReturnAnnotation: returnAnnotation,
+			Body: &BlockStatement{
+				Statements: body,
+			},
+		},
+	}
+}
+
+func NewGenericFunctionStmt(name string, typeParams []*TypeParameter, params []*FunctionParameter, returnAnnotation TypeAnnotation, body []Statement) Statement {
+	stmt := NewFunctionStmt(name, params, returnAnnotation, body).(*FunctionStatement)
+	stmt.TypeParameters = typeParams
+	return stmt
+}
+
+func NewTypeParam(name string, constraint string) *TypeParameter {
+	typeParam := &TypeParameter{
+		Identifier: &IdentifierExpression{
+			Literal: name,
+		},
+	}
+
+	if constraint != "" {
+		typeParam.Constraint = &IdentifierExpression{
+			Literal: constraint,
+		}
+	}
+
+	return typeParam
+}
+
+func NewFunctionExpr(params []*FunctionParameter, returnAnnotation TypeAnnotation, body []Statement) Expression {
+	return &FunctionExpression{
+		Parameters:       params,
+		ReturnAnnotation: returnAnnotation,
+		Body: &BlockStatement{
+			Statements: body,
+		},
+	}
+}
+
+// named functions and builtins are called through their identifier, anything else through the callee
+func NewCallExpr(callee Expression, arguments ...Expression) Expression {
+	expr := &CallExpression{
+		Arguments: arguments,
+	}
+
+	if identifier, ok := callee.(*IdentifierExpression); ok {
+		expr.Identifier = identifier
+	} else {
+		expr.Callee = callee
+	}
+
+	return expr
+}
+
+func NewReturnStmt(expr Expression) Statement {
+	return &ReturnStatement{
+		Expr: expr,
+	}
+}
+
+func NewLetStmt(name string, annotation TypeAnnotation, value Expression) Statement {
+	return &LetStatement{
+		Identifier: &IdentifierExpression{
+			Literal: name,
+		},
+		Annotation: annotation,
+		Value:      value,
+	}
+}
+
+func NewDestructuringStmt(names []string, annotation TypeAnnotation, value Expression) Statement {
+	identifiers := []*IdentifierExpression{}
+	for _, name := range names {
+		identifiers = append(identifiers, &IdentifierExpression{
+			Literal: name,
+		})
+	}
+
+	return &DestructuringStatement{
+		Identifiers: identifiers,
+		Annotation:  annotation,
+		Value:       value,
+	}
+}
+
+func NewAssignmentStmt(name string, value Expression) Statement {
+	return &AssignmentStatement{
+		Identifier: &IdentifierExpression{
+			Literal: name,
+		},
+		Value: value,
+	}
+}
+
+func NewCompoundAssignmentStmt(name string, operator tokens.Token, value Expression) Statement {
+	return &AssignmentStatement{
+		Identifier: &IdentifierExpression{
+			Literal: name,
+		},
+		Operator: operator,
+		Value:    value,
+	}
+}
+
+func NewIndexAssignmentStmt(target Expression, operator tokens.Token, value Expression) Statement {
+	return &IndexAssignmentStatement{
+		Target:   target.(*IndexExpression),
+		Operator: operator,
+		Value:    value,
+	}
+}
+
+func NewFieldAssignmentStmt(target Expression, operator tokens.Token, value Expression) Statement {
+	return &FieldAssignmentStatement{
+		Target:   target.(*FieldExpression),
+		Operator: operator,
+		Value:    value,
+	}
+}
+
+func NewEnumStmt(name string, variants ...*EnumVariant) Statement {
+	return &EnumStatement{
+		Identifier: &IdentifierExpression{
+			Literal: name,
+		},
+		Variants: variants,
+	}
+}
+
+func NewEnumVariant(name string, payload ...TypeAnnotation) *EnumVariant {
+	return &EnumVariant{
+		Identifier: &IdentifierExpression{
+			Literal: name,
+		},
+		Payload: payload,
+	}
+}
+
+func NewStructStmt(name string, fields ...*StructField) Statement {
+	return &StructStatement{
+		Identifier: &IdentifierExpression{
+			Literal: name,
+		},
+		Fields: fields,
+	}
+}
+
+func NewStructField(name string, annotation TypeAnnotation) *StructField {
+	return &StructField{
+		Identifier: &IdentifierExpression{
+			Literal: name,
+		},
+		Annotation: annotation,
+	}
+}
+
+func NewForInStmt(label *IdentifierExpression, name string, iterable Expression, body []Statement) Statement {
+	return &ForInStatement{
+		Label: label,
+		Identifier: &IdentifierExpression{
+			Literal: name,
+		},
+		Iterable: iterable,
+		Body: &BlockStatement{
+			Statements: body,
+		},
+	}
+}
+
+func NewForStmt(initialization Statement, condition Expression, update Statement, body []Statement) Statement {
+	return &ForStatement{
+		Initialization: initialization,
+		Condition:      condition,
+		Update:         update,
+		Body: &BlockStatement{
+			Statements: body,
+		},
+	}
+}
+
+func NewLabel(name string) *IdentifierExpression {
+	return &IdentifierExpression{
+		Literal: name,
+	}
+}
+
+func NewWhileStmt(label *IdentifierExpression, condition Expression, body []Statement) Statement {
+	return &WhileStatement{
+		Label:     label,
+		Condition: condition,
+		Body: &BlockStatement{
+			Statements: body,
+		},
+	}
+}
+
+func NewBreakStmt(label *IdentifierExpression) Statement {
+	return &BreakStatement{
+		Label: label,
+	}
+}
+
+func NewContinueStmt(label *IdentifierExpression) Statement {
+	return &ContinueStatement{
+		Label: label,
+	}
+}
+
+func NewConstStmt(name string, annotation TypeAnnotation, value Expression) Statement {
+	return &ConstStatement{
+		Identifier: &IdentifierExpression{
+			Literal: name,
+		},
+		Annotation: annotation,
+		Value:      value,
+	}
+}
@@ -0,0 +1,39 @@
+package ast
+
+import "reflect"
+
+var nodeInterface = reflect.TypeFor[Node]()
+
+// calls visit for the node and every node nested within it, parents before their children
+func Walk(node Node, visit func(Node)) {
+	walkValue(reflect.ValueOf(node), visit)
+}
+
+func walkValue(v reflect.Value, visit func(Node)) {
+	switch v.Kind() {
+	case reflect.Pointer:
+		if v.IsNil() {
+			return
+		}
+
+		if v.Type().Implements(nodeInterface) {
+			visit(v.Interface().(Node))
+		}
+
+		walkValue(v.Elem(), visit)
+	case reflect.Interface:
+		if v.IsNil() || v.Type() == typeInterface {
+			return
+		}
+
+		walkValue(v.Elem(), visit)
+	case reflect.Struct:
+		for i := range v.NumField() {
+			walkValue(v.Field(i), visit)
+		}
+	case reflect.Slice:
+		for i := range v.Len() {
+			walkValue(v.Index(i), visit)
+		}
+	}
+}
@@ -1,29 +1,622 @@
 package codegen
 
 import (
+	"fmt"
+	"slices"
+
+	"github.com/0xmukesh/coco/internal/ast"
 	cotypes "github.com/0xmukesh/coco/internal/types"
 	"github.com/llir/llvm/ir"
 	"github.com/llir/llvm/ir/constant"
 	"github.com/llir/llvm/ir/enum"
 	"github.com/llir/llvm/ir/types"
+	"github.com/llir/llvm/ir/value"
 )
 
 var TRUE_GLOBAL_DEF_NAME = "__coco_true"
 var FALSE_GLOBAL_DEF_NAME = "__coco_false"
+var FUNCTION_NAME_PREFIX = "__coco_fn_"
+var GLOBAL_NAME_PREFIX = "__coco_global_"
+var IPOW_FUNC_NAME = "__coco_ipow"
+var STRING_TYPE_NAME = "__coco_string"
+var STRING_CONCAT_FUNC_NAME = "__coco_string_concat"
+var STRING_COMPARE_FUNC_NAME = "__coco_string_compare"
+var STRING_CHAR_AT_FUNC_NAME = "__coco_string_char_at"
+var STRING_NEXT_CHAR_FUNC_NAME = "__coco_string_next_char"
+var CHAR_ENCODE_FUNC_NAME = "__coco_char_encode"
+var STRING_FORMAT_FUNC_NAME = "__coco_string_format"
+var INDEX_OUT_OF_BOUNDS_FUNC_NAME = "__coco_index_out_of_bounds"
+var LIST_TYPE_NAME = "__coco_list"
+var LIST_NEW_FUNC_NAME = "__coco_list_new"
+var LIST_PUSH_FUNC_NAME = "__coco_list_push"
+var LIST_POP_FUNC_NAME = "__coco_list_pop"
+var MAP_TYPE_NAME = "__coco_map"
+var MAP_NEW_FUNC_NAME = "__coco_map_new"
+var MAP_FIND_FUNC_NAME = "__coco_map_find"
+var MAP_GET_FUNC_NAME = "__coco_map_get"
+var MAP_INSERT_FUNC_NAME = "__coco_map_insert"
+var MAP_DELETE_FUNC_NAME = "__coco_map_delete"
+var STRUCT_TYPE_NAME_PREFIX = "struct."
+var ENUM_TYPE_NAME_PREFIX = "enum."
+var CLOSURE_NAME_PREFIX = "__coco_closure_"
+var FUNCTION_VALUE_NAME_SUFFIX = ".value"
+var NIL_FUNCTION_CALL_FUNC_NAME = "__coco_nil_function_call"
+var ZERO_TO_NEGATIVE_POWER_FUNC_NAME = "__coco_zero_to_negative_power"
 
 func (cg *Codegen) typeToLlvm(t cotypes.Type) (types.Type, error) {
-	switch t.(type) {
+	switch t := t.(type) {
 	case cotypes.IntType:
 		return types.I64, nil
 	case cotypes.FloatType:
 		return types.Double, nil
+	case cotypes.SizedIntType:
+		return integerType(t), nil
+	case cotypes.Float32Type:
+		return types.Float, nil
+	case cotypes.CharType:
+		return integerType(t), nil
 	case cotypes.BoolType:
 		return types.I1, nil
+	case cotypes.StringType:
+		return cg.getStringType(), nil
+	case cotypes.ArrayType:
+		elemType, err := cg.typeToLlvm(t.Element)
+		if err != nil {
+			return nil, err
+		}
+
+		return types.NewArray(uint64(t.Size), elemType), nil
+	case cotypes.ListType:
+		return types.NewPointer(cg.getListType()), nil
+	case cotypes.MapType:
+		return types.NewPointer(cg.getMapType()), nil
+	case *cotypes.StructType:
+		return cg.getStructType(t)
+	case *cotypes.EnumType:
+		return cg.getEnumType(t)
+	case cotypes.ResultType:
+		return cg.getEnumType(t.Enum())
+	case cotypes.TupleType:
+		elementTypes := []types.Type{}
+		for _, element := range t.Elements {
+			elementType, err := cg.typeToLlvm(element)
+			if err != nil {
+				return nil, err
+			}
+
+			elementTypes = append(elementTypes, elementType)
+		}
+
+		return types.NewStruct(elementTypes...), nil
+	case cotypes.FunctionType:
+		return cg.getClosureType(t)
+	case cotypes.OptionalType:
+		// whether the optional holds a value, followed by the value itself
+		innerType, err := cg.typeToLlvm(t.Inner)
+		if err != nil {
+			return nil, err
+		}
+
+		return types.NewStruct(types.I1, innerType), nil
+	case cotypes.VoidType:
+		return types.Void, nil
 	default:
 		return nil, cg.addError("unsupported type - %v", t)
 	}
 }
 
+// strings are represented as a pointer to their bytes and their length, the bytes are not null terminated
+func (cg *Codegen) getStringType() *types.StructType {
+	if cg.stringType == nil {
+		cg.stringType = types.NewStruct(types.NewPointer(types.I8), types.I64)
+		cg.module.NewTypeDef(STRING_TYPE_NAME, cg.stringType)
+	}
+
+	return cg.stringType
+}
+
+// lists are represented as a pointer to a header holding the pointer to their elements, length and capacity.
+// the header is shared by all element types, elements are accessed through a pointer of the element type
+func (cg *Codegen) getListType() *types.StructType {
+	if cg.listType == nil {
+		cg.listType = types.NewStruct(types.NewPointer(types.I8), types.I64, types.I64)
+		cg.module.NewTypeDef(LIST_TYPE_NAME, cg.listType)
+	}
+
+	return cg.listType
+}
+
+// maps are represented as a pointer to the header of a hash table managed by the runtime.
+// only the length, which is the first field, is read by the generated code
+func (cg *Codegen) getMapType() *types.StructType {
+	if cg.mapType == nil {
+		cg.mapType = types.NewStruct(
+			types.I64, types.I64, types.I64, types.I64, types.I64,
+			types.NewPointer(types.I8), types.NewPointer(types.I8), types.NewPointer(types.I8),
+		)
+		cg.module.NewTypeDef(MAP_TYPE_NAME, cg.mapType)
+	}
+
+	return cg.mapType
+}
+
+// structs are lowered to named llvm struct types, with the fields in the order they are declared in
+func (cg *Codegen) getStructType(t *cotypes.StructType) (*types.StructType, error) {
+	if structType, ok := cg.structTypes[t.Name]; ok {
+		return structType, nil
+	}
+
+	// the type is registered before its fields are lowered, so that fields can refer back to it through lists and maps
+	structType := types.NewStruct()
+	cg.module.NewTypeDef(STRUCT_TYPE_NAME_PREFIX+t.Name, structType)
+	cg.structTypes[t.Name] = structType
+
+	for _, field := range t.Fields {
+		fieldType, err := cg.typeToLlvm(field.Type)
+		if err != nil {
+			return nil, err
+		}
+
+		structType.Fields = append(structType.Fields, fieldType)
+	}
+
+	return structType, nil
+}
+
+// enums are lowered to a named llvm struct type holding the tag, which is the index of the variant, followed by
+// storage large enough for the payload of any of the variants. payloads are accessed through a pointer to
+// a struct of their types, see getPayloadType
+func (cg *Codegen) getEnumType(t *cotypes.EnumType) (*types.StructType, error) {
+	if enumType, ok := cg.enumTypes[t.Name]; ok {
+		return enumType, nil
+	}
+
+	enumType := types.NewStruct()
+	cg.module.NewTypeDef(ENUM_TYPE_NAME_PREFIX+t.Name, enumType)
+	cg.enumTypes[t.Name] = enumType
+
+	payloadSize := uint64(0)
+	for i := range t.Variants {
+		payloadType, err := cg.getPayloadType(t, i)
+		if err != nil {
+			return nil, err
+		}
+
+		payloadSize = max(payloadSize, storeSize(payloadType))
+	}
+
+	// payload storage is made up of i64s, so that it is aligned for any of the payload types
+	enumType.Fields = []types.Type{types.I64, types.NewArray((payloadSize+7)/8, types.I64)}
+	return enumType, nil
+}
+
+func (cg *Codegen) getPayloadType(t *cotypes.EnumType, idx int) (*types.StructType, error) {
+	payloadType := types.NewStruct()
+	for _, fieldType := range t.Variants[idx].Payload {
+		llvmType, err := cg.typeToLlvm(fieldType)
+		if err != nil {
+			return nil, err
+		}
+
+		payloadType.Fields = append(payloadType.Fields, llvmType)
+	}
+
+	return payloadType, nil
+}
+
+// size of the type in bytes as laid out in memory, needed at compile time to size the payload storage of enums
+func storeSize(t types.Type) uint64 {
+	size, _ := sizeAndAlignment(t)
+	return size
+}
+
+func sizeAndAlignment(t types.Type) (size uint64, align uint64) {
+	switch t := t.(type) {
+	case *types.IntType:
+		size = max((t.BitSize+7)/8, 1)
+		return size, size
+	case *types.FloatType:
+		if t.Kind == types.FloatKindFloat {
+			return 4, 4
+		}
+
+		return 8, 8
+	case *types.PointerType:
+		return 8, 8
+	case *types.ArrayType:
+		elemSize, elemAlign := sizeAndAlignment(t.ElemType)
+		return elemSize * t.Len, elemAlign
+	case *types.StructType:
+		align = 1
+		for _, field := range t.Fields {
+			fieldSize, fieldAlign := sizeAndAlignment(field)
+			size = (size+fieldAlign-1)/fieldAlign*fieldAlign + fieldSize
+			align = max(align, fieldAlign)
+		}
+
+		return (size + align - 1) / align * align, align
+	default:
+		return 0, 1
+	}
+}
+
+// function values are closures, made up of a pointer to the function and a pointer to its environment,
+// which holds the captured variables. the function takes the environment as its first parameter
+func (cg *Codegen) getClosureType(t cotypes.FunctionType) (*types.StructType, error) {
+	params := []types.Type{types.NewPointer(types.I8)}
+	for _, param := range t.Params {
+		llvmType, err := cg.typeToLlvm(param)
+		if err != nil {
+			return nil, err
+		}
+
+		params = append(params, llvmType)
+	}
+
+	returnType, err := cg.typeToLlvm(t.Return)
+	if err != nil {
+		return nil, err
+	}
+
+	return types.NewStruct(types.NewPointer(types.NewFunc(returnType, params...)), types.NewPointer(types.I8)), nil
+}
+
+// reports whether zero value of the type needs lists or maps to be allocated
+func containsHeapType(t cotypes.Type) bool {
+	switch t := t.(type) {
+	case cotypes.ListType, cotypes.MapType:
+		return true
+	case cotypes.ArrayType:
+		return containsHeapType(t.Element)
+	case *cotypes.StructType:
+		return slices.ContainsFunc(t.Fields, func(field cotypes.StructField) bool {
+			return containsHeapType(field.Type)
+		})
+	case *cotypes.EnumType:
+		// zero value of an enum is its first variant, with the zero values as payload
+		return len(t.Variants) > 0 && slices.ContainsFunc(t.Variants[0].Payload, containsHeapType)
+	case cotypes.ResultType:
+		return containsHeapType(t.Enum())
+	case cotypes.TupleType:
+		return slices.ContainsFunc(t.Elements, containsHeapType)
+	default:
+		return false
+	}
+}
+
+// llvm type of the integer type
+func integerType(t cotypes.Type) *types.IntType {
+	switch bits, _ := cotypes.IntegerLayout(t); bits {
+	case 8:
+		return types.I8
+	case 16:
+		return types.I16
+	case 32:
+		return types.I32
+	default:
+		return types.I64
+	}
+}
+
+// llvm type of the float type
+func floatType(t cotypes.Type) *types.FloatType {
+	if cotypes.FloatBits(t) == 32 {
+		return types.Float
+	}
+
+	return types.Double
+}
+
+// converts a value of a numeric type into another numeric type. integers are truncated, or extended according to
+// the signedness of the type they are converted from, and floats are rounded towards zero when converted to integers
+func (cg *Codegen) convertNumeric(val value.Value, from, to cotypes.Type) value.Value {
+	// chars are converted as the u32 holding their code point
+	codePoint := cotypes.SizedIntType{Bits: 32, Signed: false}
+	if from.Equals(cotypes.CharType{}) {
+		from = codePoint
+	}
+	if to.Equals(cotypes.CharType{}) {
+		to = codePoint
+	}
+
+	fromBits, fromSigned := cotypes.IntegerLayout(from)
+	toBits, toSigned := cotypes.IntegerLayout(to)
+
+	switch {
+	case cotypes.IsInteger(from) && cotypes.IsInteger(to):
+		switch {
+		case toBits < fromBits:
+			return cg.builder.NewTrunc(val, integerType(to))
+		case toBits > fromBits && fromSigned:
+			return cg.builder.NewSExt(val, integerType(to))
+		case toBits > fromBits:
+			return cg.builder.NewZExt(val, integerType(to))
+		}
+	case cotypes.IsInteger(from) && cotypes.IsFloat(to):
+		if fromSigned {
+			return cg.builder.NewSIToFP(val, floatType(to))
+		}
+
+		return cg.builder.NewUIToFP(val, floatType(to))
+	case cotypes.IsFloat(from) && cotypes.IsInteger(to):
+		if toSigned {
+			return cg.builder.NewFPToSI(val, integerType(to))
+		}
+
+		return cg.builder.NewFPToUI(val, integerType(to))
+	case cotypes.IsFloat(from) && cotypes.IsFloat(to):
+		switch {
+		case cotypes.FloatBits(to) < cotypes.FloatBits(from):
+			return cg.builder.NewFPTrunc(val, floatType(to))
+		case cotypes.FloatBits(to) > cotypes.FloatBits(from):
+			return cg.builder.NewFPExt(val, floatType(to))
+		}
+	}
+
+	return val
+}
+
+// returns the position of the struct field or tuple element accessed by the expression
+func fieldIndex(expr *ast.FieldExpression) int {
+	if tupleType, ok := expr.Left.GetType().(cotypes.TupleType); ok {
+		_, idx, _ := tupleType.Element(expr.Field.String())
+		return idx
+	}
+
+	_, idx, _ := expr.Left.GetType().(*cotypes.StructType).Field(expr.Field.String())
+	return idx
+}
+
+// size of the type in bytes, computed as the offset of the second element of an array starting at null
+func sizeOf(t types.Type) constant.Constant {
+	return constant.NewPtrToInt(
+		constant.NewGetElementPtr(t, constant.NewNull(types.NewPointer(t)), constant.NewInt(types.I32, 1)),
+		types.I64,
+	)
+}
+
+// string literals are stored in global defs, literals with the same value share the same global def
+func (cg *Codegen) getStringLiteral(value string) constant.Constant {
+	strGlobalDef, ok := cg.stringLiterals[value]
+	if !ok {
+		strGlobalDefName := fmt.Sprintf(".str.%d", cg.nameCounter)
+		strGlobalDef = cg.module.NewGlobalDef(strGlobalDefName, constant.NewCharArrayFromString(value))
+		strGlobalDef.Immutable = true
+		strGlobalDef.Linkage = enum.LinkagePrivate
+		strGlobalDef.UnnamedAddr = enum.UnnamedAddrUnnamedAddr
+
+		cg.stringLiterals[value] = strGlobalDef
+		cg.nameCounter++
+	}
+
+	strPtr := constant.NewGetElementPtr(
+		strGlobalDef.ContentType,
+		strGlobalDef,
+		constant.NewInt(types.I64, 0),
+		constant.NewInt(types.I64, 0),
+	)
+
+	return constant.NewStruct(cg.getStringType(), strPtr, constant.NewInt(types.I64, int64(len(value))))
+}
+
+func (cg *Codegen) setupStringConcatRuntimeFunc() *ir.Func {
+	concatFunc := cg.module.NewFunc(
+		STRING_CONCAT_FUNC_NAME,
+		types.NewPointer(types.I8),
+		ir.NewParam("a", types.NewPointer(types.I8)),
+		ir.NewParam("alen", types.I64),
+		ir.NewParam("b", types.NewPointer(types.I8)),
+		ir.NewParam("blen", types.I64),
+	)
+	cg.runtimeFuncs["string_concat"] = concatFunc
+
+	return concatFunc
+}
+
+func (cg *Codegen) setupStringCompareRuntimeFunc() *ir.Func {
+	compareFunc := cg.module.NewFunc(
+		STRING_COMPARE_FUNC_NAME,
+		types.I64,
+		ir.NewParam("a", types.NewPointer(types.I8)),
+		ir.NewParam("alen", types.I64),
+		ir.NewParam("b", types.NewPointer(types.I8)),
+		ir.NewParam("blen", types.I64),
+	)
+	cg.runtimeFuncs["string_compare"] = compareFunc
+
+	return compareFunc
+}
+
+func (cg *Codegen) setupStringFormatRuntimeFunc() *ir.Func {
+	formatFunc := cg.module.NewFunc(
+		STRING_FORMAT_FUNC_NAME,
+		types.NewPointer(types.I8),
+		ir.NewParam("len", types.NewPointer(types.I64)),
+		ir.NewParam("fmt", types.NewPointer(types.I8)),
+	)
+	formatFunc.Sig.Variadic = true
+	cg.runtimeFuncs["string_format"] = formatFunc
+
+	return formatFunc
+}
+
+func (cg *Codegen) setupStringCharAtRuntimeFunc() *ir.Func {
+	charAtFunc := cg.module.NewFunc(
+		STRING_CHAR_AT_FUNC_NAME,
+		types.I32,
+		ir.NewParam("s", types.NewPointer(types.I8)),
+		ir.NewParam("len", types.I64),
+		ir.NewParam("index", types.I64),
+	)
+	cg.runtimeFuncs["string_char_at"] = charAtFunc
+
+	return charAtFunc
+}
+
+func (cg *Codegen) setupStringNextCharRuntimeFunc() *ir.Func {
+	nextCharFunc := cg.module.NewFunc(
+		STRING_NEXT_CHAR_FUNC_NAME,
+		types.I32,
+		ir.NewParam("s", types.NewPointer(types.I8)),
+		ir.NewParam("len", types.I64),
+		ir.NewParam("index", types.NewPointer(types.I64)),
+	)
+	cg.runtimeFuncs["string_next_char"] = nextCharFunc
+
+	return nextCharFunc
+}
+
+func (cg *Codegen) setupCharEncodeRuntimeFunc() *ir.Func {
+	encodeFunc := cg.module.NewFunc(
+		CHAR_ENCODE_FUNC_NAME,
+		types.I64,
+		ir.NewParam("c", types.I32),
+		ir.NewParam("out", types.NewPointer(types.I8)),
+	)
+	cg.runtimeFuncs["char_encode"] = encodeFunc
+
+	return encodeFunc
+}
+
+func (cg *Codegen) setupIndexOutOfBoundsRuntimeFunc() *ir.Func {
+	oobFunc := cg.module.NewFunc(
+		INDEX_OUT_OF_BOUNDS_FUNC_NAME,
+		types.Void,
+		ir.NewParam("line", types.I64),
+		ir.NewParam("index", types.I64),
+		ir.NewParam("length", types.I64),
+	)
+	oobFunc.FuncAttrs = append(oobFunc.FuncAttrs, enum.FuncAttrNoReturn)
+	cg.runtimeFuncs["index_out_of_bounds"] = oobFunc
+
+	return oobFunc
+}
+
+func (cg *Codegen) setupListNewRuntimeFunc() *ir.Func {
+	listNewFunc := cg.module.NewFunc(
+		LIST_NEW_FUNC_NAME,
+		types.NewPointer(cg.getListType()),
+		ir.NewParam("elem_size", types.I64),
+		ir.NewParam("cap", types.I64),
+	)
+	cg.runtimeFuncs["list_new"] = listNewFunc
+
+	return listNewFunc
+}
+
+func (cg *Codegen) setupListPushRuntimeFunc() *ir.Func {
+	listPushFunc := cg.module.NewFunc(
+		LIST_PUSH_FUNC_NAME,
+		types.NewPointer(types.I8),
+		ir.NewParam("list", types.NewPointer(cg.getListType())),
+		ir.NewParam("elem_size", types.I64),
+	)
+	cg.runtimeFuncs["list_push"] = listPushFunc
+
+	return listPushFunc
+}
+
+func (cg *Codegen) setupListPopRuntimeFunc() *ir.Func {
+	listPopFunc := cg.module.NewFunc(
+		LIST_POP_FUNC_NAME,
+		types.NewPointer(types.I8),
+		ir.NewParam("list", types.NewPointer(cg.getListType())),
+		ir.NewParam("elem_size", types.I64),
+		ir.NewParam("line", types.I64),
+	)
+	cg.runtimeFuncs["list_pop"] = listPopFunc
+
+	return listPopFunc
+}
+
+func (cg *Codegen) setupMapNewRuntimeFunc() *ir.Func {
+	mapNewFunc := cg.module.NewFunc(
+		MAP_NEW_FUNC_NAME,
+		types.NewPointer(cg.getMapType()),
+		ir.NewParam("value_size", types.I64),
+		ir.NewParam("string_keys", types.I64),
+	)
+	cg.runtimeFuncs["map_new"] = mapNewFunc
+
+	return mapNewFunc
+}
+
+func (cg *Codegen) setupMapFindRuntimeFunc() *ir.Func {
+	mapFindFunc := cg.module.NewFunc(
+		MAP_FIND_FUNC_NAME,
+		types.NewPointer(types.I8),
+		ir.NewParam("map", types.NewPointer(cg.getMapType())),
+		ir.NewParam("key", types.I64),
+		ir.NewParam("key_ptr", types.NewPointer(types.I8)),
+	)
+	cg.runtimeFuncs["map_find"] = mapFindFunc
+
+	return mapFindFunc
+}
+
+func (cg *Codegen) setupMapGetRuntimeFunc() *ir.Func {
+	mapGetFunc := cg.module.NewFunc(
+		MAP_GET_FUNC_NAME,
+		types.NewPointer(types.I8),
+		ir.NewParam("map", types.NewPointer(cg.getMapType())),
+		ir.NewParam("key", types.I64),
+		ir.NewParam("key_ptr", types.NewPointer(types.I8)),
+		ir.NewParam("line", types.I64),
+	)
+	cg.runtimeFuncs["map_get"] = mapGetFunc
+
+	return mapGetFunc
+}
+
+func (cg *Codegen) setupMapInsertRuntimeFunc() *ir.Func {
+	mapInsertFunc := cg.module.NewFunc(
+		MAP_INSERT_FUNC_NAME,
+		types.NewPointer(types.I8),
+		ir.NewParam("map", types.NewPointer(cg.getMapType())),
+		ir.NewParam("key", types.I64),
+		ir.NewParam("key_ptr", types.NewPointer(types.I8)),
+	)
+	cg.runtimeFuncs["map_insert"] = mapInsertFunc
+
+	return mapInsertFunc
+}
+
+func (cg *Codegen) setupMapDeleteRuntimeFunc() *ir.Func {
+	mapDeleteFunc := cg.module.NewFunc(
+		MAP_DELETE_FUNC_NAME,
+		types.Void,
+		ir.NewParam("map", types.NewPointer(cg.getMapType())),
+		ir.NewParam("key", types.I64),
+		ir.NewParam("key_ptr", types.NewPointer(types.I8)),
+	)
+	cg.runtimeFuncs["map_delete"] = mapDeleteFunc
+
+	return mapDeleteFunc
+}
+
+func (cg *Codegen) setupNilFunctionCallRuntimeFunc() *ir.Func {
+	nilFunctionFunc := cg.module.NewFunc(NIL_FUNCTION_CALL_FUNC_NAME, types.Void, ir.NewParam("line", types.I64))
+	nilFunctionFunc.FuncAttrs = append(nilFunctionFunc.FuncAttrs, enum.FuncAttrNoReturn)
+	cg.runtimeFuncs["nil_function_call"] = nilFunctionFunc
+
+	return nilFunctionFunc
+}
+
+func (cg *Codegen) setupZeroToNegativePowerRuntimeFunc() *ir.Func {
+	zeroToNegativePowerFunc := cg.module.NewFunc(ZERO_TO_NEGATIVE_POWER_FUNC_NAME, types.Void, ir.NewParam("line", types.I64), ir.NewParam("exp", types.I64))
+	zeroToNegativePowerFunc.FuncAttrs = append(zeroToNegativePowerFunc.FuncAttrs, enum.FuncAttrNoReturn)
+	cg.runtimeFuncs["zero_to_negative_power"] = zeroToNegativePowerFunc
+
+	return zeroToNegativePowerFunc
+}
+
+func (cg *Codegen) setupMallocRuntimeFunc() *ir.Func {
+	mallocFunc := cg.module.NewFunc("malloc", types.NewPointer(types.I8), ir.NewParam("size", types.I64))
+	cg.runtimeFuncs["malloc"] = mallocFunc
+
+	return mallocFunc
+}
+
 func (cg *Codegen) setupPrintfRuntimeFunc() *ir.Func {
 	printfFunc := cg.module.NewFunc("printf", types.I32, ir.NewParam("fmt", types.NewPointer(types.I8)))
 	printfFunc.Sig.Variadic = true
@@ -32,6 +625,172 @@ func (cg *Codegen) setupPrintfRuntimeFunc() *ir.Func {
 	return printfFunc
 }
 
+func (cg *Codegen) setupExitRuntimeFunc() *ir.Func {
+	exitFunc := cg.module.NewFunc("exit", types.Void, ir.NewParam("status", types.I32))
+	cg.runtimeFuncs["exit"] = exitFunc
+
+	return exitFunc
+}
+
+func (cg *Codegen) setupPowRuntimeFunc() *ir.Func {
+	powFunc := cg.module.NewFunc("pow", types.Double, ir.NewParam("base", types.Double), ir.NewParam("exp", types.Double))
+	cg.runtimeFuncs["pow"] = powFunc
+
+	return powFunc
+}
+
+// integer exponentiation by squaring. x ** -n is computed as (1 / x) ** n with integer division,
+// so it is 0 unless x is 1 or -1. 0 ** -n would divide by zero, which is a runtime error reported at line
+func (cg *Codegen) setupIpowRuntimeFunc() *ir.Func {
+	base := ir.NewParam("base", types.I64)
+	exp := ir.NewParam("exp", types.I64)
+	line := ir.NewParam("line", types.I64)
+	ipowFunc := cg.module.NewFunc(IPOW_FUNC_NAME, types.I64, base, exp, line)
+	ipowFunc.Linkage = enum.LinkagePrivate
+	cg.runtimeFuncs["ipow"] = ipowFunc
+
+	zeroToNegativePowerFunc, ok := cg.runtimeFuncs["zero_to_negative_power"]
+	if !ok {
+		zeroToNegativePowerFunc = cg.setupZeroToNegativePowerRuntimeFunc()
+	}
+
+	entry := ipowFunc.NewBlock("entry")
+	checkBase := ipowFunc.NewBlock("check_base")
+	zeroBase := ipowFunc.NewBlock("zero_base")
+	invert := ipowFunc.NewBlock("invert")
+	loop := ipowFunc.NewBlock("loop")
+	body := ipowFunc.NewBlock("body")
+	exit := ipowFunc.NewBlock("exit")
+
+	one := constant.NewInt(types.I64, 1)
+	zero := constant.NewInt(types.I64, 0)
+
+	entry.NewCondBr(entry.NewICmp(enum.IPredSLT, exp, zero), checkBase, loop)
+
+	checkBase.NewCondBr(checkBase.NewICmp(enum.IPredEQ, base, zero), zeroBase, invert)
+
+	zeroBase.NewCall(zeroToNegativePowerFunc, line, exp)
+	zeroBase.NewUnreachable()
+
+	invertedBase := invert.NewSDiv(one, base)
+	negatedExp := invert.NewSub(zero, exp)
+	invert.NewBr(loop)
+
+	currBase := loop.NewPhi(ir.NewIncoming(base, entry), ir.NewIncoming(invertedBase, invert))
+	currExp := loop.NewPhi(ir.NewIncoming(exp, entry), ir.NewIncoming(negatedExp, invert))
+	result := loop.NewPhi(ir.NewIncoming(one, entry), ir.NewIncoming(one, invert))
+	loop.NewCondBr(loop.NewICmp(enum.IPredEQ, currExp, zero), exit, body)
+
+	// multiply the result by the current base if the lowest bit of exponent is set, then square the base
+	isOdd := body.NewICmp(enum.IPredNE, body.NewAnd(currExp, one), zero)
+	nextResult := body.NewSelect(isOdd, body.NewMul(result, currBase), result)
+	nextBase := body.NewMul(currBase, currBase)
+	nextExp := body.NewLShr(currExp, one)
+	body.NewBr(loop)
+
+	currBase.Incs = append(currBase.Incs, ir.NewIncoming(nextBase, body))
+	currExp.Incs = append(currExp.Incs, ir.NewIncoming(nextExp, body))
+	result.Incs = append(result.Incs, ir.NewIncoming(nextResult, body))
+
+	exit.NewRet(result)
+
+	return ipowFunc
+}
+
+// allocas are placed at the start of the entry block of the current function, so that
+// variables declared inside loops do not grow the stack on every iteration
+func (cg *Codegen) newEntryAlloca(elemType types.Type) *ir.InstAlloca {
+	alloca := ir.NewAlloca(elemType)
+	entry := cg.fn.Blocks[0]
+	entry.Insts = append([]ir.Instruction{alloca}, entry.Insts...)
+
+	return alloca
+}
+
+// returns the storage of a newly declared variable. variables which are captured by closures can outlive the
+// function which declares them, so they are stored on the heap instead of the stack
+func (cg *Codegen) newVariable(ident *ast.IdentifierExpression, llvmType types.Type) value.Value {
+	if !ident.Captured {
+		return cg.newEntryAlloca(llvmType)
+	}
+
+	return cg.builder.NewBitCast(cg.generateHeapAlloc(llvmType), types.NewPointer(llvmType))
+}
+
+// returns the storage of a variable declared by a let statement. top level variables are stored in the globals
+// declared up front, the others are declared like any other variable
+func (cg *Codegen) newLetVariable(ident *ast.IdentifierExpression, llvmType types.Type) value.Value {
+	if cg.fn == cg.mainFn && cg.scope.Parent() == nil {
+		if global, ok := cg.globals.Get(ident.Literal); ok {
+			return global.ptr
+		}
+	}
+
+	return cg.newVariable(ident, llvmType)
+}
+
+// declares a top level variable as a global, which holds the zero value of its type until the let statement runs.
+// functions can be called before that, as they are hoisted
+func (cg *Codegen) declareGlobal(ident *ast.IdentifierExpression) error {
+	llvmType, err := cg.typeToLlvm(ident.GetType())
+	if err != nil {
+		return cg.propagateOrWrapError(err, ident, "failed to retrieve llvm equivalent type: %s", err.Error())
+	}
+
+	global := cg.module.NewGlobalDef(GLOBAL_NAME_PREFIX+ident.Literal, constant.NewZeroInitializer(llvmType))
+
+	// zero values which are not constant, eg. empty lists, are created at the start of main
+	zeroValue, err := cg.generateZeroValue(ident.GetType())
+	if err != nil {
+		return cg.propagateOrWrapError(err, ident, "failed to generate zero value for global: %s", err.Error())
+	}
+
+	if _, ok := zeroValue.(constant.Constant); !ok {
+		cg.builder.NewStore(zeroValue, global)
+	}
+
+	cg.globals.Set(ident.Literal, ScopeItem{
+		ptr: global,
+		typ: ident.GetType(),
+	})
+	return nil
+}
+
+// returns the variable which the identifier refers to. optionals which are known to hold a value at the
+// identifier are accessed through a pointer to their value
+func (cg *Codegen) lookupVariable(ident *ast.IdentifierExpression) (ScopeItem, bool) {
+	variable, exists := cg.scope.Get(ident.Literal)
+	if !exists || !ident.Narrowed || variable.ptr == nil {
+		return variable, exists
+	}
+
+	optionalType := variable.typ.(cotypes.OptionalType)
+	ptr := cg.builder.NewGetElementPtr(variable.ptr.Type().(*types.PointerType).ElemType, variable.ptr, constant.NewInt(types.I32, 0), constant.NewInt(types.I32, 1))
+
+	return ScopeItem{ptr: ptr, typ: optionalType.Inner}, true
+}
+
+func (cg *Codegen) loadVariable(variable ScopeItem) value.Value {
+	return cg.builder.NewLoad(variable.ptr.Type().(*types.PointerType).ElemType, variable.ptr)
+}
+
+// allocates memory for a value of the type, which is never freed
+func (cg *Codegen) generateHeapAlloc(t types.Type) value.Value {
+	mallocFunc, ok := cg.runtimeFuncs["malloc"]
+	if !ok {
+		mallocFunc = cg.setupMallocRuntimeFunc()
+	}
+
+	return cg.builder.NewCall(mallocFunc, sizeOf(t))
+}
+
+// terminates the current block with a branch to target, unless it is already terminated (eg. by a return statement)
+func (cg *Codegen) branchTo(target *ir.Block) {
+	if cg.builder.Term == nil {
+		cg.builder.NewBr(target)
+	}
+}
+
 func (cg *Codegen) setupTrueGlobalDef() *ir.Global {
 	trueStr := cg.module.NewGlobalDef(TRUE_GLOBAL_DEF_NAME, constant.NewCharArrayFromString("true\x00"))
 	trueStr.Immutable = true
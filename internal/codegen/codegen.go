@@ -19,19 +19,41 @@ import (
 
 type Scope = *env.Environent[ScopeItem]
 type ScopeItem struct {
-	alloca *ir.InstAlloca
-	typ    cotypes.Type
+	// pointer to the memory holding the variable, which is either a stack slot, a heap cell for captured variables
+	// or a global for top level variables
+	ptr value.Value
+	typ cotypes.Type
+	// constants are not stored in memory, their value is used directly
+	constant constant.Constant
+}
+
+// branch targets of a loop which is currently being generated
+type loopInfo struct {
+	label          string
+	breakTarget    *ir.Block
+	continueTarget *ir.Block
 }
 
 type Codegen struct {
 	module  *ir.Module
 	mainFn  *ir.Func
+	fn      *ir.Func // function which is currently being generated
 	builder *ir.Block
-	ret     value.Value
 
-	scope        Scope
-	runtimeFuncs map[string]*ir.Func
-	globalDefs   map[string]*ir.Global
+	scope          Scope
+	globals        Scope // top level constants and variables, which are visible inside of function bodies as well
+	runtimeFuncs   map[string]*ir.Func
+	functions      map[string]*ir.Func
+	globalDefs     map[string]*ir.Global
+	stringLiterals map[string]*ir.Global
+	stringType     *types.StructType
+	listType       *types.StructType
+	mapType        *types.StructType
+	structTypes    map[string]*types.StructType
+	enumTypes      map[string]*types.StructType
+	// wrappers which take an environment, so that functions can be used as function values
+	functionValues map[string]*ir.Func
+	loops          []loopInfo // enclosing loops, innermost last
 
 	nameCounter int
 	errors      []error
@@ -43,13 +65,20 @@ func New() *Codegen {
 	builder := mainFn.NewBlock("")
 
 	cg := &Codegen{
-		module:       module,
-		mainFn:       mainFn,
-		builder:      builder,
-		scope:        env.NewEnvironment[ScopeItem](),
-		runtimeFuncs: make(map[string]*ir.Func),
-		globalDefs:   make(map[string]*ir.Global),
-		errors:       make([]error, 0),
+		module:         module,
+		mainFn:         mainFn,
+		fn:             mainFn,
+		builder:        builder,
+		scope:          env.NewEnvironment[ScopeItem](),
+		globals:        env.NewEnvironment[ScopeItem](),
+		runtimeFuncs:   make(map[string]*ir.Func),
+		functions:      make(map[string]*ir.Func),
+		globalDefs:     make(map[string]*ir.Global),
+		stringLiterals: make(map[string]*ir.Global),
+		structTypes:    make(map[string]*types.StructType),
+		enumTypes:      make(map[string]*types.StructType),
+		functionValues: make(map[string]*ir.Func),
+		errors:         make([]error, 0),
 	}
 
 	return cg
@@ -64,8 +93,36 @@ func (cg *Codegen) generateStatement(stmt ast.Statement) error {
 		}
 	case *ast.LetStatement:
 		return cg.generateLetStatement(s)
+	case *ast.DestructuringStatement:
+		return cg.generateDestructuringStatement(s)
+	case *ast.ConstStatement:
+		return cg.generateConstStatement(s)
 	case *ast.AssignmentStatement:
 		return cg.generateAssignmentStatement(s)
+	case *ast.IndexAssignmentStatement:
+		return cg.generateIndexAssignmentStatement(s)
+	case *ast.FieldAssignmentStatement:
+		return cg.generateFieldAssignmentStatement(s)
+	case *ast.StructStatement:
+		// struct types are emitted once they are used, see getStructType
+		return nil
+	case *ast.EnumStatement:
+		// same goes for enum types, see getEnumType
+		return nil
+	case *ast.FunctionStatement:
+		return cg.generateFunctionStatement(s)
+	case *ast.ReturnStatement:
+		return cg.generateReturnStatement(s)
+	case *ast.WhileStatement:
+		return cg.generateWhileStatement(s)
+	case *ast.ForStatement:
+		return cg.generateForStatement(s)
+	case *ast.ForInStatement:
+		return cg.generateForInStatement(s)
+	case *ast.BreakStatement:
+		return cg.generateLoopControl(s, s.Label, true)
+	case *ast.ContinueStatement:
+		return cg.generateLoopControl(s, s.Label, false)
 	case *ast.BlockStatement:
 		previousScope := cg.scope
 		cg.scope = env.NewEnvironmentWithParent(previousScope)
@@ -90,27 +147,159 @@ func (cg *Codegen) generateExpression(expr ast.Expression) (value.Value, error)
 
 	switch e := expr.(type) {
 	case *ast.IntegerExpression:
-		return constant.NewInt(types.I64, e.Value), nil
+		return constant.NewInt(integerType(e.GetType()), e.Value), nil
 	case *ast.FloatExpression:
+		// f32 constants must be exactly representable as f32
+		if cotypes.FloatBits(e.GetType()) == 32 {
+			return constant.NewFloat(types.Float, float64(float32(e.Value))), nil
+		}
+
 		return constant.NewFloat(types.Double, e.Value), nil
+	case *ast.CharExpression:
+		return constant.NewInt(types.I32, int64(e.Value)), nil
 	case *ast.BooleanExpression:
 		return constant.NewBool(e.Value), nil
+	case *ast.NoneExpression:
+		return cg.generateZeroValue(e.GetType())
+	case *ast.StringExpression:
+		// string literals keep their surrounding quotes
+		return cg.getStringLiteral(e.Value[1 : len(e.Value)-1]), nil
+	case *ast.InterpolationExpression:
+		return cg.generateInterpolationExpression(e)
 	case *ast.IdentifierExpression:
 		return cg.generateIdentifier(e)
+	case *ast.UnaryExpression:
+		return cg.generateUnaryExpression(e)
+	case *ast.TryExpression:
+		return cg.generateTryExpression(e)
 	case *ast.BinaryExpression:
 		return cg.generateBinaryExpression(e)
 	case *ast.CallExpression:
 		return cg.generateCallExpression(e)
 	case *ast.GroupedExpression:
 		return cg.generateExpression(e.Expr)
+	case *ast.CastExpression:
+		return cg.generateCastExpression(e)
 	case *ast.IfExpression:
 		return cg.generateIfExpression(e)
+	case *ast.ArrayExpression:
+		return cg.generateArrayExpression(e)
+	case *ast.TupleExpression:
+		return cg.generateTupleExpression(e)
+	case *ast.IndexExpression:
+		return cg.generateIndexExpression(e)
+	case *ast.MapExpression:
+		return cg.generateMapExpression(e)
+	case *ast.StructExpression:
+		return cg.generateStructExpression(e)
+	case *ast.FieldExpression:
+		return cg.generateFieldExpression(e)
+	case *ast.VariantExpression:
+		return cg.generateVariantExpression(e)
+	case *ast.MatchExpression:
+		return cg.generateMatchExpression(e)
+	case *ast.FunctionExpression:
+		return cg.generateFunctionExpression(e)
 	default:
 		return nil, cg.addErrorAtNode(expr, "unsupported expression type")
 	}
 }
 
+func (cg *Codegen) generateUnaryExpression(expr *ast.UnaryExpression) (value.Value, error) {
+	if expr.Token.Type == tokens.INCREMENT || expr.Token.Type == tokens.DECREMENT {
+		return cg.generateIncDecExpression(expr)
+	}
+
+	operand, err := cg.generateExpression(expr.Expr)
+	if err != nil {
+		return nil, cg.propagateOrWrapError(err, expr, "failed to generate operand: %s", err.Error())
+	}
+
+	switch expr.Token.Type {
+	case tokens.MINUS:
+		if cotypes.IsFloat(expr.GetType()) {
+			return cg.builder.NewFNeg(operand), nil
+		}
+
+		return cg.builder.NewSub(constant.NewInt(integerType(expr.GetType()), 0), operand), nil
+	case tokens.BANG:
+		return cg.builder.NewXor(operand, constant.True), nil
+	default:
+		return nil, cg.addErrorAtNode(expr, "cannot perform %s operation", expr.Token.Type)
+	}
+}
+
+// ++x and --x evaluate to the updated value, x++ and x-- to the value before the update
+func (cg *Codegen) generateIncDecExpression(expr *ast.UnaryExpression) (value.Value, error) {
+	ident, ok := expr.Expr.(*ast.IdentifierExpression)
+	if !ok {
+		return nil, cg.addErrorAtNode(expr, "operand of %s operation must be a variable", expr.Token.Type)
+	}
+
+	variable, exists := cg.lookupVariable(ident)
+	if !exists || variable.ptr == nil {
+		return nil, cg.addErrorAtNode(expr, "cannot assign to %q", ident.Literal)
+	}
+
+	oldValue := cg.loadVariable(variable)
+
+	var newValue value.Value
+	if cotypes.IsFloat(variable.typ) {
+		step := constant.NewFloat(floatType(variable.typ), 1)
+		if expr.Token.Type == tokens.INCREMENT {
+			newValue = cg.builder.NewFAdd(oldValue, step)
+		} else {
+			newValue = cg.builder.NewFSub(oldValue, step)
+		}
+	} else {
+		step := constant.NewInt(integerType(variable.typ), 1)
+		if expr.Token.Type == tokens.INCREMENT {
+			newValue = cg.builder.NewAdd(oldValue, step)
+		} else {
+			newValue = cg.builder.NewSub(oldValue, step)
+		}
+	}
+
+	cg.builder.NewStore(newValue, variable.ptr)
+
+	if expr.IsPostfix {
+		return oldValue, nil
+	}
+
+	return newValue, nil
+}
+
 func (cg *Codegen) generateBinaryExpression(expr *ast.BinaryExpression) (value.Value, error) {
+	if expr.Operator.Type == tokens.AND || expr.Operator.Type == tokens.OR {
+		return cg.generateLogicalExpression(expr)
+	}
+
+	if expr.Operator.Type == tokens.DOUBLE_QUESTION {
+		return cg.generateCoalesceExpression(expr)
+	}
+
+	// comparisons against none only look at whether the optional holds a value
+	_, isLeftNone := expr.Left.(*ast.NoneExpression)
+	_, isRightNone := expr.Right.(*ast.NoneExpression)
+	if isLeftNone || isRightNone {
+		optionalExpr := expr.Left
+		if isLeftNone {
+			optionalExpr = expr.Right
+		}
+
+		optional, err := cg.generateExpression(optionalExpr)
+		if err != nil {
+			return nil, cg.propagateOrWrapError(err, expr, "failed to generate operand: %s", err.Error())
+		}
+
+		predicate := enum.IPredEQ
+		if expr.Operator.Type == tokens.NOT_EQUALS {
+			predicate = enum.IPredNE
+		}
+
+		return cg.builder.NewICmp(predicate, cg.builder.NewExtractValue(optional, 0), constant.False), nil
+	}
+
 	left, err := cg.generateExpression(expr.Left)
 	if err != nil {
 		return nil, cg.propagateOrWrapError(err, expr, "failed to generate left operand: %s", err.Error())
@@ -121,49 +310,36 @@ func (cg *Codegen) generateBinaryExpression(expr *ast.BinaryExpression) (value.V
 		return nil, cg.propagateOrWrapError(err, expr, "failed to generate right operand: %s", err.Error())
 	}
 
-	// integer arithmetic
-	if expr.GetType().Equals(cotypes.IntType{}) {
-		switch expr.Operator.Type {
-		case tokens.PLUS:
-			return cg.builder.NewAdd(left, right), nil
-		case tokens.MINUS:
-			return cg.builder.NewSub(left, right), nil
-		case tokens.STAR:
-			return cg.builder.NewMul(left, right), nil
-		case tokens.SLASH:
-			return cg.builder.NewSDiv(left, right), nil
-		default:
-			return nil, cg.addErrorAtNode(expr, "cannot perform %s operation", expr.Operator.Type)
-		}
+	// arithmetic
+	if cotypes.GetTypeCategory(expr.GetType()) == cotypes.CategoryNumeric {
+		return cg.generateArithmetic(expr, expr.Operator.Type, expr.Operator.Line, expr.GetType(), left, right)
 	}
 
-	// float arithmetic
-	if expr.GetType().Equals(cotypes.FloatType{}) {
-		switch expr.Operator.Type {
-		case tokens.PLUS:
-			return cg.builder.NewFAdd(left, right), nil
-		case tokens.MINUS:
-			return cg.builder.NewFSub(left, right), nil
-		case tokens.STAR:
-			return cg.builder.NewMul(left, right), nil
-		case tokens.SLASH:
-			return cg.builder.NewFDiv(left, right), nil
-		default:
-			return nil, cg.addErrorAtNode(expr, "cannot perform %s operation", expr.Operator.Type)
+	// strings
+	if expr.Left.GetType().Equals(cotypes.StringType{}) && expr.Right.GetType().Equals(cotypes.StringType{}) {
+		if expr.Operator.Type == tokens.PLUS {
+			return cg.generateStringConcat(left, right), nil
 		}
+
+		return cg.generateStringComparison(expr, left, right)
 	}
 
-	// integer comparison
-	if left.Type().Equal(types.I64) && right.Type().Equal(types.I64) && expr.GetType().Equals(cotypes.BoolType{}) {
+	// integer and char comparison, unsigned integers and chars are compared with the unsigned predicates
+	if (cotypes.IsInteger(expr.Left.GetType()) || expr.Left.GetType().Equals(cotypes.CharType{})) && expr.GetType().Equals(cotypes.BoolType{}) {
+		lessThan, greaterThan, lessThanEquals, greaterThanEquals := enum.IPredSLT, enum.IPredSGT, enum.IPredSLE, enum.IPredSGE
+		if _, signed := cotypes.IntegerLayout(expr.Left.GetType()); !signed {
+			lessThan, greaterThan, lessThanEquals, greaterThanEquals = enum.IPredULT, enum.IPredUGT, enum.IPredULE, enum.IPredUGE
+		}
+
 		switch expr.Operator.Type {
 		case tokens.LESS_THAN:
-			return cg.builder.NewICmp(enum.IPredSLT, left, right), nil
+			return cg.builder.NewICmp(lessThan, left, right), nil
 		case tokens.GREATER_THAN:
-			return cg.builder.NewICmp(enum.IPredSGT, left, right), nil
+			return cg.builder.NewICmp(greaterThan, left, right), nil
 		case tokens.LESS_THAN_EQUALS:
-			return cg.builder.NewICmp(enum.IPredSLE, left, right), nil
+			return cg.builder.NewICmp(lessThanEquals, left, right), nil
 		case tokens.GREATER_THAN_EQUALS:
-			return cg.builder.NewICmp(enum.IPredSGE, left, right), nil
+			return cg.builder.NewICmp(greaterThanEquals, left, right), nil
 		case tokens.EQUALS:
 			return cg.builder.NewICmp(enum.IPredEQ, left, right), nil
 		case tokens.NOT_EQUALS:
@@ -173,8 +349,18 @@ func (cg *Codegen) generateBinaryExpression(expr *ast.BinaryExpression) (value.V
 		}
 	}
 
+	// bool equality
+	if left.Type().Equal(types.I1) && right.Type().Equal(types.I1) {
+		switch expr.Operator.Type {
+		case tokens.EQUALS:
+			return cg.builder.NewICmp(enum.IPredEQ, left, right), nil
+		case tokens.NOT_EQUALS:
+			return cg.builder.NewICmp(enum.IPredNE, left, right), nil
+		}
+	}
+
 	// float comparison
-	if left.Type().Equal(types.Double) && right.Type().Equal(types.Double) && expr.GetType().Equals(cotypes.BoolType{}) {
+	if cotypes.IsFloat(expr.Left.GetType()) && expr.GetType().Equals(cotypes.BoolType{}) {
 		switch expr.Operator.Type {
 		case tokens.LESS_THAN:
 			return cg.builder.NewFCmp(enum.FPredOLT, left, right), nil
@@ -196,275 +382,2238 @@ func (cg *Codegen) generateBinaryExpression(expr *ast.BinaryExpression) (value.V
 	return nil, cg.addErrorAtNode(expr, "cannot perform %s operation", expr.Operator.Type)
 }
 
-func (cg *Codegen) generateIdentifier(expr *ast.IdentifierExpression) (value.Value, error) {
-	variable, exists := cg.scope.Get(expr.Literal)
-	if !exists {
-		return nil, cg.addErrorAtNode(expr, "undefined variable %q", expr.Literal)
-	}
+// applies an arithmetic operator to two operands which are both of the given numeric type. line is the line of the
+// operator in the source, which runtime errors are reported at
+func (cg *Codegen) generateArithmetic(node ast.Node, op tokens.TokenType, line int, typ cotypes.Type, left, right value.Value) (value.Value, error) {
+	// integer arithmetic, division and remainder depend on the signedness of the operands
+	if cotypes.IsInteger(typ) {
+		_, signed := cotypes.IntegerLayout(typ)
 
-	return cg.builder.NewLoad(variable.alloca.ElemType, variable.alloca), nil
-}
+		switch op {
+		case tokens.PLUS:
+			return cg.builder.NewAdd(left, right), nil
+		case tokens.MINUS:
+			return cg.builder.NewSub(left, right), nil
+		case tokens.STAR:
+			return cg.builder.NewMul(left, right), nil
+		case tokens.SLASH:
+			if !signed {
+				return cg.builder.NewUDiv(left, right), nil
+			}
 
-func (cg *Codegen) generateCallExpression(expr *ast.CallExpression) (value.Value, error) {
-	if !expr.IsBuiltin {
-		return nil, cg.addErrorAtNode(expr, "cannot call %q identifier", expr.Identifier.String())
-	}
+			return cg.builder.NewSDiv(left, right), nil
+		case tokens.MODULO:
+			if !signed {
+				return cg.builder.NewURem(left, right), nil
+			}
 
-	if expr.IsBuiltin && expr.BuiltinKind == nil {
-		return nil, cg.addErrorAtNode(expr, "function %q is marked as builtin but missing builtin kind", expr.Identifier.String())
+			return cg.builder.NewSRem(left, right), nil
+		case tokens.DOUBLE_STAR:
+			ipowFunc, ok := cg.runtimeFuncs["ipow"]
+			if !ok {
+				ipowFunc = cg.setupIpowRuntimeFunc()
+			}
+
+			// the runtime works on int, other integer types are extended to it and the result is truncated back
+			base, exp := cg.convertNumeric(left, typ, cotypes.IntType{}), cg.convertNumeric(right, typ, cotypes.IntType{})
+			result := cg.builder.NewCall(ipowFunc, base, exp, constant.NewInt(types.I64, int64(line)))
+			return cg.convertNumeric(result, cotypes.IntType{}, typ), nil
+		}
 	}
 
-	switch *expr.BuiltinKind {
-	case ast.BuiltinFuncPrint:
-		return cg.generatePrintExpression(expr)
-	case ast.BuiltinFuncExit:
-		return cg.generateExitExpression(expr)
-	case ast.BuiltinFuncInt:
-		return cg.generateIntExpression(expr)
-	case ast.BuiltinFuncFloat:
-		return cg.generateFloatExpression(expr)
-	default:
-		return nil, cg.addErrorAtNode(expr, "unsupported builtin function %q", expr.Identifier.String())
+	// float arithmetic
+	if cotypes.IsFloat(typ) {
+		switch op {
+		case tokens.PLUS:
+			return cg.builder.NewFAdd(left, right), nil
+		case tokens.MINUS:
+			return cg.builder.NewFSub(left, right), nil
+		case tokens.STAR:
+			return cg.builder.NewFMul(left, right), nil
+		case tokens.SLASH:
+			return cg.builder.NewFDiv(left, right), nil
+		case tokens.MODULO:
+			return cg.builder.NewFRem(left, right), nil
+		case tokens.DOUBLE_STAR:
+			powFunc, ok := cg.runtimeFuncs["pow"]
+			if !ok {
+				powFunc = cg.setupPowRuntimeFunc()
+			}
+
+			base, exp := cg.convertNumeric(left, typ, cotypes.FloatType{}), cg.convertNumeric(right, typ, cotypes.FloatType{})
+			return cg.convertNumeric(cg.builder.NewCall(powFunc, base, exp), cotypes.FloatType{}, typ), nil
+		}
 	}
+
+	return nil, cg.addErrorAtNode(node, "cannot perform %s operation", op)
 }
 
-func (cg *Codegen) generatePrintExpression(expr *ast.CallExpression) (value.Value, error) {
-	funcName := expr.Identifier.String()
-	printfFunc, ok := cg.runtimeFuncs[funcName]
+func (cg *Codegen) generateStringConcat(left, right value.Value) value.Value {
+	concatFunc, ok := cg.runtimeFuncs["string_concat"]
 	if !ok {
-		printfFunc = cg.setupPrintfRuntimeFunc()
+		concatFunc = cg.setupStringConcatRuntimeFunc()
 	}
 
-	var fmtStr strings.Builder
-	var boolArgs []value.Value
-
-	for i, arg := range expr.Arguments {
-		if i > 0 {
-			fmtStr.WriteString(" ")
-		}
-
-		switch arg.GetType().(type) {
-		case cotypes.IntType:
-			fmtStr.WriteString("%ld")
-		case cotypes.FloatType:
-			fmtStr.WriteString("%g")
-		case cotypes.BoolType:
-			fmtStr.WriteString("%s")
-
-			trueStr, ok := cg.globalDefs[TRUE_GLOBAL_DEF_NAME]
-			if !ok {
-				trueStr = cg.setupTrueGlobalDef()
-			}
+	leftPtr := cg.builder.NewExtractValue(left, 0)
+	leftLen := cg.builder.NewExtractValue(left, 1)
+	rightPtr := cg.builder.NewExtractValue(right, 0)
+	rightLen := cg.builder.NewExtractValue(right, 1)
 
-			falseStr, ok := cg.globalDefs[FALSE_GLOBAL_DEF_NAME]
-			if !ok {
-				falseStr = cg.setupFalseStrGlobalDef()
-			}
+	ptr := cg.builder.NewCall(concatFunc, leftPtr, leftLen, rightPtr, rightLen)
+	length := cg.builder.NewAdd(leftLen, rightLen)
 
-			truePtr := cg.builder.NewGetElementPtr(
-				types.NewArray(uint64(len("true\x00")), types.I8),
-				trueStr,
-				constant.NewInt(types.I64, 0),
-				constant.NewInt(types.I64, 0),
-			)
-
-			falsePtr := cg.builder.NewGetElementPtr(
-				types.NewArray(uint64(len("false\x00")), types.I8),
-				falseStr,
-				constant.NewInt(types.I64, 0),
-				constant.NewInt(types.I64, 0),
-			)
-
-			boolValue, err := cg.generateExpression(arg)
-			if err != nil {
-				return nil, err
-			}
+	str := cg.builder.NewInsertValue(constant.NewUndef(cg.getStringType()), ptr, 0)
+	return cg.builder.NewInsertValue(str, length, 1)
+}
 
-			strPtr := cg.builder.NewSelect(boolValue, truePtr, falsePtr)
-			boolArgs = append(boolArgs, strPtr)
-		}
+// strings are compared lexicographically by the runtime, which returns a negative number, zero or
+// a positive number. the result is then compared against zero with the same operator
+func (cg *Codegen) generateStringComparison(expr *ast.BinaryExpression, left, right value.Value) (value.Value, error) {
+	var pred enum.IPred
+	switch expr.Operator.Type {
+	case tokens.LESS_THAN:
+		pred = enum.IPredSLT
+	case tokens.GREATER_THAN:
+		pred = enum.IPredSGT
+	case tokens.LESS_THAN_EQUALS:
+		pred = enum.IPredSLE
+	case tokens.GREATER_THAN_EQUALS:
+		pred = enum.IPredSGE
+	case tokens.EQUALS:
+		pred = enum.IPredEQ
+	case tokens.NOT_EQUALS:
+		pred = enum.IPredNE
+	default:
+		return nil, cg.addErrorAtNode(expr, "cannot perform %s operation", expr.Operator.Type)
 	}
 
-	globalDefs := slices.Collect(maps.Values(cg.globalDefs))
-	// check if there is already a global def fmt str with the same value
-	// if yes then reuse instead of creating a new one
-	fmtStrIdx := slices.IndexFunc(globalDefs, func(e *ir.Global) bool {
-		str := fmt.Sprintf("c\"%s\\0A\\00\"", fmtStr.String()) // take the raw fmt string and convert into the style which llir/llvm returns
-		return e.Init.Ident() == str
-	})
+	compareFunc, ok := cg.runtimeFuncs["string_compare"]
+	if !ok {
+		compareFunc = cg.setupStringCompareRuntimeFunc()
+	}
 
-	var fmtGlobalDef *ir.Global
-	if fmtStrIdx != -1 {
-		fmtGlobalDef = globalDefs[fmtStrIdx]
-	} else {
-		fmtStr.WriteString("\n\x00") // add newline and null terminator character
+	result := cg.builder.NewCall(
+		compareFunc,
+		cg.builder.NewExtractValue(left, 0),
+		cg.builder.NewExtractValue(left, 1),
+		cg.builder.NewExtractValue(right, 0),
+		cg.builder.NewExtractValue(right, 1),
+	)
 
-		fmtGlobalDefName := fmt.Sprintf(".fmt.%d", cg.nameCounter)
-		fmtGlobalDef = cg.module.NewGlobalDef(fmtGlobalDefName, constant.NewCharArrayFromString(fmtStr.String()))
-		fmtGlobalDef.Immutable = true
-		fmtGlobalDef.Linkage = enum.LinkagePrivate
-		fmtGlobalDef.UnnamedAddr = enum.UnnamedAddrUnnamedAddr
+	return cg.builder.NewICmp(pred, result, constant.NewInt(types.I64, 0)), nil
+}
 
-		cg.globalDefs[fmtGlobalDefName] = fmtGlobalDef
-		cg.nameCounter++
+func (cg *Codegen) generateArrayExpression(expr *ast.ArrayExpression) (value.Value, error) {
+	if listType, ok := expr.GetType().(cotypes.ListType); ok {
+		return cg.generateListLiteral(expr, listType)
 	}
 
-	fmtPtr := cg.builder.NewGetElementPtr(
-		types.NewArray(uint64(len(fmtStr.String())), types.I8),
-		fmtGlobalDef,
-		constant.NewInt(types.I64, 0),
-		constant.NewInt(types.I64, 0),
-	)
+	llvmType, err := cg.typeToLlvm(expr.GetType())
+	if err != nil {
+		return nil, cg.propagateOrWrapError(err, expr, "failed to retrieve llvm equivalent type: %s", err.Error())
+	}
+	arrayType := llvmType.(*types.ArrayType)
 
-	args := []value.Value{fmtPtr}
-	boolIdx := 0
-	for _, arg := range expr.Arguments {
-		switch arg.GetType().(type) {
-		case cotypes.BoolType:
-			// use precomputed boolean str value
-			args = append(args, boolArgs[boolIdx])
-			boolIdx++
-		default:
-			v, err := cg.generateExpression(arg)
-			if err != nil {
-				return nil, err
-			}
+	elements := make([]value.Value, 0, len(expr.Elements))
+	constants := make([]constant.Constant, 0, len(expr.Elements))
+	for i, element := range expr.Elements {
+		v, err := cg.generateExpression(element)
+		if err != nil {
+			return nil, cg.propagateOrWrapError(err, expr, "failed to generate array element at %d idx: %s", i, err.Error())
+		}
 
-			args = append(args, v)
+		elements = append(elements, v)
+		if c, ok := v.(constant.Constant); ok {
+			constants = append(constants, c)
 		}
 	}
 
-	cg.builder.NewCall(printfFunc, args...)
-	return nil, nil
-}
-
-func (cg *Codegen) generateExitExpression(expr *ast.CallExpression) (value.Value, error) {
-	exitVal, err := cg.generateExpression(expr.Arguments[0])
-	if err != nil {
-		return nil, cg.propagateOrWrapError(err, expr, "failed to generate value for exit call expression argument: %s", err.Error())
+	// literals made up of constants only are lowered to a constant array
+	if len(constants) == len(elements) {
+		return constant.NewArray(arrayType, constants...), nil
 	}
 
-	if exitVal.Type() == types.I64 {
-		cg.ret = cg.builder.NewTrunc(exitVal, types.I32)
-	} else {
-		cg.ret = exitVal
+	var array value.Value = constant.NewUndef(arrayType)
+	for i, element := range elements {
+		array = cg.builder.NewInsertValue(array, element, uint64(i))
 	}
 
-	return nil, nil
+	return array, nil
 }
 
-func (cg *Codegen) generateIntExpression(expr *ast.CallExpression) (value.Value, error) {
-	val, err := cg.generateExpression(expr.Arguments[0])
+func (cg *Codegen) generateListLiteral(expr *ast.ArrayExpression, listType cotypes.ListType) (value.Value, error) {
+	elementType, err := cg.typeToLlvm(listType.Element)
 	if err != nil {
-		return nil, cg.propagateOrWrapError(err, expr, "failed to generate value for int call expression argument: %s", err.Error())
+		return nil, cg.propagateOrWrapError(err, expr, "failed to retrieve llvm equivalent type: %s", err.Error())
 	}
 
-	if !val.Type().Equal(types.I64) {
-		return cg.builder.NewFPToSI(val, types.I64), nil
+	list := cg.generateNewList(elementType, int64(len(expr.Elements)))
+	for i, element := range expr.Elements {
+		v, err := cg.generateExpression(element)
+		if err != nil {
+			return nil, cg.propagateOrWrapError(err, expr, "failed to generate list element at %d idx: %s", i, err.Error())
+		}
+
+		cg.generateListPush(list, elementType, v)
 	}
 
-	return val, nil
+	return list, nil
 }
 
-func (cg *Codegen) generateFloatExpression(expr *ast.CallExpression) (value.Value, error) {
-	val, err := cg.generateExpression(expr.Arguments[0])
-	if err != nil {
-		return nil, cg.propagateOrWrapError(err, expr, "failed to generate value for float call expression argument: %s", err.Error())
+func (cg *Codegen) generateNewList(elementType types.Type, capacity int64) value.Value {
+	listNewFunc, ok := cg.runtimeFuncs["list_new"]
+	if !ok {
+		listNewFunc = cg.setupListNewRuntimeFunc()
 	}
 
-	if !val.Type().Equal(types.Double) {
-		return cg.builder.NewSIToFP(val, types.Double), nil
+	return cg.builder.NewCall(listNewFunc, sizeOf(elementType), constant.NewInt(types.I64, capacity))
+}
+
+// runtime returns pointer to the slot of the pushed element, the value is stored into it afterwards
+func (cg *Codegen) generateListPush(list value.Value, elementType types.Type, v value.Value) {
+	listPushFunc, ok := cg.runtimeFuncs["list_push"]
+	if !ok {
+		listPushFunc = cg.setupListPushRuntimeFunc()
 	}
 
-	return val, nil
+	slot := cg.builder.NewCall(listPushFunc, list, sizeOf(elementType))
+	cg.builder.NewStore(v, cg.builder.NewBitCast(slot, types.NewPointer(elementType)))
 }
 
-func (cg *Codegen) generateIfExpression(expr *ast.IfExpression) (value.Value, error) {
-	condition, err := cg.generateExpression(expr.Condition)
+func (cg *Codegen) generateListLen(list value.Value) value.Value {
+	lenPtr := cg.builder.NewGetElementPtr(cg.getListType(), list, constant.NewInt(types.I32, 0), constant.NewInt(types.I32, 1))
+	return cg.builder.NewLoad(types.I64, lenPtr)
+}
+
+// returns pointer to the first element of the list
+func (cg *Codegen) generateListData(list value.Value, elementType types.Type) value.Value {
+	dataPtr := cg.builder.NewGetElementPtr(cg.getListType(), list, constant.NewInt(types.I32, 0), constant.NewInt(types.I32, 0))
+	data := cg.builder.NewLoad(types.NewPointer(types.I8), dataPtr)
+
+	return cg.builder.NewBitCast(data, types.NewPointer(elementType))
+}
+
+func (cg *Codegen) generateMapExpression(expr *ast.MapExpression) (value.Value, error) {
+	mapType := expr.GetType().(cotypes.MapType)
+	valueType, err := cg.typeToLlvm(mapType.Value)
 	if err != nil {
-		return nil, cg.propagateOrWrapError(err, expr, "failed to generate value for if-branch condition: %s", err.Error())
+		return nil, cg.propagateOrWrapError(err, expr, "failed to retrieve llvm equivalent type: %s", err.Error())
 	}
 
-	ifTrue := cg.mainFn.NewBlock("")
-	ifFalse := cg.mainFn.NewBlock("")
-	merge := cg.mainFn.NewBlock("")
-
-	cg.builder.NewCondBr(condition, ifTrue, ifFalse)
+	m := cg.generateNewMap(mapType, valueType)
+	for i := range expr.Keys {
+		key, err := cg.generateExpression(expr.Keys[i])
+		if err != nil {
+			return nil, cg.propagateOrWrapError(err, expr, "failed to generate map key at %d idx: %s", i, err.Error())
+		}
 
-	cg.builder = ifTrue
-	cg.generateStatement(expr.Consequence)
-	cg.builder.NewBr(merge)
+		v, err := cg.generateExpression(expr.Values[i])
+		if err != nil {
+			return nil, cg.propagateOrWrapError(err, expr, "failed to generate map value at %d idx: %s", i, err.Error())
+		}
 
-	cg.builder = ifFalse
-	cg.generateStatement(expr.Alternative)
-	cg.builder.NewBr(merge)
+		cg.generateMapInsert(m, mapType, valueType, key, v)
+	}
 
-	cg.builder = merge
-	return nil, nil
+	return m, nil
 }
 
-func (cg *Codegen) generateLetStatement(stmt *ast.LetStatement) error {
-	varName := stmt.Identifier.String()
-	exists := cg.scope.Has(varName)
-	if exists {
-		return cg.addErrorAtNode(stmt, "cannot redeclare %q variable", varName)
+func (cg *Codegen) generateNewMap(mapType cotypes.MapType, valueType types.Type) value.Value {
+	mapNewFunc, ok := cg.runtimeFuncs["map_new"]
+	if !ok {
+		mapNewFunc = cg.setupMapNewRuntimeFunc()
 	}
 
-	initValue, err := cg.generateExpression(stmt.Value)
-	if err != nil {
-		return cg.propagateOrWrapError(err, stmt, "failed to generate value for let statement: %s", err.Error())
+	stringKeys := int64(0)
+	if mapType.Key.Equals(cotypes.StringType{}) {
+		stringKeys = 1
 	}
 
-	varType := stmt.Value.GetType()
-	llvmType, err := cg.typeToLlvm(varType)
-	if err != nil {
-		return cg.propagateOrWrapError(err, stmt, "failed to retrieve llvm equivalent type: %s", err.Error())
+	return cg.builder.NewCall(mapNewFunc, sizeOf(valueType), constant.NewInt(types.I64, stringKeys))
+}
+
+// lowers a key into the integer and pointer pair taken by the runtime. integer, bool and char keys are passed
+// as an int along with a null pointer, string keys as their length and pointer to their bytes
+func (cg *Codegen) generateMapKey(keyType cotypes.Type, key value.Value) (value.Value, value.Value) {
+	switch keyType.(type) {
+	case cotypes.StringType:
+		return cg.builder.NewExtractValue(key, 1), cg.builder.NewExtractValue(key, 0)
+	case cotypes.BoolType:
+		return cg.builder.NewZExt(key, types.I64), constant.NewNull(types.NewPointer(types.I8))
+	default:
+		// sized integers are extended according to their signedness, chars as the u32 holding their code point
+		return cg.convertNumeric(key, keyType, cotypes.IntType{}), constant.NewNull(types.NewPointer(types.I8))
 	}
+}
 
-	alloca := cg.builder.NewAlloca(llvmType)
-	cg.builder.NewStore(initValue, alloca)
+// runtime returns pointer to the slot of the value, the value is stored into it afterwards
+func (cg *Codegen) generateMapInsert(m value.Value, mapType cotypes.MapType, valueType types.Type, key, v value.Value) {
+	mapInsertFunc, ok := cg.runtimeFuncs["map_insert"]
+	if !ok {
+		mapInsertFunc = cg.setupMapInsertRuntimeFunc()
+	}
 
-	cg.scope.Set(varName, ScopeItem{
-		alloca: alloca,
-		typ:    varType,
-	})
-	return nil
+	keyInt, keyPtr := cg.generateMapKey(mapType.Key, key)
+	slot := cg.builder.NewCall(mapInsertFunc, m, keyInt, keyPtr)
+	cg.builder.NewStore(v, cg.builder.NewBitCast(slot, types.NewPointer(valueType)))
 }
 
-func (cg *Codegen) generateAssignmentStatement(stmt *ast.AssignmentStatement) error {
-	varName := stmt.Identifier.String()
-	variable, exists := cg.scope.Get(varName)
-	if !exists {
-		return cg.addErrorAtNode(stmt, "cannot assign to undefined variable: %s", varName)
-	}
+// generates the map and key arguments shared by the map builtins
+func (cg *Codegen) generateMapArguments(expr *ast.CallExpression) (m value.Value, keyInt value.Value, keyPtr value.Value, err error) {
+	funcName := expr.Identifier.String()
 
-	newValue, err := cg.generateExpression(stmt.Value)
+	m, err = cg.generateExpression(expr.Arguments[0])
 	if err != nil {
-		return cg.propagateOrWrapError(err, stmt, "failed to generate value for assignment statement: %s", err.Error())
+		return nil, nil, nil, cg.propagateOrWrapError(err, expr, "failed to generate map for %s call expression: %s", funcName, err.Error())
 	}
-	newType := stmt.Value.GetType()
 
-	if !variable.typ.Equals(newType) {
-		return cg.addErrorAtNode(stmt, "cannot assign %s type to variable of type %s", newType, variable.typ)
+	key, err := cg.generateExpression(expr.Arguments[1])
+	if err != nil {
+		return nil, nil, nil, cg.propagateOrWrapError(err, expr, "failed to generate key for %s call expression: %s", funcName, err.Error())
 	}
 
-	cg.builder.NewStore(newValue, variable.alloca)
-	return nil
+	keyInt, keyPtr = cg.generateMapKey(expr.Arguments[0].GetType().(cotypes.MapType).Key, key)
+	return m, keyInt, keyPtr, nil
 }
 
-func (cg *Codegen) Generate(program *ast.Program) *ir.Module {
-	for _, stmt := range program.Statements {
-		if err := cg.generateStatement(stmt); err != nil {
+// fields are evaluated in the order they are written, but placed according to the order they are declared in
+func (cg *Codegen) generateStructExpression(expr *ast.StructExpression) (value.Value, error) {
+	structType := expr.GetType().(*cotypes.StructType)
+	llvmType, err := cg.typeToLlvm(structType)
+	if err != nil {
+		return nil, cg.propagateOrWrapError(err, expr, "failed to retrieve llvm equivalent type: %s", err.Error())
+	}
+
+	fields := make([]value.Value, len(structType.Fields))
+	isConstant := true
+	for _, field := range expr.Fields {
+		v, err := cg.generateExpression(field.Value)
+		if err != nil {
+			return nil, cg.propagateOrWrapError(err, expr, "failed to generate value of field %s: %s", field.Identifier, err.Error())
+		}
+
+		_, idx, _ := structType.Field(field.Identifier.String())
+		fields[idx] = v
+
+		if _, ok := v.(constant.Constant); !ok {
+			isConstant = false
+		}
+	}
+
+	// literals made up of constants only are lowered to a constant struct
+	if isConstant {
+		constants := []constant.Constant{}
+		for _, field := range fields {
+			constants = append(constants, field.(constant.Constant))
+		}
+
+		return constant.NewStruct(llvmType.(*types.StructType), constants...), nil
+	}
+
+	var structValue value.Value = constant.NewUndef(llvmType)
+	for i, field := range fields {
+		structValue = cg.builder.NewInsertValue(structValue, field, uint64(i))
+	}
+
+	return structValue, nil
+}
+
+// tuples are lowered to anonymous structs, which are passed around by value
+func (cg *Codegen) generateTupleExpression(expr *ast.TupleExpression) (value.Value, error) {
+	llvmType, err := cg.typeToLlvm(expr.GetType())
+	if err != nil {
+		return nil, cg.propagateOrWrapError(err, expr, "failed to retrieve llvm equivalent type: %s", err.Error())
+	}
+
+	var tupleValue value.Value = constant.NewUndef(llvmType)
+	for i, element := range expr.Elements {
+		v, err := cg.generateExpression(element)
+		if err != nil {
+			return nil, cg.propagateOrWrapError(err, expr, "failed to generate tuple element at %d idx: %s", i, err.Error())
+		}
+
+		tupleValue = cg.builder.NewInsertValue(tupleValue, v, uint64(i))
+	}
+
+	return tupleValue, nil
+}
+
+func (cg *Codegen) generateFieldExpression(expr *ast.FieldExpression) (value.Value, error) {
+	left, err := cg.generateExpression(expr.Left)
+	if err != nil {
+		return nil, cg.propagateOrWrapError(err, expr, "failed to generate accessed expression: %s", err.Error())
+	}
+
+	return cg.builder.NewExtractValue(left, uint64(fieldIndex(expr))), nil
+}
+
+// returns pointer to the field, so that it can be assigned to
+func (cg *Codegen) generateFieldPtr(expr *ast.FieldExpression) (value.Value, error) {
+	structPtr, err := cg.generateAddress(expr.Left)
+	if err != nil {
+		return nil, cg.propagateOrWrapError(err, expr, "failed to generate accessed expression: %s", err.Error())
+	}
+
+	llvmType, err := cg.typeToLlvm(expr.Left.GetType())
+	if err != nil {
+		return nil, cg.propagateOrWrapError(err, expr, "failed to retrieve llvm equivalent type: %s", err.Error())
+	}
+
+	return cg.builder.NewGetElementPtr(llvmType, structPtr, constant.NewInt(types.I32, 0), constant.NewInt(types.I32, int64(fieldIndex(expr)))), nil
+}
+
+func (cg *Codegen) generateVariantExpression(expr *ast.VariantExpression) (value.Value, error) {
+	enumType := expr.GetType().(*cotypes.EnumType)
+
+	payload := []value.Value{}
+	for i, arg := range expr.Arguments {
+		v, err := cg.generateExpression(arg)
+		if err != nil {
+			return nil, cg.propagateOrWrapError(err, expr, "failed to generate value at %d idx of variant %s: %s", i, expr.Variant, err.Error())
+		}
+
+		payload = append(payload, v)
+	}
+
+	_, idx, _ := enumType.Variant(expr.Variant.String())
+	v, err := cg.generateVariantValue(enumType, idx, payload)
+	if err != nil {
+		return nil, cg.propagateOrWrapError(err, expr, "failed to generate value of variant %s: %s", expr.Variant, err.Error())
+	}
+
+	return v, nil
+}
+
+// the payload is written through a pointer to the payload storage of a temporary, as its type differs between the variants
+func (cg *Codegen) generateVariantValue(t *cotypes.EnumType, idx int, payload []value.Value) (value.Value, error) {
+	enumType, err := cg.getEnumType(t)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(payload) == 0 {
+		return constant.NewStruct(enumType, constant.NewInt(types.I64, int64(idx)), constant.NewZeroInitializer(enumType.Fields[1])), nil
+	}
+
+	payloadType, err := cg.getPayloadType(t, idx)
+	if err != nil {
+		return nil, err
+	}
+
+	alloca := cg.newEntryAlloca(enumType)
+	cg.builder.NewStore(constant.NewZeroInitializer(enumType), alloca)
+
+	tagPtr := cg.builder.NewGetElementPtr(enumType, alloca, constant.NewInt(types.I32, 0), constant.NewInt(types.I32, 0))
+	cg.builder.NewStore(constant.NewInt(types.I64, int64(idx)), tagPtr)
+
+	payloadPtr := cg.generatePayloadPtr(enumType, alloca, payloadType)
+	for i, v := range payload {
+		fieldPtr := cg.builder.NewGetElementPtr(payloadType, payloadPtr, constant.NewInt(types.I32, 0), constant.NewInt(types.I32, int64(i)))
+		cg.builder.NewStore(v, fieldPtr)
+	}
+
+	return cg.builder.NewLoad(enumType, alloca), nil
+}
+
+func (cg *Codegen) generatePayloadPtr(enumType *types.StructType, enumPtr value.Value, payloadType *types.StructType) value.Value {
+	storagePtr := cg.builder.NewGetElementPtr(enumType, enumPtr, constant.NewInt(types.I32, 0), constant.NewInt(types.I32, 1))
+	return cg.builder.NewBitCast(storagePtr, types.NewPointer(payloadType))
+}
+
+// match expressions switch on the tag of the subject. the wildcard arm is the default case, without it
+// the default case is unreachable as the type checker ensures that every variant is matched
+func (cg *Codegen) generateMatchExpression(expr *ast.MatchExpression) (value.Value, error) {
+	enumType, _ := cotypes.AsEnum(expr.Subject.GetType())
+	llvmType, err := cg.getEnumType(enumType)
+	if err != nil {
+		return nil, cg.propagateOrWrapError(err, expr, "failed to retrieve llvm equivalent type: %s", err.Error())
+	}
+
+	subjectPtr, err := cg.generateAddress(expr.Subject)
+	if err != nil {
+		return nil, cg.propagateOrWrapError(err, expr, "failed to generate subject of match expression: %s", err.Error())
+	}
+
+	tagPtr := cg.builder.NewGetElementPtr(llvmType, subjectPtr, constant.NewInt(types.I32, 0), constant.NewInt(types.I32, 0))
+	tag := cg.builder.NewLoad(types.I64, tagPtr)
+
+	armBlocks := make([]*ir.Block, len(expr.Arms))
+	var cases []*ir.Case
+	var defaultBlock *ir.Block
+	for i, arm := range expr.Arms {
+		armBlocks[i] = cg.fn.NewBlock("")
+
+		if arm.IsWildcard() {
+			defaultBlock = armBlocks[i]
+			continue
+		}
+
+		_, idx, _ := enumType.Variant(arm.Variant.String())
+		cases = append(cases, ir.NewCase(constant.NewInt(types.I64, int64(idx)), armBlocks[i]))
+	}
+
+	if defaultBlock == nil {
+		defaultBlock = cg.fn.NewBlock("")
+		defaultBlock.NewUnreachable()
+	}
+
+	merge := cg.fn.NewBlock("")
+	cg.builder.NewSwitch(tag, defaultBlock, cases...)
+
+	isVoid := expr.GetType().Equals(cotypes.VoidType{})
+	var incomings []*ir.Incoming
+	for i, arm := range expr.Arms {
+		cg.builder = armBlocks[i]
+
+		previousScope := cg.scope
+		cg.scope = env.NewEnvironmentWithParent(previousScope)
+
+		if err := cg.generateMatchBindings(arm, enumType, llvmType, subjectPtr); err != nil {
+			cg.scope = previousScope
+			return nil, cg.propagateOrWrapError(err, expr, "failed to generate bindings of match arm %s: %s", arm.Variant, err.Error())
+		}
+
+		if isVoid {
+			err = cg.generateStatement(arm.Body)
+			cg.scope = previousScope
+			if err != nil {
+				return nil, err
+			}
+
+			cg.branchTo(merge)
+			continue
+		}
+
+		armValue, err := cg.generateBranchBlock(arm.Body)
+		cg.scope = previousScope
+		if err != nil {
+			return nil, err
+		}
+
+		// arm value is nil if the arm always exits, the builder is then in an unreachable block
+		if armValue == nil {
+			cg.builder.NewUnreachable()
+			continue
+		}
+
+		incomings = append(incomings, ir.NewIncoming(armValue, cg.builder))
+		cg.builder.NewBr(merge)
+	}
+
+	cg.builder = merge
+	if isVoid {
+		return nil, nil
+	}
+
+	return cg.builder.NewPhi(incomings...), nil
+}
+
+// copies the payload of the subject into the variables bound by the arm
+func (cg *Codegen) generateMatchBindings(arm *ast.MatchArm, enumType *cotypes.EnumType, llvmType *types.StructType, subjectPtr value.Value) error {
+	if len(arm.Bindings) == 0 {
+		return nil
+	}
+
+	_, idx, _ := enumType.Variant(arm.Variant.String())
+	payloadType, err := cg.getPayloadType(enumType, idx)
+	if err != nil {
+		return err
+	}
+
+	payloadPtr := cg.generatePayloadPtr(llvmType, subjectPtr, payloadType)
+	for i, binding := range arm.Bindings {
+		if binding.Literal == "_" {
+			continue
+		}
+
+		fieldType := payloadType.Fields[i]
+		fieldPtr := cg.builder.NewGetElementPtr(payloadType, payloadPtr, constant.NewInt(types.I32, 0), constant.NewInt(types.I32, int64(i)))
+
+		ptr := cg.newVariable(binding, fieldType)
+		cg.builder.NewStore(cg.builder.NewLoad(fieldType, fieldPtr), ptr)
+		cg.scope.Set(binding.Literal, ScopeItem{
+			ptr: ptr,
+			typ: enumType.Variants[idx].Payload[i],
+		})
+	}
+
+	return nil
+}
+
+func (cg *Codegen) generateIndexExpression(expr *ast.IndexExpression) (value.Value, error) {
+	if expr.Left.GetType().Equals(cotypes.StringType{}) {
+		return cg.generateStringIndex(expr)
+	}
+
+	elementPtr, err := cg.generateElementPtr(expr)
+	if err != nil {
+		return nil, err
+	}
+
+	elementType, err := cg.typeToLlvm(expr.GetType())
+	if err != nil {
+		return nil, cg.propagateOrWrapError(err, expr, "failed to retrieve llvm equivalent type: %s", err.Error())
+	}
+
+	return cg.builder.NewLoad(elementType, elementPtr), nil
+}
+
+// strings are indexed by byte offset, consistent with len counting bytes, so the indices of a string with multi-byte
+// chars are not consecutive. the runtime decodes the char whose utf-8 encoding starts at the index
+func (cg *Codegen) generateStringIndex(expr *ast.IndexExpression) (value.Value, error) {
+	str, err := cg.generateExpression(expr.Left)
+	if err != nil {
+		return nil, cg.propagateOrWrapError(err, expr, "failed to generate indexed string: %s", err.Error())
+	}
+
+	index, err := cg.generateIndex(expr)
+	if err != nil {
+		return nil, err
+	}
+
+	charAtFunc, ok := cg.runtimeFuncs["string_char_at"]
+	if !ok {
+		charAtFunc = cg.setupStringCharAtRuntimeFunc()
+	}
+
+	strLen := cg.builder.NewExtractValue(str, 1)
+	cg.generateBoundsCheck(expr, index, strLen)
+
+	return cg.builder.NewCall(charAtFunc, cg.builder.NewExtractValue(str, 0), strLen, index), nil
+}
+
+// returns pointer to the indexed element, after checking that the index is within bounds
+func (cg *Codegen) generateElementPtr(expr *ast.IndexExpression) (value.Value, error) {
+	if listType, ok := expr.Left.GetType().(cotypes.ListType); ok {
+		return cg.generateListElementPtr(expr, listType)
+	}
+
+	arrayPtr, err := cg.generateAddress(expr.Left)
+	if err != nil {
+		return nil, err
+	}
+
+	index, err := cg.generateIndex(expr)
+	if err != nil {
+		return nil, err
+	}
+
+	arrayType, ok := expr.Left.GetType().(cotypes.ArrayType)
+	if !ok {
+		return nil, cg.addErrorAtNode(expr, "cannot index into value of type %s", expr.Left.GetType())
+	}
+
+	llvmType, err := cg.typeToLlvm(arrayType)
+	if err != nil {
+		return nil, cg.propagateOrWrapError(err, expr, "failed to retrieve llvm equivalent type: %s", err.Error())
+	}
+
+	cg.generateBoundsCheck(expr, index, constant.NewInt(types.I64, arrayType.Size))
+
+	return cg.builder.NewGetElementPtr(llvmType, arrayPtr, constant.NewInt(types.I64, 0), index), nil
+}
+
+func (cg *Codegen) generateListElementPtr(expr *ast.IndexExpression, listType cotypes.ListType) (value.Value, error) {
+	list, err := cg.generateExpression(expr.Left)
+	if err != nil {
+		return nil, cg.propagateOrWrapError(err, expr, "failed to generate indexed list: %s", err.Error())
+	}
+
+	index, err := cg.generateIndex(expr)
+	if err != nil {
+		return nil, err
+	}
+
+	elementType, err := cg.typeToLlvm(listType.Element)
+	if err != nil {
+		return nil, cg.propagateOrWrapError(err, expr, "failed to retrieve llvm equivalent type: %s", err.Error())
+	}
+
+	cg.generateBoundsCheck(expr, index, cg.generateListLen(list))
+
+	return cg.builder.NewGetElementPtr(elementType, cg.generateListData(list, elementType), index), nil
+}
+
+// indices of any integer type are extended to int, which is what lengths and bounds checks are in
+func (cg *Codegen) generateIndex(expr *ast.IndexExpression) (value.Value, error) {
+	index, err := cg.generateExpression(expr.Index)
+	if err != nil {
+		return nil, cg.propagateOrWrapError(err, expr, "failed to generate index: %s", err.Error())
+	}
+
+	return cg.convertNumeric(index, expr.Index.GetType(), cotypes.IntType{}), nil
+}
+
+// aborts the program if the index is not within [0, length). constant indices into arrays are already checked by the typechecker
+func (cg *Codegen) generateBoundsCheck(expr *ast.IndexExpression, index value.Value, length value.Value) {
+	constIndex, isConstIndex := index.(*constant.Int)
+	constLength, isConstLength := length.(*constant.Int)
+	if isConstIndex && isConstLength && constIndex.X.Sign() >= 0 && constIndex.X.Cmp(constLength.X) < 0 {
+		return
+	}
+
+	oobFunc, ok := cg.runtimeFuncs["index_out_of_bounds"]
+	if !ok {
+		oobFunc = cg.setupIndexOutOfBoundsRuntimeFunc()
+	}
+
+	inBounds := cg.fn.NewBlock("")
+	outOfBounds := cg.fn.NewBlock("")
+
+	// negative indices wrap around to large unsigned values, so a single unsigned comparison checks both bounds
+	cg.builder.NewCondBr(cg.builder.NewICmp(enum.IPredULT, index, length), inBounds, outOfBounds)
+
+	outOfBounds.NewCall(oobFunc, constant.NewInt(types.I64, int64(expr.Token.Line)), index, length)
+	outOfBounds.NewUnreachable()
+
+	cg.builder = inBounds
+}
+
+// returns pointer to the memory holding the value of the expression. variables are accessed in place,
+// other values (eg. literals or return values of function calls) are first stored on the stack
+func (cg *Codegen) generateAddress(expr ast.Expression) (value.Value, error) {
+	switch e := expr.(type) {
+	case *ast.IdentifierExpression:
+		if variable, exists := cg.lookupVariable(e); exists && variable.ptr != nil {
+			return variable.ptr, nil
+		}
+	case *ast.IndexExpression:
+		return cg.generateElementPtr(e)
+	case *ast.FieldExpression:
+		return cg.generateFieldPtr(e)
+	case *ast.GroupedExpression:
+		return cg.generateAddress(e.Expr)
+	}
+
+	v, err := cg.generateExpression(expr)
+	if err != nil {
+		return nil, err
+	}
+
+	alloca := cg.newEntryAlloca(v.Type())
+	cg.builder.NewStore(v, alloca)
+
+	return alloca, nil
+}
+
+// the default value of ?? is only evaluated if the optional does not hold a value
+func (cg *Codegen) generateCoalesceExpression(expr *ast.BinaryExpression) (value.Value, error) {
+	optional, err := cg.generateExpression(expr.Left)
+	if err != nil {
+		return nil, cg.propagateOrWrapError(err, expr, "failed to generate left operand: %s", err.Error())
+	}
+
+	present := cg.fn.NewBlock("")
+	absent := cg.fn.NewBlock("")
+	merge := cg.fn.NewBlock("")
+
+	cg.builder.NewCondBr(cg.builder.NewExtractValue(optional, 0), present, absent)
+
+	// if the default value is optional itself, so is the result and the optional is used as it is
+	cg.builder = present
+	var presentValue value.Value = optional
+	if _, isOptional := expr.GetType().(cotypes.OptionalType); !isOptional {
+		presentValue = cg.builder.NewExtractValue(optional, 1)
+	}
+	cg.builder.NewBr(merge)
+
+	cg.builder = absent
+	defaultValue, err := cg.generateExpression(expr.Right)
+	if err != nil {
+		return nil, cg.propagateOrWrapError(err, expr, "failed to generate right operand: %s", err.Error())
+	}
+	absentEnd := cg.builder
+	cg.builder.NewBr(merge)
+
+	cg.builder = merge
+	return cg.builder.NewPhi(
+		ir.NewIncoming(presentValue, present),
+		ir.NewIncoming(defaultValue, absentEnd),
+	), nil
+}
+
+// && and || only evaluate the right operand if the left one does not already decide the result
+func (cg *Codegen) generateLogicalExpression(expr *ast.BinaryExpression) (value.Value, error) {
+	left, err := cg.generateExpression(expr.Left)
+	if err != nil {
+		return nil, cg.propagateOrWrapError(err, expr, "failed to generate left operand: %s", err.Error())
+	}
+	// left operand can span multiple blocks (eg. nested logical expressions), so the incoming block
+	// for the phi node is wherever the builder ended up
+	leftEnd := cg.builder
+
+	evalRight := cg.fn.NewBlock("")
+	merge := cg.fn.NewBlock("")
+
+	isAnd := expr.Operator.Type == tokens.AND
+	if isAnd {
+		cg.builder.NewCondBr(left, evalRight, merge)
+	} else {
+		cg.builder.NewCondBr(left, merge, evalRight)
+	}
+
+	cg.builder = evalRight
+	right, err := cg.generateExpression(expr.Right)
+	if err != nil {
+		return nil, cg.propagateOrWrapError(err, expr, "failed to generate right operand: %s", err.Error())
+	}
+	rightEnd := cg.builder
+	cg.builder.NewBr(merge)
+
+	cg.builder = merge
+	// if the right operand was skipped, the result is false for && and true for ||
+	return cg.builder.NewPhi(
+		ir.NewIncoming(constant.NewBool(!isAnd), leftEnd),
+		ir.NewIncoming(right, rightEnd),
+	), nil
+}
+
+func (cg *Codegen) generateIdentifier(expr *ast.IdentifierExpression) (value.Value, error) {
+	variable, exists := cg.lookupVariable(expr)
+	if !exists {
+		// functions used as values
+		if _, isFunction := cg.functions[expr.Literal]; isFunction {
+			return cg.generateFunctionValue(expr.Literal, expr.GetType().(cotypes.FunctionType))
+		}
+
+		return nil, cg.addErrorAtNode(expr, "undefined variable %q", expr.Literal)
+	}
+
+	if variable.constant != nil {
+		return variable.constant, nil
+	}
+
+	return cg.loadVariable(variable), nil
+}
+
+func (cg *Codegen) generateCallExpression(expr *ast.CallExpression) (value.Value, error) {
+	if expr.Callee != nil {
+		return cg.generateFunctionValueCall(expr)
+	}
+
+	if !expr.IsBuiltin {
+		return cg.generateFunctionCall(expr)
+	}
+
+	if expr.IsBuiltin && expr.BuiltinKind == nil {
+		return nil, cg.addErrorAtNode(expr, "function %q is marked as builtin but missing builtin kind", expr.Identifier.String())
+	}
+
+	switch *expr.BuiltinKind {
+	case ast.BuiltinFuncPrint:
+		return cg.generatePrintExpression(expr)
+	case ast.BuiltinFuncExit:
+		return cg.generateExitExpression(expr)
+	case ast.BuiltinFuncConvert:
+		return cg.generateConversionExpression(expr)
+	case ast.BuiltinFuncLen:
+		return cg.generateLenExpression(expr)
+	case ast.BuiltinFuncPush:
+		return cg.generatePushExpression(expr)
+	case ast.BuiltinFuncPop:
+		return cg.generatePopExpression(expr)
+	case ast.BuiltinFuncGet:
+		return cg.generateGetExpression(expr)
+	case ast.BuiltinFuncSet:
+		return cg.generateSetExpression(expr)
+	case ast.BuiltinFuncHas:
+		return cg.generateHasExpression(expr)
+	case ast.BuiltinFuncDelete:
+		return cg.generateDeleteExpression(expr)
+	case ast.BuiltinFuncOk, ast.BuiltinFuncErr:
+		return cg.generateResultExpression(expr)
+	default:
+		return nil, cg.addErrorAtNode(expr, "unsupported builtin function %q", expr.Identifier.String())
+	}
+}
+
+func (cg *Codegen) generatePrintExpression(expr *ast.CallExpression) (value.Value, error) {
+	funcName := expr.Identifier.String()
+	printfFunc, ok := cg.runtimeFuncs[funcName]
+	if !ok {
+		printfFunc = cg.setupPrintfRuntimeFunc()
+	}
+
+	var fmtStr strings.Builder
+	// arguments are evaluated from left to right, the format string pointer is prepended once it is known
+	var printArgs []value.Value
+
+	for i, arg := range expr.Arguments {
+		if i > 0 {
+			fmtStr.WriteString(" ")
+		}
+
+		argValue, err := cg.generateExpression(arg)
+		if err != nil {
+			return nil, cg.propagateOrWrapError(err, expr, "failed to generate print func arg at %d idx: %s", i, err.Error())
+		}
+
+		printArgs = cg.generateFormatArg(&fmtStr, printArgs, arg.GetType(), argValue)
+	}
+
+	fmtStr.WriteString("\n")
+
+	args := append([]value.Value{cg.getFormatString(fmtStr.String())}, printArgs...)
+	cg.builder.NewCall(printfFunc, args...)
+	return nil, nil
+}
+
+// writes the printf conversion of a value of the given type into the format string, and appends the arguments
+// it consumes to args. used by print and interpolated strings, which can hold values of the same types
+func (cg *Codegen) generateFormatArg(fmtStr *strings.Builder, args []value.Value, t cotypes.Type, val value.Value) []value.Value {
+	switch t := t.(type) {
+	case cotypes.IntType:
+		fmtStr.WriteString("%ld")
+		args = append(args, val)
+	case cotypes.SizedIntType:
+		// smaller integers are extended to int, which is what the format expects
+		if t.Signed {
+			fmtStr.WriteString("%ld")
+		} else {
+			fmtStr.WriteString("%lu")
+		}
+		args = append(args, cg.convertNumeric(val, t, cotypes.IntType{}))
+	case cotypes.FloatType:
+		fmtStr.WriteString("%g")
+		args = append(args, val)
+	case cotypes.Float32Type:
+		// variadic arguments are passed as double
+		fmtStr.WriteString("%g")
+		args = append(args, cg.convertNumeric(val, t, cotypes.FloatType{}))
+	case cotypes.StringType:
+		// strings are not null terminated, so the length is passed as precision
+		fmtStr.WriteString("%.*s")
+		strLen := cg.builder.NewTrunc(cg.builder.NewExtractValue(val, 1), types.I32)
+		args = append(args, strLen, cg.builder.NewExtractValue(val, 0))
+	case cotypes.CharType:
+		// chars are printed as their utf-8 encoding, which takes up to 4 bytes
+		encodeFunc, ok := cg.runtimeFuncs["char_encode"]
+		if !ok {
+			encodeFunc = cg.setupCharEncodeRuntimeFunc()
+		}
+
+		buf := cg.newEntryAlloca(types.NewArray(4, types.I8))
+		bufPtr := cg.builder.NewGetElementPtr(buf.ElemType, buf, constant.NewInt(types.I64, 0), constant.NewInt(types.I64, 0))
+		encodedLen := cg.builder.NewCall(encodeFunc, val, bufPtr)
+
+		fmtStr.WriteString("%.*s")
+		args = append(args, cg.builder.NewTrunc(encodedLen, types.I32), bufPtr)
+	case cotypes.BoolType:
+		fmtStr.WriteString("%s")
+
+		trueStr, ok := cg.globalDefs[TRUE_GLOBAL_DEF_NAME]
+		if !ok {
+			trueStr = cg.setupTrueGlobalDef()
+		}
+
+		falseStr, ok := cg.globalDefs[FALSE_GLOBAL_DEF_NAME]
+		if !ok {
+			falseStr = cg.setupFalseStrGlobalDef()
+		}
+
+		truePtr := cg.builder.NewGetElementPtr(
+			types.NewArray(uint64(len("true\x00")), types.I8),
+			trueStr,
+			constant.NewInt(types.I64, 0),
+			constant.NewInt(types.I64, 0),
+		)
+
+		falsePtr := cg.builder.NewGetElementPtr(
+			types.NewArray(uint64(len("false\x00")), types.I8),
+			falseStr,
+			constant.NewInt(types.I64, 0),
+			constant.NewInt(types.I64, 0),
+		)
+
+		args = append(args, cg.builder.NewSelect(val, truePtr, falsePtr))
+	}
+
+	return args
+}
+
+// returns pointer to a null terminated global def holding the format string, format strings with the same value share it
+func (cg *Codegen) getFormatString(format string) value.Value {
+	init := constant.NewCharArrayFromString(format + "\x00")
+
+	globalDefs := slices.Collect(maps.Values(cg.globalDefs))
+	// check if there is already a global def fmt str with the same value
+	// if yes then reuse instead of creating a new one
+	fmtStrIdx := slices.IndexFunc(globalDefs, func(e *ir.Global) bool {
+		return e.Init.Ident() == init.Ident()
+	})
+
+	var fmtGlobalDef *ir.Global
+	if fmtStrIdx != -1 {
+		fmtGlobalDef = globalDefs[fmtStrIdx]
+	} else {
+		fmtGlobalDefName := fmt.Sprintf(".fmt.%d", cg.nameCounter)
+		fmtGlobalDef = cg.module.NewGlobalDef(fmtGlobalDefName, init)
+		fmtGlobalDef.Immutable = true
+		fmtGlobalDef.Linkage = enum.LinkagePrivate
+		fmtGlobalDef.UnnamedAddr = enum.UnnamedAddrUnnamedAddr
+
+		cg.globalDefs[fmtGlobalDefName] = fmtGlobalDef
+		cg.nameCounter++
+	}
+
+	return cg.builder.NewGetElementPtr(
+		fmtGlobalDef.ContentType,
+		fmtGlobalDef,
+		constant.NewInt(types.I64, 0),
+		constant.NewInt(types.I64, 0),
+	)
+}
+
+// interpolated strings are formatted by the runtime, their string literal parts become part of the format string
+func (cg *Codegen) generateInterpolationExpression(expr *ast.InterpolationExpression) (value.Value, error) {
+	var fmtStr strings.Builder
+	var formatArgs []value.Value
+
+	for _, part := range expr.Parts {
+		// string literals keep their surrounding quotes
+		if str, ok := part.(*ast.StringExpression); ok {
+			fmtStr.WriteString(strings.ReplaceAll(str.Value[1:len(str.Value)-1], "%", "%%"))
+			continue
+		}
+
+		partValue, err := cg.generateExpression(part)
+		if err != nil {
+			return nil, cg.propagateOrWrapError(err, expr, "failed to generate interpolated expression %s: %s", part, err.Error())
+		}
+
+		formatArgs = cg.generateFormatArg(&fmtStr, formatArgs, part.GetType(), partValue)
+	}
+
+	formatFunc, ok := cg.runtimeFuncs["string_format"]
+	if !ok {
+		formatFunc = cg.setupStringFormatRuntimeFunc()
+	}
+
+	lenPtr := cg.newEntryAlloca(types.I64)
+	args := append([]value.Value{lenPtr, cg.getFormatString(fmtStr.String())}, formatArgs...)
+	strPtr := cg.builder.NewCall(formatFunc, args...)
+
+	str := cg.builder.NewInsertValue(constant.NewUndef(cg.getStringType()), strPtr, 0)
+	return cg.builder.NewInsertValue(str, cg.builder.NewLoad(types.I64, lenPtr), 1), nil
+}
+
+func (cg *Codegen) generateExitExpression(expr *ast.CallExpression) (value.Value, error) {
+	exitVal, err := cg.generateExpression(expr.Arguments[0])
+	if err != nil {
+		return nil, cg.propagateOrWrapError(err, expr, "failed to generate value for exit call expression argument: %s", err.Error())
+	}
+
+	exitFunc, ok := cg.runtimeFuncs["exit"]
+	if !ok {
+		exitFunc = cg.setupExitRuntimeFunc()
+	}
+
+	if exitVal.Type() == types.I64 {
+		exitVal = cg.builder.NewTrunc(exitVal, types.I32)
+	}
+
+	cg.builder.NewCall(exitFunc, exitVal)
+	return nil, nil
+}
+
+func (cg *Codegen) generateLenExpression(expr *ast.CallExpression) (value.Value, error) {
+	val, err := cg.generateExpression(expr.Arguments[0])
+	if err != nil {
+		return nil, cg.propagateOrWrapError(err, expr, "failed to generate value for len call expression argument: %s", err.Error())
+	}
+
+	switch t := expr.Arguments[0].GetType().(type) {
+	case cotypes.ArrayType:
+		return constant.NewInt(types.I64, t.Size), nil
+	case cotypes.ListType:
+		return cg.generateListLen(val), nil
+	case cotypes.MapType:
+		lenPtr := cg.builder.NewGetElementPtr(cg.getMapType(), val, constant.NewInt(types.I32, 0), constant.NewInt(types.I32, 0))
+		return cg.builder.NewLoad(types.I64, lenPtr), nil
+	case cotypes.StringType:
+		return cg.builder.NewExtractValue(val, 1), nil
+	default:
+		return nil, cg.addErrorAtNode(expr, "cannot get length of %s", t)
+	}
+}
+
+func (cg *Codegen) generatePushExpression(expr *ast.CallExpression) (value.Value, error) {
+	list, err := cg.generateExpression(expr.Arguments[0])
+	if err != nil {
+		return nil, cg.propagateOrWrapError(err, expr, "failed to generate list for push call expression: %s", err.Error())
+	}
+
+	val, err := cg.generateExpression(expr.Arguments[1])
+	if err != nil {
+		return nil, cg.propagateOrWrapError(err, expr, "failed to generate value for push call expression: %s", err.Error())
+	}
+
+	elementType, err := cg.typeToLlvm(expr.Arguments[1].GetType())
+	if err != nil {
+		return nil, cg.propagateOrWrapError(err, expr, "failed to retrieve llvm equivalent type: %s", err.Error())
+	}
+
+	cg.generateListPush(list, elementType, val)
+	return nil, nil
+}
+
+func (cg *Codegen) generatePopExpression(expr *ast.CallExpression) (value.Value, error) {
+	list, err := cg.generateExpression(expr.Arguments[0])
+	if err != nil {
+		return nil, cg.propagateOrWrapError(err, expr, "failed to generate list for pop call expression: %s", err.Error())
+	}
+
+	elementType, err := cg.typeToLlvm(expr.GetType())
+	if err != nil {
+		return nil, cg.propagateOrWrapError(err, expr, "failed to retrieve llvm equivalent type: %s", err.Error())
+	}
+
+	listPopFunc, ok := cg.runtimeFuncs["list_pop"]
+	if !ok {
+		listPopFunc = cg.setupListPopRuntimeFunc()
+	}
+
+	// runtime aborts if the list is empty, otherwise returns pointer to the removed element
+	slot := cg.builder.NewCall(listPopFunc, list, sizeOf(elementType), constant.NewInt(types.I64, int64(expr.Token.Line)))
+	return cg.builder.NewLoad(elementType, cg.builder.NewBitCast(slot, types.NewPointer(elementType))), nil
+}
+
+func (cg *Codegen) generateGetExpression(expr *ast.CallExpression) (value.Value, error) {
+	m, keyInt, keyPtr, err := cg.generateMapArguments(expr)
+	if err != nil {
+		return nil, err
+	}
+
+	valueType, err := cg.typeToLlvm(expr.GetType())
+	if err != nil {
+		return nil, cg.propagateOrWrapError(err, expr, "failed to retrieve llvm equivalent type: %s", err.Error())
+	}
+
+	mapGetFunc, ok := cg.runtimeFuncs["map_get"]
+	if !ok {
+		mapGetFunc = cg.setupMapGetRuntimeFunc()
+	}
+
+	// runtime aborts if the key is missing, otherwise returns pointer to its value
+	slot := cg.builder.NewCall(mapGetFunc, m, keyInt, keyPtr, constant.NewInt(types.I64, int64(expr.Token.Line)))
+	return cg.builder.NewLoad(valueType, cg.builder.NewBitCast(slot, types.NewPointer(valueType))), nil
+}
+
+func (cg *Codegen) generateSetExpression(expr *ast.CallExpression) (value.Value, error) {
+	m, err := cg.generateExpression(expr.Arguments[0])
+	if err != nil {
+		return nil, cg.propagateOrWrapError(err, expr, "failed to generate map for set call expression: %s", err.Error())
+	}
+
+	key, err := cg.generateExpression(expr.Arguments[1])
+	if err != nil {
+		return nil, cg.propagateOrWrapError(err, expr, "failed to generate key for set call expression: %s", err.Error())
+	}
+
+	val, err := cg.generateExpression(expr.Arguments[2])
+	if err != nil {
+		return nil, cg.propagateOrWrapError(err, expr, "failed to generate value for set call expression: %s", err.Error())
+	}
+
+	mapType := expr.Arguments[0].GetType().(cotypes.MapType)
+	valueType, err := cg.typeToLlvm(mapType.Value)
+	if err != nil {
+		return nil, cg.propagateOrWrapError(err, expr, "failed to retrieve llvm equivalent type: %s", err.Error())
+	}
+
+	cg.generateMapInsert(m, mapType, valueType, key, val)
+	return nil, nil
+}
+
+func (cg *Codegen) generateHasExpression(expr *ast.CallExpression) (value.Value, error) {
+	m, keyInt, keyPtr, err := cg.generateMapArguments(expr)
+	if err != nil {
+		return nil, err
+	}
+
+	mapFindFunc, ok := cg.runtimeFuncs["map_find"]
+	if !ok {
+		mapFindFunc = cg.setupMapFindRuntimeFunc()
+	}
+
+	slot := cg.builder.NewCall(mapFindFunc, m, keyInt, keyPtr)
+	return cg.builder.NewICmp(enum.IPredNE, slot, constant.NewNull(types.NewPointer(types.I8))), nil
+}
+
+func (cg *Codegen) generateDeleteExpression(expr *ast.CallExpression) (value.Value, error) {
+	m, keyInt, keyPtr, err := cg.generateMapArguments(expr)
+	if err != nil {
+		return nil, err
+	}
+
+	mapDeleteFunc, ok := cg.runtimeFuncs["map_delete"]
+	if !ok {
+		mapDeleteFunc = cg.setupMapDeleteRuntimeFunc()
+	}
+
+	cg.builder.NewCall(mapDeleteFunc, m, keyInt, keyPtr)
+	return nil, nil
+}
+
+// results are lowered as enums, ok(...) and err(...) create their first and second variant
+func (cg *Codegen) generateResultExpression(expr *ast.CallExpression) (value.Value, error) {
+	payload, err := cg.generateExpression(expr.Arguments[0])
+	if err != nil {
+		return nil, cg.propagateOrWrapError(err, expr, "failed to generate %s func arg: %s", expr.Identifier, err.Error())
+	}
+
+	idx := 0
+	if *expr.BuiltinKind == ast.BuiltinFuncErr {
+		idx = 1
+	}
+
+	v, err := cg.generateVariantValue(expr.GetType().(cotypes.ResultType).Enum(), idx, []value.Value{payload})
+	if err != nil {
+		return nil, cg.propagateOrWrapError(err, expr, "failed to generate result: %s", err.Error())
+	}
+
+	return v, nil
+}
+
+// <result>? branches on the tag of the result, an err result is converted into the result type of the
+// enclosing function and returned. otherwise the value of the ok result is loaded from its payload
+func (cg *Codegen) generateTryExpression(expr *ast.TryExpression) (value.Value, error) {
+	enumType := expr.Expr.GetType().(cotypes.ResultType).Enum()
+	llvmType, err := cg.getEnumType(enumType)
+	if err != nil {
+		return nil, cg.propagateOrWrapError(err, expr, "failed to retrieve llvm equivalent type: %s", err.Error())
+	}
+
+	resultPtr, err := cg.generateAddress(expr.Expr)
+	if err != nil {
+		return nil, cg.propagateOrWrapError(err, expr, "failed to generate operand of ? operator: %s", err.Error())
+	}
+
+	tagPtr := cg.builder.NewGetElementPtr(llvmType, resultPtr, constant.NewInt(types.I32, 0), constant.NewInt(types.I32, 0))
+	isErr := cg.builder.NewICmp(enum.IPredEQ, cg.builder.NewLoad(types.I64, tagPtr), constant.NewInt(types.I64, 1))
+
+	onErr := cg.fn.NewBlock("")
+	onOk := cg.fn.NewBlock("")
+	cg.builder.NewCondBr(isErr, onErr, onOk)
+
+	cg.builder = onErr
+	errValue, err := cg.generatePayloadLoad(enumType, llvmType, resultPtr, 1)
+	if err != nil {
+		return nil, cg.propagateOrWrapError(err, expr, "failed to generate error of ? operator: %s", err.Error())
+	}
+
+	returned, err := cg.generateVariantValue(expr.FunctionResult.(cotypes.ResultType).Enum(), 1, []value.Value{errValue})
+	if err != nil {
+		return nil, cg.propagateOrWrapError(err, expr, "failed to generate returned result of ? operator: %s", err.Error())
+	}
+	cg.builder.NewRet(returned)
+
+	cg.builder = onOk
+	okValue, err := cg.generatePayloadLoad(enumType, llvmType, resultPtr, 0)
+	if err != nil {
+		return nil, cg.propagateOrWrapError(err, expr, "failed to generate value of ? operator: %s", err.Error())
+	}
+
+	return okValue, nil
+}
+
+// loads the single payload value of the variant from the enum stored at enumPtr
+func (cg *Codegen) generatePayloadLoad(t *cotypes.EnumType, enumType *types.StructType, enumPtr value.Value, idx int) (value.Value, error) {
+	payloadType, err := cg.getPayloadType(t, idx)
+	if err != nil {
+		return nil, err
+	}
+
+	payloadPtr := cg.generatePayloadPtr(enumType, enumPtr, payloadType)
+	fieldPtr := cg.builder.NewGetElementPtr(payloadType, payloadPtr, constant.NewInt(types.I32, 0), constant.NewInt(types.I32, 0))
+	return cg.builder.NewLoad(payloadType.Fields[0], fieldPtr), nil
+}
+
+func (cg *Codegen) generateConversionExpression(expr *ast.CallExpression) (value.Value, error) {
+	val, err := cg.generateExpression(expr.Arguments[0])
+	if err != nil {
+		return nil, cg.propagateOrWrapError(err, expr, "failed to generate value for %s call expression argument: %s", expr.Identifier, err.Error())
+	}
+
+	return cg.convertNumeric(val, expr.Arguments[0].GetType(), expr.GetType()), nil
+}
+
+func (cg *Codegen) generateCastExpression(expr *ast.CastExpression) (value.Value, error) {
+	val, err := cg.generateExpression(expr.Expr)
+	if err != nil {
+		return nil, cg.propagateOrWrapError(err, expr, "failed to generate value for cast expression: %s", err.Error())
+	}
+
+	fromType, toType := expr.Expr.GetType(), expr.GetType()
+
+	if cotypes.GetTypeCategory(fromType) == cotypes.CategoryNumeric && cotypes.GetTypeCategory(toType) == cotypes.CategoryNumeric {
+		return cg.convertNumeric(val, fromType, toType), nil
+	}
+
+	// values are wrapped into optionals which hold them
+	if _, ok := toType.(cotypes.OptionalType); ok {
+		llvmType, err := cg.typeToLlvm(toType)
+		if err != nil {
+			return nil, cg.propagateOrWrapError(err, expr, "failed to retrieve llvm equivalent type: %s", err.Error())
+		}
+
+		optional := cg.builder.NewInsertValue(constant.NewUndef(llvmType), constant.True, 0)
+		return cg.builder.NewInsertValue(optional, val, 1), nil
+	}
+
+	return nil, cg.addErrorAtNode(expr, "cannot cast %s to %s", fromType, toType)
+}
+
+func (cg *Codegen) generateIfExpression(expr *ast.IfExpression) (value.Value, error) {
+	condition, err := cg.generateExpression(expr.Condition)
+	if err != nil {
+		return nil, cg.propagateOrWrapError(err, expr, "failed to generate value for if-branch condition: %s", err.Error())
+	}
+
+	// if let branches on whether the optional holds a value, which is bound in the consequence
+	var payload value.Value
+	if expr.Binding != nil {
+		payload = cg.builder.NewExtractValue(condition, 1)
+		condition = cg.builder.NewExtractValue(condition, 0)
+	}
+
+	ifTrue := cg.fn.NewBlock("")
+	ifFalse := cg.fn.NewBlock("")
+	merge := cg.fn.NewBlock("")
+
+	cg.builder.NewCondBr(condition, ifTrue, ifFalse)
+
+	if expr.GetType().Equals(cotypes.VoidType{}) {
+		cg.builder = ifTrue
+		leave := cg.enterConsequence(expr, payload)
+		cg.generateStatement(expr.Consequence)
+		leave()
+		cg.branchTo(merge)
+
+		cg.builder = ifFalse
+		if expr.Alternative != nil {
+			cg.generateStatement(expr.Alternative)
+		}
+		cg.branchTo(merge)
+
+		cg.builder = merge
+		return nil, nil
+	}
+
+	// value of each branch flows into the merge block through a phi node. branches which return,
+	// break or continue never reach the merge block, so they are left out of it
+	var incomings []*ir.Incoming
+	for _, branch := range []struct {
+		block *ir.Block
+		body  *ast.BlockStatement
+	}{{ifTrue, expr.Consequence}, {ifFalse, expr.Alternative}} {
+		cg.builder = branch.block
+		leave := func() {}
+		if branch.block == ifTrue {
+			leave = cg.enterConsequence(expr, payload)
+		}
+
+		branchValue, err := cg.generateBranchBlock(branch.body)
+		leave()
+		if err != nil {
+			return nil, err
+		}
+
+		// branch value is nil if the branch always exits, the builder is then in an unreachable block
+		if branchValue == nil {
+			cg.builder.NewUnreachable()
+			continue
+		}
+
+		incomings = append(incomings, ir.NewIncoming(branchValue, cg.builder))
+		cg.builder.NewBr(merge)
+	}
+
+	cg.builder = merge
+	return cg.builder.NewPhi(incomings...), nil
+}
+
+// enters the scope of the consequence of an if expression, in which the binding of an if let expression
+// holds the payload of the optional. the returned function leaves it again
+func (cg *Codegen) enterConsequence(expr *ast.IfExpression, payload value.Value) (leave func()) {
+	if expr.Binding == nil {
+		return func() {}
+	}
+
+	previousScope := cg.scope
+	cg.scope = env.NewEnvironmentWithParent(previousScope)
+
+	ptr := cg.newVariable(expr.Binding, payload.Type())
+	cg.builder.NewStore(payload, ptr)
+	cg.scope.Set(expr.Binding.String(), ScopeItem{
+		ptr: ptr,
+		typ: expr.Binding.GetType(),
+	})
+
+	return func() {
+		cg.scope = previousScope
+	}
+}
+
+// generates a branch of an if expression or an arm of a match expression and returns the value of its last expression
+func (cg *Codegen) generateBranchBlock(block *ast.BlockStatement) (value.Value, error) {
+	previousScope := cg.scope
+	cg.scope = env.NewEnvironmentWithParent(previousScope)
+	defer func() {
+		cg.scope = previousScope
+	}()
+
+	for _, stmt := range block.Statements[:len(block.Statements)-1] {
+		if err := cg.generateStatement(stmt); err != nil {
+			return nil, err
+		}
+	}
+
+	last, ok := block.Statements[len(block.Statements)-1].(*ast.ExpressionStatement)
+	if !ok {
+		return nil, cg.generateStatement(block.Statements[len(block.Statements)-1])
+	}
+
+	return cg.generateExpression(last.Expr)
+}
+
+func (cg *Codegen) generateWhileStatement(stmt *ast.WhileStatement) error {
+	header := cg.fn.NewBlock("")
+	body := cg.fn.NewBlock("")
+	exit := cg.fn.NewBlock("")
+
+	cg.builder.NewBr(header)
+
+	// condition is re-evaluated in the header block before every iteration
+	cg.builder = header
+	condition, err := cg.generateExpression(stmt.Condition)
+	if err != nil {
+		return cg.propagateOrWrapError(err, stmt, "failed to generate value for while loop condition: %s", err.Error())
+	}
+	cg.builder.NewCondBr(condition, body, exit)
+
+	cg.builder = body
+	cg.enterLoop(stmt.Label, exit, header)
+	err = cg.generateStatement(stmt.Body)
+	cg.exitLoop()
+	if err != nil {
+		return err
+	}
+	cg.branchTo(header)
+
+	cg.builder = exit
+	return nil
+}
+
+func (cg *Codegen) generateForStatement(stmt *ast.ForStatement) error {
+	// variables declared in the initialization statement are scoped to the loop
+	previousScope := cg.scope
+	cg.scope = env.NewEnvironmentWithParent(previousScope)
+	defer func() {
+		cg.scope = previousScope
+	}()
+
+	if stmt.Initialization != nil {
+		if err := cg.generateStatement(stmt.Initialization); err != nil {
+			return err
+		}
+	}
+
+	header := cg.fn.NewBlock("")
+	body := cg.fn.NewBlock("")
+	update := cg.fn.NewBlock("")
+	exit := cg.fn.NewBlock("")
+
+	cg.builder.NewBr(header)
+
+	// missing condition means that the loop runs until it is exited from the body
+	cg.builder = header
+	if stmt.Condition != nil {
+		condition, err := cg.generateExpression(stmt.Condition)
+		if err != nil {
+			return cg.propagateOrWrapError(err, stmt, "failed to generate value for for loop condition: %s", err.Error())
+		}
+		cg.builder.NewCondBr(condition, body, exit)
+	} else {
+		cg.builder.NewBr(body)
+	}
+
+	cg.builder = body
+	cg.enterLoop(stmt.Label, exit, update)
+	err := cg.generateStatement(stmt.Body)
+	cg.exitLoop()
+	if err != nil {
+		return err
+	}
+	cg.branchTo(update)
+
+	cg.builder = update
+	if stmt.Update != nil {
+		if err := cg.generateStatement(stmt.Update); err != nil {
+			return err
+		}
+	}
+	cg.builder.NewBr(header)
+
+	cg.builder = exit
+	return nil
+}
+
+// arrays are iterated in place and lists through their header, so the length of a list is re-read on every iteration.
+// strings are iterated by char rather than by byte, the runtime decodes each char and advances the index past it
+func (cg *Codegen) generateForInStatement(stmt *ast.ForInStatement) error {
+	previousScope := cg.scope
+	cg.scope = env.NewEnvironmentWithParent(previousScope)
+	defer func() {
+		cg.scope = previousScope
+	}()
+
+	elementType := stmt.Identifier.GetType()
+	llvmElementType, err := cg.typeToLlvm(elementType)
+	if err != nil {
+		return cg.propagateOrWrapError(err, stmt, "failed to retrieve llvm equivalent type: %s", err.Error())
+	}
+
+	var iterable value.Value
+	var iterableType types.Type
+	_, isList := stmt.Iterable.GetType().(cotypes.ListType)
+	_, isString := stmt.Iterable.GetType().(cotypes.StringType)
+	if isList || isString {
+		iterable, err = cg.generateExpression(stmt.Iterable)
+	} else {
+		iterable, err = cg.generateAddress(stmt.Iterable)
+		if err == nil {
+			iterableType, err = cg.typeToLlvm(stmt.Iterable.GetType())
+		}
+	}
+	if err != nil {
+		return cg.propagateOrWrapError(err, stmt, "failed to generate iterable of for loop: %s", err.Error())
+	}
+
+	indexAlloca := cg.newEntryAlloca(types.I64)
+	cg.builder.NewStore(constant.NewInt(types.I64, 0), indexAlloca)
+
+	header := cg.fn.NewBlock("")
+	body := cg.fn.NewBlock("")
+	update := cg.fn.NewBlock("")
+	exit := cg.fn.NewBlock("")
+
+	cg.builder.NewBr(header)
+
+	cg.builder = header
+	index := cg.builder.NewLoad(types.I64, indexAlloca)
+	var length value.Value
+	switch {
+	case isList:
+		length = cg.generateListLen(iterable)
+	case isString:
+		length = cg.builder.NewExtractValue(iterable, 1)
+	default:
+		length = constant.NewInt(types.I64, stmt.Iterable.GetType().(cotypes.ArrayType).Size)
+	}
+	cg.builder.NewCondBr(cg.builder.NewICmp(enum.IPredSLT, index, length), body, exit)
+
+	// element is copied into the loop variable at the start of every iteration
+	cg.builder = body
+	var element value.Value
+	switch {
+	case isList:
+		elementPtr := cg.builder.NewGetElementPtr(llvmElementType, cg.generateListData(iterable, llvmElementType), index)
+		element = cg.builder.NewLoad(llvmElementType, elementPtr)
+	case isString:
+		nextCharFunc, ok := cg.runtimeFuncs["string_next_char"]
+		if !ok {
+			nextCharFunc = cg.setupStringNextCharRuntimeFunc()
+		}
+
+		element = cg.builder.NewCall(nextCharFunc, cg.builder.NewExtractValue(iterable, 0), length, indexAlloca)
+	default:
+		elementPtr := cg.builder.NewGetElementPtr(iterableType, iterable, constant.NewInt(types.I64, 0), index)
+		element = cg.builder.NewLoad(llvmElementType, elementPtr)
+	}
+
+	// loop variable is declared in the body, so that closures capturing it get a separate variable for every iteration
+	loopVariable := cg.newVariable(stmt.Identifier, llvmElementType)
+	cg.builder.NewStore(element, loopVariable)
+	cg.scope.Set(stmt.Identifier.String(), ScopeItem{
+		ptr: loopVariable,
+		typ: elementType,
+	})
+
+	cg.enterLoop(stmt.Label, exit, update)
+	err = cg.generateStatement(stmt.Body)
+	cg.exitLoop()
+	if err != nil {
+		return err
+	}
+	cg.branchTo(update)
+
+	cg.builder = update
+	if !isString {
+		cg.builder.NewStore(cg.builder.NewAdd(cg.builder.NewLoad(types.I64, indexAlloca), constant.NewInt(types.I64, 1)), indexAlloca)
+	}
+	cg.builder.NewBr(header)
+
+	cg.builder = exit
+	return nil
+}
+
+func (cg *Codegen) enterLoop(label *ast.IdentifierExpression, breakTarget, continueTarget *ir.Block) {
+	info := loopInfo{
+		breakTarget:    breakTarget,
+		continueTarget: continueTarget,
+	}
+
+	if label != nil {
+		info.label = label.String()
+	}
+
+	cg.loops = append(cg.loops, info)
+}
+
+func (cg *Codegen) exitLoop() {
+	cg.loops = cg.loops[:len(cg.loops)-1]
+}
+
+func (cg *Codegen) generateLoopControl(stmt ast.Statement, label *ast.IdentifierExpression, isBreak bool) error {
+	loopIdx := len(cg.loops) - 1
+	if label != nil {
+		loopIdx = slices.IndexFunc(cg.loops, func(l loopInfo) bool {
+			return l.label == label.String()
+		})
+	}
+
+	if loopIdx < 0 {
+		return cg.addErrorAtNode(stmt, "%s statement outside of loop", stmt.TokenLiteral())
+	}
+
+	if isBreak {
+		cg.builder.NewBr(cg.loops[loopIdx].breakTarget)
+	} else {
+		cg.builder.NewBr(cg.loops[loopIdx].continueTarget)
+	}
+
+	// any statement after break or continue is unreachable, but it still needs a block to live in
+	cg.builder = cg.fn.NewBlock("")
+	return nil
+}
+
+func (cg *Codegen) generateLetStatement(stmt *ast.LetStatement) error {
+	varName := stmt.Identifier.String()
+	exists := cg.scope.Has(varName)
+	if exists {
+		return cg.addErrorAtNode(stmt, "cannot redeclare %q variable", varName)
+	}
+
+	varType := stmt.Identifier.GetType()
+	llvmType, err := cg.typeToLlvm(varType)
+	if err != nil {
+		return cg.propagateOrWrapError(err, stmt, "failed to retrieve llvm equivalent type: %s", err.Error())
+	}
+
+	var initValue value.Value
+	if stmt.Value != nil {
+		initValue, err = cg.generateExpression(stmt.Value)
+		if err != nil {
+			return cg.propagateOrWrapError(err, stmt, "failed to generate value for let statement: %s", err.Error())
+		}
+	} else {
+		initValue, err = cg.generateZeroValue(varType)
+		if err != nil {
+			return cg.propagateOrWrapError(err, stmt, "failed to generate zero value for let statement: %s", err.Error())
+		}
+	}
+
+	ptr := cg.newLetVariable(stmt.Identifier, llvmType)
+	cg.builder.NewStore(initValue, ptr)
+
+	cg.scope.Set(varName, ScopeItem{
+		ptr: ptr,
+		typ: varType,
+	})
+	return nil
+}
+
+// declares a variable for each element of the tuple, elements bound to _ are skipped
+func (cg *Codegen) generateDestructuringStatement(stmt *ast.DestructuringStatement) error {
+	tuple, err := cg.generateExpression(stmt.Value)
+	if err != nil {
+		return cg.propagateOrWrapError(err, stmt, "failed to generate value for let statement: %s", err.Error())
+	}
+
+	for i, identifier := range stmt.Identifiers {
+		varName := identifier.String()
+		if varName == "_" {
+			continue
+		}
+
+		if cg.scope.Has(varName) {
+			return cg.addErrorAtNode(stmt, "cannot redeclare %q variable", varName)
+		}
+
+		varType := identifier.GetType()
+		llvmType, err := cg.typeToLlvm(varType)
+		if err != nil {
+			return cg.propagateOrWrapError(err, stmt, "failed to retrieve llvm equivalent type: %s", err.Error())
+		}
+
+		ptr := cg.newLetVariable(identifier, llvmType)
+		cg.builder.NewStore(cg.builder.NewExtractValue(tuple, uint64(i)), ptr)
+
+		cg.scope.Set(varName, ScopeItem{
+			ptr: ptr,
+			typ: varType,
+		})
+	}
+
+	return nil
+}
+
+// variables without an initializer are zero initialized, lists start out as an empty list
+func (cg *Codegen) generateZeroValue(t cotypes.Type) (value.Value, error) {
+	llvmType, err := cg.typeToLlvm(t)
+	if err != nil {
+		return nil, err
+	}
+
+	switch t := t.(type) {
+	case cotypes.ListType:
+		elementType, err := cg.typeToLlvm(t.Element)
+		if err != nil {
+			return nil, err
+		}
+
+		return cg.generateNewList(elementType, 0), nil
+	case cotypes.MapType:
+		valueType, err := cg.typeToLlvm(t.Value)
+		if err != nil {
+			return nil, err
+		}
+
+		return cg.generateNewMap(t, valueType), nil
+	case *cotypes.StructType:
+		if !containsHeapType(t) {
+			break
+		}
+
+		var structValue value.Value = constant.NewUndef(llvmType)
+		for i, field := range t.Fields {
+			fieldValue, err := cg.generateZeroValue(field.Type)
+			if err != nil {
+				return nil, err
+			}
+
+			structValue = cg.builder.NewInsertValue(structValue, fieldValue, uint64(i))
+		}
+
+		return structValue, nil
+	case cotypes.ResultType:
+		return cg.generateZeroValue(t.Enum())
+	case cotypes.TupleType:
+		if !containsHeapType(t) {
+			break
+		}
+
+		var tupleValue value.Value = constant.NewUndef(llvmType)
+		for i, element := range t.Elements {
+			elementValue, err := cg.generateZeroValue(element)
+			if err != nil {
+				return nil, err
+			}
+
+			tupleValue = cg.builder.NewInsertValue(tupleValue, elementValue, uint64(i))
+		}
+
+		return tupleValue, nil
+	case *cotypes.EnumType:
+		if !containsHeapType(t) {
+			break
+		}
+
+		payload := []value.Value{}
+		for _, payloadType := range t.Variants[0].Payload {
+			v, err := cg.generateZeroValue(payloadType)
+			if err != nil {
+				return nil, err
+			}
+
+			payload = append(payload, v)
+		}
+
+		return cg.generateVariantValue(t, 0, payload)
+	case cotypes.ArrayType:
+		if !containsHeapType(t) {
+			break
+		}
+
+		var array value.Value = constant.NewUndef(llvmType)
+		for i := range t.Size {
+			element, err := cg.generateZeroValue(t.Element)
+			if err != nil {
+				return nil, err
+			}
+
+			array = cg.builder.NewInsertValue(array, element, uint64(i))
+		}
+
+		return array, nil
+	}
+
+	return constant.NewZeroInitializer(llvmType), nil
+}
+
+func (cg *Codegen) generateConstStatement(stmt *ast.ConstStatement) error {
+	constName := stmt.Identifier.String()
+	if cg.scope.Has(constName) {
+		return cg.addErrorAtNode(stmt, "cannot redeclare %q variable", constName)
+	}
+
+	// typechecker folds the initializer into a literal, which is lowered to an llvm constant
+	initValue, err := cg.generateExpression(stmt.Value)
+	if err != nil {
+		return cg.propagateOrWrapError(err, stmt, "failed to generate value for const statement: %s", err.Error())
+	}
+
+	constValue, ok := initValue.(constant.Constant)
+	if !ok {
+		return cg.addErrorAtNode(stmt, "initializer of constant %q is not a compile-time constant", constName)
+	}
+
+	item := ScopeItem{
+		typ:      stmt.Identifier.GetType(),
+		constant: constValue,
+	}
+	cg.scope.Set(constName, item)
+
+	if cg.fn == cg.mainFn && cg.scope.Parent() == nil {
+		cg.globals.Set(constName, item)
+	}
+
+	return nil
+}
+
+func (cg *Codegen) generateAssignmentStatement(stmt *ast.AssignmentStatement) error {
+	varName := stmt.Identifier.String()
+	variable, exists := cg.lookupVariable(stmt.Identifier)
+	if !exists {
+		return cg.addErrorAtNode(stmt, "cannot assign to undefined variable: %s", varName)
+	}
+
+	if variable.constant != nil {
+		return cg.addErrorAtNode(stmt, "cannot assign to constant: %s", varName)
+	}
+
+	newValue, err := cg.generateExpression(stmt.Value)
+	if err != nil {
+		return cg.propagateOrWrapError(err, stmt, "failed to generate value for assignment statement: %s", err.Error())
+	}
+	newType := stmt.Value.GetType()
+
+	if !variable.typ.Equals(newType) {
+		return cg.addErrorAtNode(stmt, "cannot assign %s type to variable of type %s", newType, variable.typ)
+	}
+
+	// compound assignments load the current value, apply the operator and store the result back
+	if op, isCompound := stmt.CompoundOperator(); isCompound {
+		oldValue := cg.loadVariable(variable)
+
+		if variable.typ.Equals(cotypes.StringType{}) {
+			newValue = cg.generateStringConcat(oldValue, newValue)
+		} else {
+			newValue, err = cg.generateArithmetic(stmt, op, stmt.Operator.Line, variable.typ, oldValue, newValue)
+			if err != nil {
+				return err
+			}
+		}
+	}
+
+	cg.builder.NewStore(newValue, variable.ptr)
+	return nil
+}
+
+func (cg *Codegen) generateIndexAssignmentStatement(stmt *ast.IndexAssignmentStatement) error {
+	elementPtr, err := cg.generateElementPtr(stmt.Target)
+	if err != nil {
+		return err
+	}
+
+	op, isCompound := stmt.CompoundOperator()
+	return cg.generateTargetAssignment(stmt, elementPtr, stmt.Target.GetType(), op, isCompound, stmt.Operator.Line, stmt.Value)
+}
+
+func (cg *Codegen) generateFieldAssignmentStatement(stmt *ast.FieldAssignmentStatement) error {
+	fieldPtr, err := cg.generateFieldPtr(stmt.Target)
+	if err != nil {
+		return err
+	}
+
+	op, isCompound := stmt.CompoundOperator()
+	return cg.generateTargetAssignment(stmt, fieldPtr, stmt.Target.GetType(), op, isCompound, stmt.Operator.Line, stmt.Value)
+}
+
+// stores the value into the element or field pointed to by ptr, compound assignments apply the operator to the old value first
+func (cg *Codegen) generateTargetAssignment(stmt ast.Statement, ptr value.Value, targetType cotypes.Type, op tokens.TokenType, isCompound bool, line int, valueExpr ast.Expression) error {
+	newValue, err := cg.generateExpression(valueExpr)
+	if err != nil {
+		return cg.propagateOrWrapError(err, stmt, "failed to generate value for assignment statement: %s", err.Error())
+	}
+
+	if isCompound {
+		llvmType, err := cg.typeToLlvm(targetType)
+		if err != nil {
+			return cg.propagateOrWrapError(err, stmt, "failed to retrieve llvm equivalent type: %s", err.Error())
+		}
+
+		oldValue := cg.builder.NewLoad(llvmType, ptr)
+		newValue, err = cg.generateArithmetic(stmt, op, line, targetType, oldValue, newValue)
+		if err != nil {
+			return err
+		}
+	}
+
+	cg.builder.NewStore(newValue, ptr)
+	return nil
+}
+
+func (cg *Codegen) generateFunctionCall(expr *ast.CallExpression) (value.Value, error) {
+	funcName := expr.Identifier.String()
+	if len(expr.TypeArguments) > 0 {
+		funcName = ast.InstanceName(funcName, expr.TypeArguments)
+	}
+	function, exists := cg.functions[funcName]
+	if !exists {
+		return nil, cg.addErrorAtNode(expr, "cannot call undefined function %q", funcName)
+	}
+
+	args := []value.Value{}
+	for i, arg := range expr.Arguments {
+		v, err := cg.generateExpression(arg)
+		if err != nil {
+			return nil, cg.propagateOrWrapError(err, expr, "failed to generate value for argument at %d idx: %s", i, err.Error())
+		}
+
+		args = append(args, v)
+	}
+
+	return cg.builder.NewCall(function, args...), nil
+}
+
+// declares the llvm function for a function statement, so that it can be called before its body is generated
+func (cg *Codegen) declareFunction(stmt *ast.FunctionStatement) error {
+	// generic functions are monomorphized, each instance is a separate llvm function. type arguments can contain
+	// characters which assemblers do not accept in symbol names, so the instances are numbered instead
+	if len(stmt.TypeParameters) > 0 {
+		for i, instance := range stmt.Instances {
+			if err := cg.declareFunction(instance); err != nil {
+				return err
+			}
+
+			cg.functions[instance.Identifier.String()].SetName(fmt.Sprintf("%s%s.%d", FUNCTION_NAME_PREFIX, stmt.Identifier, i))
+		}
+
+		return nil
+	}
+
+	funcName := stmt.Identifier.String()
+
+	params := []*ir.Param{}
+	for _, param := range stmt.Function.Parameters {
+		llvmType, err := cg.typeToLlvm(param.Identifier.GetType())
+		if err != nil {
+			return cg.propagateOrWrapError(err, stmt, "failed to retrieve llvm equivalent type: %s", err.Error())
+		}
+
+		params = append(params, ir.NewParam(param.Identifier.String(), llvmType))
+	}
+
+	returnType, err := cg.typeToLlvm(stmt.Function.ReturnType)
+	if err != nil {
+		return cg.propagateOrWrapError(err, stmt, "failed to retrieve llvm equivalent type: %s", err.Error())
+	}
+
+	cg.functions[funcName] = cg.module.NewFunc(FUNCTION_NAME_PREFIX+funcName, returnType, params...)
+	return nil
+}
+
+func (cg *Codegen) generateFunctionStatement(stmt *ast.FunctionStatement) error {
+	if len(stmt.TypeParameters) > 0 {
+		for _, instance := range stmt.Instances {
+			if err := cg.generateFunctionStatement(instance); err != nil {
+				return err
+			}
+		}
+
+		return nil
+	}
+
+	funcName := stmt.Identifier.String()
+	function, exists := cg.functions[funcName]
+	if !exists {
+		return cg.addErrorAtNode(stmt, "function %q is not declared", funcName)
+	}
+
+	previousFn, previousBuilder, previousScope, previousLoops := cg.fn, cg.builder, cg.scope, cg.loops
+	defer func() {
+		cg.fn, cg.builder, cg.scope, cg.loops = previousFn, previousBuilder, previousScope, previousLoops
+	}()
+	cg.loops = nil
+
+	cg.fn = function
+	cg.builder = function.NewBlock("")
+	// function bodies cannot see variables of the enclosing scope, only top level constants and variables
+	cg.scope = env.NewEnvironmentWithParent(cg.globals)
+
+	return cg.generateFunctionBody(stmt.Function, function.Params)
+}
+
+// generates the body of the function which is currently being generated, params are the llvm parameters
+// which correspond to the parameters of the function expression
+func (cg *Codegen) generateFunctionBody(expr *ast.FunctionExpression, params []*ir.Param) error {
+	// parameters are spilled to memory, so that they can be reassigned like any other variable
+	for i, param := range params {
+		ptr := cg.newVariable(expr.Parameters[i].Identifier, param.Typ)
+		cg.builder.NewStore(param, ptr)
+
+		cg.scope.Set(param.Name(), ScopeItem{
+			ptr: ptr,
+			typ: expr.Parameters[i].Identifier.GetType(),
+		})
+	}
+
+	if err := cg.generateStatement(expr.Body); err != nil {
+		return err
+	}
+
+	if cg.builder.Term == nil {
+		if expr.ReturnType.Equals(cotypes.VoidType{}) {
+			cg.builder.NewRet(nil)
+		} else {
+			// typechecker guarantees that every path returns, so this block can never be reached
+			cg.builder.NewUnreachable()
+		}
+	}
+
+	return nil
+}
+
+// function literals are lowered to a function which takes the environment of the closure as its first parameter.
+// the environment holds pointers to the captured variables, so that assignments to them are seen on both sides
+func (cg *Codegen) generateFunctionExpression(expr *ast.FunctionExpression) (value.Value, error) {
+	closureType, err := cg.typeToLlvm(expr.GetType())
+	if err != nil {
+		return nil, cg.propagateOrWrapError(err, expr, "failed to retrieve llvm equivalent type: %s", err.Error())
+	}
+
+	// captured constants are not stored in memory, they are copied into the scope of the function instead
+	captures := []string{}
+	capturedConstants := map[string]ScopeItem{}
+	envFields := []value.Value{}
+	envFieldTypes := []types.Type{}
+	for _, name := range expr.Captures {
+		variable, exists := cg.scope.Get(name)
+		if !exists {
+			return nil, cg.addErrorAtNode(expr, "undefined captured variable %q", name)
+		}
+
+		if variable.constant != nil {
+			capturedConstants[name] = variable
 			continue
 		}
+
+		captures = append(captures, name)
+		envFields = append(envFields, variable.ptr)
+		envFieldTypes = append(envFieldTypes, variable.ptr.Type())
+	}
+	envType := types.NewStruct(envFieldTypes...)
+
+	var envPtr value.Value = constant.NewNull(types.NewPointer(types.I8))
+	if len(envFields) > 0 {
+		envPtr = cg.generateHeapAlloc(envType)
+		typedEnvPtr := cg.builder.NewBitCast(envPtr, types.NewPointer(envType))
+		for i, field := range envFields {
+			cg.builder.NewStore(field, cg.builder.NewGetElementPtr(envType, typedEnvPtr, constant.NewInt(types.I32, 0), constant.NewInt(types.I32, int64(i))))
+		}
+	}
+
+	params := []*ir.Param{ir.NewParam("env", types.NewPointer(types.I8))}
+	for _, param := range expr.Parameters {
+		llvmType, err := cg.typeToLlvm(param.Identifier.GetType())
+		if err != nil {
+			return nil, cg.propagateOrWrapError(err, expr, "failed to retrieve llvm equivalent type: %s", err.Error())
+		}
+
+		params = append(params, ir.NewParam(param.Identifier.String(), llvmType))
+	}
+
+	returnType, err := cg.typeToLlvm(expr.ReturnType)
+	if err != nil {
+		return nil, cg.propagateOrWrapError(err, expr, "failed to retrieve llvm equivalent type: %s", err.Error())
+	}
+
+	function := cg.module.NewFunc(fmt.Sprintf("%s%d", CLOSURE_NAME_PREFIX, cg.nameCounter), returnType, params...)
+	cg.nameCounter++
+
+	previousFn, previousBuilder, previousScope, previousLoops := cg.fn, cg.builder, cg.scope, cg.loops
+	cg.fn = function
+	cg.builder = function.NewBlock("")
+	cg.scope = env.NewEnvironmentWithParent(cg.globals)
+	cg.loops = nil
+
+	for name, variable := range capturedConstants {
+		cg.scope.Set(name, variable)
+	}
+
+	typedEnvPtr := cg.builder.NewBitCast(function.Params[0], types.NewPointer(envType))
+	for i, name := range captures {
+		fieldPtr := cg.builder.NewGetElementPtr(envType, typedEnvPtr, constant.NewInt(types.I32, 0), constant.NewInt(types.I32, int64(i)))
+		variable, _ := previousScope.Get(name)
+		cg.scope.Set(name, ScopeItem{
+			ptr: cg.builder.NewLoad(envFieldTypes[i], fieldPtr),
+			typ: variable.typ,
+		})
+	}
+
+	err = cg.generateFunctionBody(expr, function.Params[1:])
+	cg.fn, cg.builder, cg.scope, cg.loops = previousFn, previousBuilder, previousScope, previousLoops
+	if err != nil {
+		return nil, err
+	}
+
+	if len(envFields) == 0 {
+		return constant.NewStruct(closureType.(*types.StructType), function, constant.NewNull(types.NewPointer(types.I8))), nil
+	}
+
+	var closure value.Value = constant.NewUndef(closureType)
+	closure = cg.builder.NewInsertValue(closure, function, 0)
+	return cg.builder.NewInsertValue(closure, envPtr, 1), nil
+}
+
+// function statements do not take an environment, so they are wrapped in a function which does when used as values
+func (cg *Codegen) generateFunctionValue(funcName string, t cotypes.FunctionType) (value.Value, error) {
+	closureType, err := cg.getClosureType(t)
+	if err != nil {
+		return nil, err
+	}
+
+	wrapper, ok := cg.functionValues[funcName]
+	if !ok {
+		function := cg.functions[funcName]
+
+		params := []*ir.Param{ir.NewParam("env", types.NewPointer(types.I8))}
+		args := []value.Value{}
+		for _, param := range function.Params {
+			wrapperParam := ir.NewParam(param.Name(), param.Typ)
+			params = append(params, wrapperParam)
+			args = append(args, wrapperParam)
+		}
+
+		wrapper = cg.module.NewFunc(FUNCTION_NAME_PREFIX+funcName+FUNCTION_VALUE_NAME_SUFFIX, function.Sig.RetType, params...)
+		block := wrapper.NewBlock("")
+		result := block.NewCall(function, args...)
+		if t.Return.Equals(cotypes.VoidType{}) {
+			block.NewRet(nil)
+		} else {
+			block.NewRet(result)
+		}
+
+		cg.functionValues[funcName] = wrapper
+	}
+
+	return constant.NewStruct(closureType, wrapper, constant.NewNull(types.NewPointer(types.I8))), nil
+}
+
+// calls the function of the closure with its environment. zero value of function values holds no function,
+// calling it is a runtime error
+func (cg *Codegen) generateFunctionValueCall(expr *ast.CallExpression) (value.Value, error) {
+	closure, err := cg.generateExpression(expr.Callee)
+	if err != nil {
+		return nil, cg.propagateOrWrapError(err, expr, "failed to generate called expression: %s", err.Error())
+	}
+
+	args := []value.Value{cg.builder.NewExtractValue(closure, 1)}
+	for i, arg := range expr.Arguments {
+		v, err := cg.generateExpression(arg)
+		if err != nil {
+			return nil, cg.propagateOrWrapError(err, expr, "failed to generate value for argument at %d idx: %s", i, err.Error())
+		}
+
+		args = append(args, v)
+	}
+
+	nilFunctionFunc, ok := cg.runtimeFuncs["nil_function_call"]
+	if !ok {
+		nilFunctionFunc = cg.setupNilFunctionCallRuntimeFunc()
 	}
 
-	if cg.ret != nil {
-		cg.builder.NewRet(cg.ret)
+	function := cg.builder.NewExtractValue(closure, 0)
+	isNil := cg.fn.NewBlock("")
+	notNil := cg.fn.NewBlock("")
+	cg.builder.NewCondBr(cg.builder.NewICmp(enum.IPredEQ, function, constant.NewNull(function.Type().(*types.PointerType))), isNil, notNil)
+
+	isNil.NewCall(nilFunctionFunc, constant.NewInt(types.I64, int64(expr.Token.Line)))
+	isNil.NewUnreachable()
+
+	cg.builder = notNil
+	return cg.builder.NewCall(function, args...), nil
+}
+
+func (cg *Codegen) generateReturnStatement(stmt *ast.ReturnStatement) error {
+	if stmt.Expr == nil {
+		cg.builder.NewRet(nil)
 	} else {
-		cg.builder.NewRet(constant.NewInt(types.I32, 0))
+		v, err := cg.generateExpression(stmt.Expr)
+		if err != nil {
+			return cg.propagateOrWrapError(err, stmt, "failed to generate value for return statement: %s", err.Error())
+		}
+
+		cg.builder.NewRet(v)
+	}
+
+	// any statement after return is unreachable, but it still needs a block to live in
+	cg.builder = cg.fn.NewBlock("")
+	return nil
+}
+
+func (cg *Codegen) Generate(program *ast.Program) *ir.Module {
+	// top level constants are generated first, same as in the typechecker
+	for _, stmt := range program.Statements {
+		if constStmt, ok := stmt.(*ast.ConstStatement); ok {
+			cg.generateConstStatement(constStmt)
+		}
+	}
+
+	// top level variables are globals, which are declared up front so that functions can use them, see declareGlobal
+	for _, stmt := range program.Statements {
+		switch s := stmt.(type) {
+		case *ast.LetStatement:
+			cg.declareGlobal(s.Identifier)
+		case *ast.DestructuringStatement:
+			for _, identifier := range s.Identifiers {
+				if identifier.String() != "_" {
+					cg.declareGlobal(identifier)
+				}
+			}
+		}
+	}
+
+	for _, stmt := range program.Statements {
+		if fnStmt, ok := stmt.(*ast.FunctionStatement); ok {
+			cg.declareFunction(fnStmt)
+		}
+	}
+
+	for _, stmt := range program.Statements {
+		if _, ok := stmt.(*ast.ConstStatement); ok {
+			continue
+		}
+
+		if err := cg.generateStatement(stmt); err != nil {
+			continue
+		}
 	}
 
+	cg.builder.NewRet(constant.NewInt(types.I32, 0))
+
 	return cg.module
 }
 
@@ -17,6 +17,9 @@ import (
 	"github.com/0xmukesh/coco/internal/typechecker"
 )
 
+//go:embed runtime/runtime.c
+var runtimeSource []byte
+
 type Driver struct {
 	source *Source
 }
@@ -100,7 +103,14 @@ func (d *Driver) IrFileToBinary(irFilePath string, outFilePath string, deleteIrA
 		outFilePath = strings.Replace(irFilePath, ".ll", "", 1)
 	}
 
-	cmd := exec.Command("clang", "-O2", irFilePath, "-o", outFilePath)
+	runtimeFilePath, err := writeRuntimeSource()
+	if err != nil {
+		return err
+	}
+	defer os.Remove(runtimeFilePath)
+
+	// libm is needed for float exponentiation
+	cmd := exec.Command("clang", "-O2", irFilePath, runtimeFilePath, "-o", outFilePath, "-lm")
 	cmd.Stderr = os.Stderr
 	cmd.Stdout = os.Stdout
 
@@ -111,6 +121,22 @@ func (d *Driver) IrFileToBinary(irFilePath string, outFilePath string, deleteIrA
 	return nil
 }
 
+// writes the embedded runtime into a temporary file, so that it can be compiled along with the ir
+func writeRuntimeSource() (string, error) {
+	runtimeFile, err := os.CreateTemp("", "coco_runtime_*.c")
+	if err != nil {
+		return "", err
+	}
+	defer runtimeFile.Close()
+
+	if _, err := runtimeFile.Write(runtimeSource); err != nil {
+		os.Remove(runtimeFile.Name())
+		return "", err
+	}
+
+	return runtimeFile.Name(), nil
+}
+
 func (d *Driver) Pipeline(outFilePath string, emitIr bool) error {
 	outFilePath, err := filepath.Abs(outFilePath)
 	if err != nil {
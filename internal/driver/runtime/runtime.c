@@ -0,0 +1,421 @@
+// runtime support for coco programs, compiled and linked together with the generated llvm ir.
+// strings are passed around as a pointer to their bytes and a length, they are not null terminated
+
+#include <inttypes.h>
+#include <stdarg.h>
+#include <stdint.h>
+#include <stdio.h>
+#include <stdlib.h>
+#include <string.h>
+
+// concatenates a and b into a newly allocated buffer of alen + blen bytes
+char *__coco_string_concat(const char *a, int64_t alen, const char *b, int64_t blen) {
+  char *out = malloc(alen + blen > 0 ? alen + blen : 1);
+  if (out == NULL) {
+    abort();
+  }
+
+  if (alen > 0) {
+    memcpy(out, a, alen);
+  }
+
+  if (blen > 0) {
+    memcpy(out + alen, b, blen);
+  }
+
+  return out;
+}
+
+// formats the arguments according to fmt into a newly allocated buffer, used by interpolated strings.
+// the length of the formatted string is written to len
+char *__coco_string_format(int64_t *len, const char *fmt, ...) {
+  va_list args;
+  va_start(args, fmt);
+  int n = vsnprintf(NULL, 0, fmt, args);
+  va_end(args);
+
+  if (n < 0) {
+    abort();
+  }
+
+  char *out = malloc(n + 1);
+  if (out == NULL) {
+    abort();
+  }
+
+  va_start(args, fmt);
+  vsnprintf(out, n + 1, fmt, args);
+  va_end(args);
+
+  *len = n;
+  return out;
+}
+
+// lexicographically compares a and b, returns a negative number if a < b, 0 if a == b and a positive number if a > b
+int64_t __coco_string_compare(const char *a, int64_t alen, const char *b, int64_t blen) {
+  int64_t n = alen < blen ? alen : blen;
+  if (n > 0) {
+    int cmp = memcmp(a, b, n);
+    if (cmp != 0) {
+      return cmp;
+    }
+  }
+
+  if (alen == blen) {
+    return 0;
+  }
+
+  return alen < blen ? -1 : 1;
+}
+
+// chars which cannot be decoded or encoded are replaced with U+FFFD
+#define COCO_REPLACEMENT_CHAR 0xfffd
+
+static int coco_is_scalar(uint32_t c) {
+  return c <= 0x10ffff && (c < 0xd800 || c > 0xdfff);
+}
+
+// decodes the char whose utf-8 encoding starts at byte i of s and stores the length of its encoding in width.
+// bytes which do not start a valid encoding, eg. the continuation bytes of a multi-byte char, decode to U+FFFD
+// one byte at a time
+static int32_t coco_decode_char(const char *s, int64_t len, int64_t i, int64_t *width) {
+  const unsigned char *p = (const unsigned char *)s + i;
+  *width = 1;
+  if (p[0] < 0x80) {
+    return p[0];
+  }
+
+  int64_t n;
+  uint32_t c, min;
+  if ((p[0] & 0xe0) == 0xc0) {
+    n = 1, c = p[0] & 0x1f, min = 0x80;
+  } else if ((p[0] & 0xf0) == 0xe0) {
+    n = 2, c = p[0] & 0x0f, min = 0x800;
+  } else if ((p[0] & 0xf8) == 0xf0) {
+    n = 3, c = p[0] & 0x07, min = 0x10000;
+  } else {
+    return COCO_REPLACEMENT_CHAR;
+  }
+
+  if (i + n >= len) {
+    return COCO_REPLACEMENT_CHAR;
+  }
+
+  for (int64_t k = 1; k <= n; k++) {
+    if ((p[k] & 0xc0) != 0x80) {
+      return COCO_REPLACEMENT_CHAR;
+    }
+
+    c = c << 6 | (p[k] & 0x3f);
+  }
+
+  // overlong encodings are rejected, so that each char has a single encoding
+  if (c < min || !coco_is_scalar(c)) {
+    return COCO_REPLACEMENT_CHAR;
+  }
+
+  *width = n + 1;
+  return c;
+}
+
+// strings are indexed by byte offset, like their length is counted in bytes. i is already checked to be within
+// bounds, an index into the middle of a multi-byte char decodes to U+FFFD
+int32_t __coco_string_char_at(const char *s, int64_t len, int64_t i) {
+  int64_t width;
+  return coco_decode_char(s, len, i, &width);
+}
+
+// decodes the char starting at byte *i of s and advances *i past it, which iterates over the chars of a string
+int32_t __coco_string_next_char(const char *s, int64_t len, int64_t *i) {
+  int64_t width;
+  int32_t c = coco_decode_char(s, len, *i, &width);
+  *i += width;
+  return c;
+}
+
+// writes the utf-8 encoding of c into out, which has room for 4 bytes, and returns the number of bytes written
+int64_t __coco_char_encode(int32_t c, char *out) {
+  uint32_t u = coco_is_scalar((uint32_t)c) ? (uint32_t)c : COCO_REPLACEMENT_CHAR;
+
+  if (u < 0x80) {
+    out[0] = u;
+    return 1;
+  }
+
+  if (u < 0x800) {
+    out[0] = 0xc0 | u >> 6;
+    out[1] = 0x80 | (u & 0x3f);
+    return 2;
+  }
+
+  if (u < 0x10000) {
+    out[0] = 0xe0 | u >> 12;
+    out[1] = 0x80 | (u >> 6 & 0x3f);
+    out[2] = 0x80 | (u & 0x3f);
+    return 3;
+  }
+
+  out[0] = 0xf0 | u >> 18;
+  out[1] = 0x80 | (u >> 12 & 0x3f);
+  out[2] = 0x80 | (u >> 6 & 0x3f);
+  out[3] = 0x80 | (u & 0x3f);
+  return 4;
+}
+
+// called by bounds checks of index expressions, line is the line of the index expression in the source
+void __coco_index_out_of_bounds(int64_t line, int64_t index, int64_t length) {
+  // output printed so far is still buffered, abort does not flush it
+  fflush(stdout);
+  fprintf(stderr, "runtime error at line %" PRId64 ": index %" PRId64 " out of bounds for length %" PRId64 "\n", line, index, length);
+  abort();
+}
+
+// called when a function value which holds no function is called, line is the line of the call in the source
+void __coco_nil_function_call(int64_t line) {
+  fflush(stdout);
+  fprintf(stderr, "runtime error at line %" PRId64 ": call of nil function value\n", line);
+  abort();
+}
+
+// called by integer exponentiation when 0 is raised to a negative power, which would divide by zero.
+// line is the line of the operator in the source
+void __coco_zero_to_negative_power(int64_t line, int64_t exp) {
+  fflush(stdout);
+  fprintf(stderr, "runtime error at line %" PRId64 ": 0 cannot be raised to negative power %" PRId64 "\n", line, exp);
+  abort();
+}
+
+// lists are referenced through a pointer to their header, so that every copy of a list sees the pushed elements.
+// elements are stored back to back in data, the element size is passed in by the generated code
+typedef struct {
+  char *data;
+  int64_t len;
+  int64_t cap;
+} coco_list;
+
+coco_list *__coco_list_new(int64_t elem_size, int64_t cap) {
+  coco_list *list = malloc(sizeof(coco_list));
+  if (list == NULL) {
+    abort();
+  }
+
+  list->len = 0;
+  list->cap = cap;
+  list->data = NULL;
+
+  if (cap > 0) {
+    list->data = malloc(cap * elem_size);
+    if (list->data == NULL) {
+      abort();
+    }
+  }
+
+  return list;
+}
+
+// grows the list by one element and returns pointer to the new element, which is written by the caller.
+// capacity is doubled whenever the list is full
+void *__coco_list_push(coco_list *list, int64_t elem_size) {
+  if (list->len == list->cap) {
+    int64_t cap = list->cap > 0 ? list->cap * 2 : 4;
+
+    char *data = realloc(list->data, cap * elem_size);
+    if (data == NULL) {
+      abort();
+    }
+
+    list->data = data;
+    list->cap = cap;
+  }
+
+  return list->data + list->len++ * elem_size;
+}
+
+// removes the last element of the list and returns pointer to it, which stays valid until the next push
+void *__coco_list_pop(coco_list *list, int64_t elem_size, int64_t line) {
+  if (list->len == 0) {
+    fflush(stdout);
+    fprintf(stderr, "runtime error at line %" PRId64 ": pop from empty list\n", line);
+    abort();
+  }
+
+  return list->data + --list->len * elem_size;
+}
+
+// maps are referenced through a pointer to their header like lists. they are hash tables with open addressing
+// and linear probing, deleted entries leave a tombstone behind so that probing continues past them.
+// keys are passed in as an integer and a pointer, for int and bool keys the pointer is NULL, for string keys
+// the integer is the length of the string and the pointer points to its bytes
+typedef struct {
+  int64_t key;
+  const char *ptr;
+} coco_map_key;
+
+enum { COCO_SLOT_EMPTY, COCO_SLOT_OCCUPIED, COCO_SLOT_DELETED };
+
+typedef struct {
+  int64_t len;
+  int64_t cap;
+  // occupied slots and tombstones, used to decide when to grow
+  int64_t used;
+  int64_t value_size;
+  int64_t string_keys;
+  uint8_t *states;
+  coco_map_key *keys;
+  char *values;
+} coco_map;
+
+static uint64_t coco_map_hash(const coco_map *map, int64_t key, const char *key_ptr) {
+  if (map->string_keys) {
+    // fnv-1a
+    uint64_t hash = 14695981039346656037ULL;
+    for (int64_t i = 0; i < key; i++) {
+      hash ^= (uint8_t)key_ptr[i];
+      hash *= 1099511628211ULL;
+    }
+
+    return hash;
+  }
+
+  // finalizer of splitmix64, spreads sequential integers across the table
+  uint64_t hash = (uint64_t)key;
+  hash = (hash ^ (hash >> 30)) * 0xbf58476d1ce4e5b9ULL;
+  hash = (hash ^ (hash >> 27)) * 0x94d049bb133111ebULL;
+  return hash ^ (hash >> 31);
+}
+
+static int coco_map_key_equals(const coco_map *map, const coco_map_key *slot, int64_t key, const char *key_ptr) {
+  if (slot->key != key) {
+    return 0;
+  }
+
+  return !map->string_keys || key == 0 || memcmp(slot->ptr, key_ptr, key) == 0;
+}
+
+static void coco_map_alloc(coco_map *map, int64_t cap) {
+  map->cap = cap;
+  map->states = calloc(cap, sizeof(uint8_t));
+  map->keys = malloc(cap * sizeof(coco_map_key));
+  map->values = malloc(cap * map->value_size);
+  if (map->states == NULL || map->keys == NULL || map->values == NULL) {
+    abort();
+  }
+}
+
+coco_map *__coco_map_new(int64_t value_size, int64_t string_keys) {
+  coco_map *map = malloc(sizeof(coco_map));
+  if (map == NULL) {
+    abort();
+  }
+
+  map->len = 0;
+  map->used = 0;
+  map->value_size = value_size > 0 ? value_size : 1;
+  map->string_keys = string_keys;
+  coco_map_alloc(map, 8);
+
+  return map;
+}
+
+// returns index of the slot holding the key, or -1 if the key is not in the map
+static int64_t coco_map_lookup(const coco_map *map, int64_t key, const char *key_ptr) {
+  uint64_t mask = map->cap - 1;
+  for (uint64_t i = coco_map_hash(map, key, key_ptr) & mask;; i = (i + 1) & mask) {
+    if (map->states[i] == COCO_SLOT_EMPTY) {
+      return -1;
+    }
+
+    if (map->states[i] == COCO_SLOT_OCCUPIED && coco_map_key_equals(map, &map->keys[i], key, key_ptr)) {
+      return i;
+    }
+  }
+}
+
+// rehashes all entries into a table of the given capacity, which drops the tombstones
+static void coco_map_resize(coco_map *map, int64_t cap) {
+  coco_map old = *map;
+  coco_map_alloc(map, cap);
+  map->used = map->len;
+
+  uint64_t mask = cap - 1;
+  for (int64_t i = 0; i < old.cap; i++) {
+    if (old.states[i] != COCO_SLOT_OCCUPIED) {
+      continue;
+    }
+
+    uint64_t j = coco_map_hash(map, old.keys[i].key, old.keys[i].ptr) & mask;
+    while (map->states[j] != COCO_SLOT_EMPTY) {
+      j = (j + 1) & mask;
+    }
+
+    map->states[j] = COCO_SLOT_OCCUPIED;
+    map->keys[j] = old.keys[i];
+    memcpy(map->values + j * map->value_size, old.values + i * map->value_size, map->value_size);
+  }
+
+  free(old.states);
+  free(old.keys);
+  free(old.values);
+}
+
+// returns pointer to the value of the key, NULL if the key is not in the map
+void *__coco_map_find(coco_map *map, int64_t key, const char *key_ptr) {
+  int64_t i = coco_map_lookup(map, key, key_ptr);
+  return i < 0 ? NULL : map->values + i * map->value_size;
+}
+
+// same as __coco_map_find, but aborts if the key is not in the map
+void *__coco_map_get(coco_map *map, int64_t key, const char *key_ptr, int64_t line) {
+  void *value = __coco_map_find(map, key, key_ptr);
+  if (value == NULL) {
+    fflush(stdout);
+    if (map->string_keys) {
+      fprintf(stderr, "runtime error at line %" PRId64 ": key \"%.*s\" not found in map\n", line, (int)key, key_ptr);
+    } else {
+      fprintf(stderr, "runtime error at line %" PRId64 ": key %" PRId64 " not found in map\n", line, key);
+    }
+    abort();
+  }
+
+  return value;
+}
+
+// returns pointer to the value of the key, which is written by the caller. the key is added if it is not in the map.
+// table is grown once three quarters of the slots are in use
+void *__coco_map_insert(coco_map *map, int64_t key, const char *key_ptr) {
+  int64_t existing = coco_map_lookup(map, key, key_ptr);
+  if (existing >= 0) {
+    return map->values + existing * map->value_size;
+  }
+
+  if ((map->used + 1) * 4 > map->cap * 3) {
+    coco_map_resize(map, map->len * 2 >= map->cap / 2 ? map->cap * 2 : map->cap);
+  }
+
+  uint64_t mask = map->cap - 1;
+  uint64_t i = coco_map_hash(map, key, key_ptr) & mask;
+  while (map->states[i] == COCO_SLOT_OCCUPIED) {
+    i = (i + 1) & mask;
+  }
+
+  if (map->states[i] == COCO_SLOT_EMPTY) {
+    map->used++;
+  }
+
+  map->states[i] = COCO_SLOT_OCCUPIED;
+  map->keys[i].key = key;
+  map->keys[i].ptr = key_ptr;
+  map->len++;
+
+  return map->values + i * map->value_size;
+}
+
+void __coco_map_delete(coco_map *map, int64_t key, const char *key_ptr) {
+  int64_t i = coco_map_lookup(map, key, key_ptr);
+  if (i < 0) {
+    return;
+  }
+
+  map->states[i] = COCO_SLOT_DELETED;
+  map->len--;
+}
@@ -5,6 +5,7 @@ import (
 	"errors"
 	"fmt"
 	"strings"
+	"unicode/utf8"
 
 	"github.com/0xmukesh/coco/internal/tokens"
 	"github.com/0xmukesh/coco/internal/utils"
@@ -18,6 +19,11 @@ type Lexer struct {
 	prevTokenType tokens.TokenType
 	line          int
 	column        int
+	// whether the previous token is an integer used to access a tuple element i.e. the "0" in "t.0"
+	elementIndex bool
+	// one entry per interpolated string literal whose expression is being lexed, holding the number of
+	// braces opened within the expression which are not closed yet
+	interpolations []int
 }
 
 func New(input string) *Lexer {
@@ -119,15 +125,28 @@ func (l *Lexer) readNumeric(integerOnly bool) string {
 	return l.input[startPosition : l.currPosition+1]
 }
 
-func (l *Lexer) readString(delim byte) (string, error) {
+// reads a string or char literal starting at its opening delim. string literals are only read up to their first
+// interpolated expression, if any, in which case interpolated is true and the rest is read by continueString
+func (l *Lexer) readString(delim byte) (str string, interpolated bool, err error) {
 	var out bytes.Buffer
-	foundClosingDelim := false
-
 	out.WriteByte(delim) // writing starting delim to the token literal
 
+	return l.readStringPart(&out, delim)
+}
+
+// reads the characters of a literal into out, up to its closing delim or up to the "${" starting an interpolated expression
+func (l *Lexer) readStringPart(out *bytes.Buffer, delim byte) (string, bool, error) {
+	foundClosingDelim := false
+
 	for {
 		l.readChar()
 
+		if delim == '"' && l.currChar == '$' && l.peekChar() == '{' {
+			// consume left brace
+			l.readChar()
+			return out.String(), true, nil
+		}
+
 		if l.currChar == delim {
 			foundClosingDelim = true
 		}
@@ -152,13 +171,13 @@ func (l *Lexer) readString(delim byte) (string, error) {
 					out.WriteByte('\n')
 				} else if escapeSequenceCode == 't' {
 					out.WriteByte('\t')
-				} else if escapeSequenceCode == '"' && delim == '"' {
-					out.WriteByte('"')
+				} else if escapeSequenceCode == '"' || escapeSequenceCode == '\'' || escapeSequenceCode == '$' {
+					out.WriteByte(escapeSequenceCode)
 				}
 
 				continue
 			} else {
-				return "", errors.New("invalid escape character")
+				return "", false, errors.New("invalid escape character")
 			}
 		}
 
@@ -166,9 +185,31 @@ func (l *Lexer) readString(delim byte) (string, error) {
 	}
 
 	if foundClosingDelim {
-		return out.String(), nil
+		return out.String(), false, nil
+	} else if delim == '\'' {
+		return out.String(), false, errors.New("unterminated char literal")
 	} else {
-		return out.String(), errors.New("unterminated string")
+		return out.String(), false, errors.New("unterminated string")
+	}
+}
+
+// reads the rest of an interpolated string literal after the "}" closing one of its expressions, up to either
+// the next interpolated expression or the closing quote
+func (l *Lexer) continueString() tokens.Token {
+	l.interpolations = l.interpolations[:len(l.interpolations)-1]
+
+	var out bytes.Buffer
+	startColumn := l.column + 1
+	str, interpolated, err := l.readStringPart(&out, '"')
+
+	switch {
+	case err != nil:
+		return l.newTokenWithExplicitStartColumn(tokens.ILLEGAL, startColumn, err.Error())
+	case interpolated:
+		l.interpolations = append(l.interpolations, 0)
+		return l.newTokenWithExplicitStartColumn(tokens.STRING_MIDDLE, startColumn, str)
+	default:
+		return l.newTokenWithExplicitStartColumn(tokens.STRING_TAIL, startColumn, str)
 	}
 }
 
@@ -263,6 +304,11 @@ func (l *Lexer) nextToken() tokens.Token {
 			// consume assign token
 			l.readChar()
 			tok = l.newTokenWithExplicitStartColumn(tokens.EQUALS, startColumn, "==")
+		} else if l.peekChar() == '>' {
+			startColumn := l.column
+			// consume assign token
+			l.readChar()
+			tok = l.newTokenWithExplicitStartColumn(tokens.FAT_ARROW, startColumn, "=>")
 		} else {
 			tok = l.newToken(tokens.ASSIGN, string(l.currChar))
 		}
@@ -310,9 +356,23 @@ func (l *Lexer) nextToken() tokens.Token {
 	case ')':
 		tok = l.newToken(tokens.RPAREN, string(l.currChar))
 	case '{':
+		// braces within an interpolated expression are counted, so that the one closing the expression can be told apart
+		if n := len(l.interpolations); n > 0 {
+			l.interpolations[n-1]++
+		}
+
 		tok = l.newToken(tokens.LBRACE, string(l.currChar))
 	case '}':
-		tok = l.newToken(tokens.RBRACE, string(l.currChar))
+		n := len(l.interpolations)
+		if n > 0 && l.interpolations[n-1] == 0 {
+			tok = l.continueString()
+		} else {
+			if n > 0 {
+				l.interpolations[n-1]--
+			}
+
+			tok = l.newToken(tokens.RBRACE, string(l.currChar))
+		}
 	case '[':
 		tok = l.newToken(tokens.LSQUARE, string(l.currChar))
 	case ']':
@@ -322,18 +382,50 @@ func (l *Lexer) nextToken() tokens.Token {
 	case ',':
 		tok = l.newToken(tokens.COMMA, string(l.currChar))
 	case ':':
-		tok = l.newToken(tokens.COLON, string(l.currChar))
+		if l.peekChar() == ':' {
+			startColumn := l.column
+			// consume colon token
+			l.readChar()
+			tok = l.newTokenWithExplicitStartColumn(tokens.DOUBLE_COLON, startColumn, "::")
+		} else {
+			tok = l.newToken(tokens.COLON, string(l.currChar))
+		}
+	case '?':
+		if l.peekChar() == '?' {
+			startColumn := l.column
+			// consume question token
+			l.readChar()
+			tok = l.newTokenWithExplicitStartColumn(tokens.DOUBLE_QUESTION, startColumn, "??")
+		} else {
+			tok = l.newToken(tokens.QUESTION, string(l.currChar))
+		}
 	case '"':
 		startColumn := l.column + 1
-		str, err := l.readString(l.currChar)
+		str, interpolated, err := l.readString(l.currChar)
 
 		if err != nil {
 			tok = l.newTokenWithExplicitStartColumn(tokens.ILLEGAL, startColumn, err.Error())
+		} else if interpolated {
+			l.interpolations = append(l.interpolations, 0)
+			tok = l.newTokenWithExplicitStartColumn(tokens.STRING_HEAD, startColumn, str)
 		} else {
 			tok = l.newTokenWithExplicitStartColumn(tokens.STRING, startColumn, str)
 		}
+	case '\'':
+		startColumn := l.column + 1
+		str, _, err := l.readString(l.currChar)
+
+		if err != nil {
+			tok = l.newTokenWithExplicitStartColumn(tokens.ILLEGAL, startColumn, err.Error())
+		} else if utf8.RuneCountInString(str[1:len(str)-1]) != 1 {
+			tok = l.newTokenWithExplicitStartColumn(tokens.ILLEGAL, startColumn, "char literal must contain exactly one character")
+		} else {
+			tok = l.newTokenWithExplicitStartColumn(tokens.CHAR, startColumn, str)
+		}
 	case '.':
-		if utils.IsDigit(l.peekChar()) {
+		if utils.IsDigit(l.peekChar()) && l.isElementAccess() {
+			tok = l.newToken(tokens.DOT, string(l.currChar))
+		} else if utils.IsDigit(l.peekChar()) {
 			// check if the previous token is either float/integer
 			// if yes, then "." after it is considered as malformed
 			if l.prevTokenType == tokens.FLOAT || l.prevTokenType == tokens.INTEGER {
@@ -349,7 +441,7 @@ func (l *Lexer) nextToken() tokens.Token {
 				}
 			}
 		} else {
-			tok = l.newToken(tokens.ILLEGAL, fmt.Sprintf("illegal character - %s", string(l.currChar)))
+			tok = l.newToken(tokens.DOT, string(l.currChar))
 		}
 	case 0:
 		tok = l.newToken(tokens.EOF, "")
@@ -361,7 +453,8 @@ func (l *Lexer) nextToken() tokens.Token {
 			tok = l.newTokenWithExplicitStartColumn(tokens.IdentTokenTypeLookup(identifier), startColumn, identifier)
 		} else if utils.IsDigit(l.currChar) {
 			startColumn := l.column
-			numeric := l.readNumeric(false)
+			// digits after "." in an element access i.e. "t.0.1" are an integer, rather than the start of a float
+			numeric := l.readNumeric(l.prevTokenType == tokens.DOT)
 
 			if strings.Contains(numeric, ".") {
 				tok = l.newTokenWithExplicitStartColumn(tokens.FLOAT, startColumn, numeric)
@@ -374,10 +467,24 @@ func (l *Lexer) nextToken() tokens.Token {
 	}
 
 	l.readChar()
+	l.elementIndex = tok.Type == tokens.INTEGER && l.prevTokenType == tokens.DOT
 	l.prevTokenType = tok.Type
 	return tok
 }
 
+// checks whether "." followed by a digit accesses a tuple element i.e. "t.0", "f().1", "f()?.1" or "t.0.1", rather than
+// starting a float literal
+func (l *Lexer) isElementAccess() bool {
+	switch l.prevTokenType {
+	case tokens.IDENTIFIER, tokens.RPAREN, tokens.RSQUARE, tokens.QUESTION:
+		return true
+	case tokens.INTEGER:
+		return l.elementIndex
+	default:
+		return false
+	}
+}
+
 func (l *Lexer) Lex() []tokens.Token {
 	var tks []tokens.Token
 
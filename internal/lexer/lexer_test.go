@@ -26,6 +26,7 @@ func TestLexer_SingleCharacterTokens(t *testing.T) {
 		newLexerTest("comman", ",", tokens.COMMA),
 		newLexerTest("semicolon", ";", tokens.SEMICOLON),
 		newLexerTest("colon", ":", tokens.COLON),
+		newLexerTest("question", "?", tokens.QUESTION),
 		newLexerTest("illegal", "#", tokens.ILLEGAL),
 	}
 
@@ -51,6 +52,9 @@ func TestLexer_MultiCharacterTokens(t *testing.T) {
 		newLexerTest("minus equal", "-=", tokens.MINUS_EQUAL),
 		newLexerTest("star equal", "*=", tokens.STAR_EQUAL),
 		newLexerTest("slash equal", "/=", tokens.SLASH_EQUAL),
+		newLexerTest("double colon", "::", tokens.DOUBLE_COLON),
+		newLexerTest("fat arrow", "=>", tokens.FAT_ARROW),
+		newLexerTest("double question", "??", tokens.DOUBLE_QUESTION),
 	}
 
 	for _, tt := range tests {
@@ -106,6 +110,23 @@ func TestLexer_FloatLiterals(t *testing.T) {
 	}
 }
 
+func TestLexer_TupleElementAccess(t *testing.T) {
+	tests := []lexerTestItem{
+		newLexerTestVerbose("identifier", "t.0", []tokens.TokenType{tokens.IDENTIFIER, tokens.DOT, tokens.INTEGER}, []string{"t", ".", "0"}),
+		newLexerTestVerbose("nested", "t.0.1", []tokens.TokenType{tokens.IDENTIFIER, tokens.DOT, tokens.INTEGER, tokens.DOT, tokens.INTEGER}, []string{"t", ".", "0", ".", "1"}),
+		newLexerTestVerbose("call", "f().1", []tokens.TokenType{tokens.IDENTIFIER, tokens.LPAREN, tokens.RPAREN, tokens.DOT, tokens.INTEGER}, []string{"f", "(", ")", ".", "1"}),
+		newLexerTestVerbose("propagated result", "g()?.0", []tokens.TokenType{tokens.IDENTIFIER, tokens.LPAREN, tokens.RPAREN, tokens.QUESTION, tokens.DOT, tokens.INTEGER}, []string{"g", "(", ")", "?", ".", "0"}),
+		newLexerTestVerbose("index", "xs[0].1", []tokens.TokenType{tokens.IDENTIFIER, tokens.LSQUARE, tokens.INTEGER, tokens.RSQUARE, tokens.DOT, tokens.INTEGER}, []string{"xs", "[", "0", "]", ".", "1"}),
+		newLexerTestVerbose("float argument", "f(.5)", []tokens.TokenType{tokens.IDENTIFIER, tokens.LPAREN, tokens.FLOAT, tokens.RPAREN}, []string{"f", "(", ".5", ")"}),
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			runLexerTest(t, tt)
+		})
+	}
+}
+
 func TestLexer_StringLiterals(t *testing.T) {
 	tests := []lexerTestItem{
 		newLexerTest("simple", `"hello world"`, tokens.STRING),
@@ -129,6 +150,66 @@ func TestLexer_StringLiterals(t *testing.T) {
 	}
 }
 
+func TestLexer_CharLiterals(t *testing.T) {
+	tests := []lexerTestItem{
+		newLexerTest("simple", `'a'`, tokens.CHAR),
+		newLexerTest("multi-byte", `'€'`, tokens.CHAR),
+		newLexerTest("double quote", `'"'`, tokens.CHAR),
+		// escape sequences are replaced by the characters they stand for
+		newLexerTestVerbose("newline escape", `'\n'`, []tokens.TokenType{tokens.CHAR}, []string{"'\n'"}),
+		newLexerTestVerbose("single quote escape", `'\''`, []tokens.TokenType{tokens.CHAR}, []string{"'''"}),
+		newLexerTestVerbose("backslash escape", `'\\'`, []tokens.TokenType{tokens.CHAR}, []string{`'\'`}),
+		newLexerTestFail("empty", `''`, expectIllegalToken("char literal must contain exactly one character")),
+		newLexerTestFail("multiple characters", `'ab'`, expectIllegalToken("char literal must contain exactly one character")),
+		newLexerTestFail("unterminated", `'a`, expectIllegalToken("unterminated char literal")),
+		newLexerTestFail("invalid escape character", `'\z'`, expectIllegalToken("invalid escape character")),
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			runLexerTest(t, tt)
+		})
+	}
+}
+
+func TestLexer_StringInterpolation(t *testing.T) {
+	tests := []lexerTestItem{
+		newLexerTestVerbose(
+			"single expression",
+			`"x = ${x}"`,
+			[]tokens.TokenType{tokens.STRING_HEAD, tokens.IDENTIFIER, tokens.STRING_TAIL},
+			[]string{`"x = `, "x", `"`},
+		),
+		newLexerTestVerbose(
+			"multiple expressions",
+			`"${a} and ${b + 1}!"`,
+			[]tokens.TokenType{tokens.STRING_HEAD, tokens.IDENTIFIER, tokens.STRING_MIDDLE, tokens.IDENTIFIER, tokens.PLUS, tokens.INTEGER, tokens.STRING_TAIL},
+			[]string{`"`, "a", " and ", "b", "+", "1", `!"`},
+		),
+		newLexerTestVerbose(
+			"braces within expression",
+			`"${ {} }"`,
+			[]tokens.TokenType{tokens.STRING_HEAD, tokens.LBRACE, tokens.RBRACE, tokens.STRING_TAIL},
+			[]string{`"`, "{", "}", `"`},
+		),
+		newLexerTestVerbose(
+			"nested",
+			`"${"${a}"}"`,
+			[]tokens.TokenType{tokens.STRING_HEAD, tokens.STRING_HEAD, tokens.IDENTIFIER, tokens.STRING_TAIL, tokens.STRING_TAIL},
+			[]string{`"`, `"`, "a", `"`, `"`},
+		),
+		newLexerTest("dollar without brace", `"$x"`, tokens.STRING),
+		newLexerTestVerbose("escaped", `"\${x}"`, []tokens.TokenType{tokens.STRING}, []string{`"${x}"`}),
+		newLexerTestFail("unterminated", `"${x} b`, expectIllegalToken("unterminated string")),
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			runLexerTest(t, tt)
+		})
+	}
+}
+
 func TestLexer_Whitespace(t *testing.T) {
 	tokenTypes := []tokens.TokenType{tokens.INTEGER, tokens.PLUS, tokens.INTEGER}
 	tokenLiterals := []string{"5", "+", "2"}
@@ -3,6 +3,7 @@ package parser
 import (
 	"slices"
 	"strconv"
+	"unicode/utf8"
 
 	"github.com/0xmukesh/coco/internal/ast"
 	"github.com/0xmukesh/coco/internal/tokens"
@@ -12,38 +13,42 @@ import (
 const (
 	LOWEST         = iota
 	ASSIGN         // =
-	LOGICAL        // &&, ||
+	LOGICAL_OR     // ||
+	LOGICAL_AND    // &&
 	COMPARISON     // >, >=, <, <=, ==, !=
+	COALESCE       // ??
 	ADDITION       // +, -
 	MULTIPLICATION // *, /, %
 	EXPONENTIATION // **
 	UNARY
+	POSTFIX // x++, x--, x?
 	FUNCTION_CALL
+	INDEX // a[i], a.x
 )
 
 var precedenceTable = map[tokens.TokenType]int{
 	tokens.ASSIGN:              ASSIGN,
-	tokens.AND:                 LOGICAL,
-	tokens.OR:                  LOGICAL,
+	tokens.AND:                 LOGICAL_AND,
+	tokens.OR:                  LOGICAL_OR,
 	tokens.EQUALS:              COMPARISON,
 	tokens.NOT_EQUALS:          COMPARISON,
 	tokens.LESS_THAN:           COMPARISON,
 	tokens.GREATER_THAN:        COMPARISON,
 	tokens.LESS_THAN_EQUALS:    COMPARISON,
 	tokens.GREATER_THAN_EQUALS: COMPARISON,
+	tokens.DOUBLE_QUESTION:     COALESCE,
 	tokens.MINUS:               ADDITION,
-	tokens.MINUS_EQUAL:         ADDITION,
 	tokens.PLUS:                ADDITION,
-	tokens.PLUS_EQUAL:          ADDITION,
 	tokens.STAR:                MULTIPLICATION,
-	tokens.STAR_EQUAL:          MULTIPLICATION,
 	tokens.SLASH:               MULTIPLICATION,
-	tokens.SLASH_EQUAL:         MULTIPLICATION,
 	tokens.MODULO:              MULTIPLICATION,
 	tokens.DOUBLE_STAR:         EXPONENTIATION,
-	tokens.INCREMENT:           UNARY,
-	tokens.DECREMENT:           UNARY,
+	tokens.INCREMENT:           POSTFIX,
+	tokens.DECREMENT:           POSTFIX,
+	tokens.QUESTION:            POSTFIX,
 	tokens.LPAREN:              FUNCTION_CALL,
+	tokens.LSQUARE:             INDEX,
+	tokens.DOT:                 INDEX,
 }
 
 type (
@@ -72,9 +77,12 @@ func New(tks []tokens.Token) *Parser {
 
 	p.registerPrefixFn(tokens.IDENTIFIER, p.parseIdentifierExpression)
 	p.registerPrefixFn(tokens.STRING, p.parseStringExpression)
+	p.registerPrefixFn(tokens.STRING_HEAD, p.parseInterpolationExpression)
+	p.registerPrefixFn(tokens.CHAR, p.parseCharExpression)
 	p.registerPrefixFn(tokens.INTEGER, p.parseIntegerExpression)
 	p.registerPrefixFn(tokens.TRUE, p.parseBooleanExpression)
 	p.registerPrefixFn(tokens.FALSE, p.parseBooleanExpression)
+	p.registerPrefixFn(tokens.NONE, p.parseNoneExpression)
 	p.registerPrefixFn(tokens.FLOAT, p.parseFloatExpression)
 	p.registerPrefixFn(tokens.MINUS, p.parseUnaryExpression)
 	p.registerPrefixFn(tokens.BANG, p.parseUnaryExpression)
@@ -83,16 +91,15 @@ func New(tks []tokens.Token) *Parser {
 	p.registerPrefixFn(tokens.LPAREN, p.parseGroupedExpression)
 	p.registerPrefixFn(tokens.IF, p.parseIfExpression)
 	p.registerPrefixFn(tokens.FUNCTION, p.parseFunctionExpression)
+	p.registerPrefixFn(tokens.LSQUARE, p.parseArrayExpression)
+	p.registerPrefixFn(tokens.LBRACE, p.parseMapExpression)
+	p.registerPrefixFn(tokens.MATCH, p.parseMatchExpression)
 
 	p.registerInfixFn(tokens.PLUS, p.parseBinaryExpression)
 	p.registerInfixFn(tokens.MINUS, p.parseBinaryExpression)
 	p.registerInfixFn(tokens.STAR, p.parseBinaryExpression)
 	p.registerInfixFn(tokens.SLASH, p.parseBinaryExpression)
 	p.registerInfixFn(tokens.MODULO, p.parseBinaryExpression)
-	p.registerInfixFn(tokens.PLUS_EQUAL, p.parseBinaryExpression)
-	p.registerInfixFn(tokens.MINUS_EQUAL, p.parseBinaryExpression)
-	p.registerInfixFn(tokens.STAR_EQUAL, p.parseBinaryExpression)
-	p.registerInfixFn(tokens.SLASH_EQUAL, p.parseBinaryExpression)
 	p.registerInfixFn(tokens.LESS_THAN, p.parseBinaryExpression)
 	p.registerInfixFn(tokens.GREATER_THAN, p.parseBinaryExpression)
 	p.registerInfixFn(tokens.LESS_THAN_EQUALS, p.parseBinaryExpression)
@@ -102,7 +109,13 @@ func New(tks []tokens.Token) *Parser {
 	p.registerInfixFn(tokens.OR, p.parseBinaryExpression)
 	p.registerInfixFn(tokens.AND, p.parseBinaryExpression)
 	p.registerInfixFn(tokens.DOUBLE_STAR, p.parseBinaryExpression)
+	p.registerInfixFn(tokens.DOUBLE_QUESTION, p.parseBinaryExpression)
+	p.registerInfixFn(tokens.INCREMENT, p.parsePostfixExpression)
+	p.registerInfixFn(tokens.DECREMENT, p.parsePostfixExpression)
+	p.registerInfixFn(tokens.QUESTION, p.parseTryExpression)
 	p.registerInfixFn(tokens.LPAREN, p.parseCallExpression)
+	p.registerInfixFn(tokens.LSQUARE, p.parseIndexExpression)
+	p.registerInfixFn(tokens.DOT, p.parseFieldExpression)
 
 	p.readToken()
 	p.readToken()
@@ -133,6 +146,15 @@ func (p *Parser) readToken() {
 	}
 }
 
+// returns the token n positions after the next token
+func (p *Parser) peekTokenAt(n int) tokens.Token {
+	if p.nextIdx+n >= len(p.tokens) {
+		return tokens.New(tokens.EOF, "", p.currToken.Line, p.currToken.StartColumn, p.currToken.EndColumn)
+	}
+
+	return p.tokens[p.nextIdx+n]
+}
+
 func (p *Parser) peekToken() tokens.Token {
 	if p.nextIdx >= len(p.tokens) {
 		return tokens.New(tokens.EOF, "", p.currToken.Line, p.currToken.StartColumn, p.currToken.EndColumn)
@@ -179,10 +201,209 @@ func (p *Parser) checkAndReadToken(tt tokens.TokenType) bool {
 }
 
 func (p *Parser) parseIdentifierExpression() ast.Expression {
-	return &ast.IdentifierExpression{
+	identifier := &ast.IdentifierExpression{
+		Token:   p.currToken,
+		Literal: p.currToken.Literal,
+	}
+
+	// conditions of if, while and for are wrapped in parens, so an identifier followed by
+	// a left brace can only be a struct literal
+	if p.isNextToken(tokens.LBRACE) {
+		return p.parseStructExpression(identifier)
+	}
+
+	if p.isNextToken(tokens.DOUBLE_COLON) {
+		return p.parseVariantExpression(identifier)
+	}
+
+	return identifier
+}
+
+func (p *Parser) parseVariantExpression(enum *ast.IdentifierExpression) ast.Expression {
+	p.readToken() // land on double colon
+
+	expr := &ast.VariantExpression{
+		Token:     enum.Token,
+		Enum:      enum,
+		Arguments: []ast.Expression{},
+	}
+
+	if !p.checkAndReadToken(tokens.IDENTIFIER) {
+		return nil
+	}
+
+	expr.Variant = &ast.IdentifierExpression{
 		Token:   p.currToken,
 		Literal: p.currToken.Literal,
 	}
+
+	if p.isNextToken(tokens.LPAREN) {
+		p.readToken() // land on left paren
+
+		expr.Arguments = p.parseCallArguments()
+		if expr.Arguments == nil || slices.Contains(expr.Arguments, nil) {
+			return nil
+		}
+	}
+
+	return expr
+}
+
+// match (<subject>) { <arm>, <arm>, ... }
+// commas between the arms are optional
+func (p *Parser) parseMatchExpression() ast.Expression {
+	expr := &ast.MatchExpression{
+		Token: p.currToken,
+		Arms:  []*ast.MatchArm{},
+	}
+
+	if !p.checkAndReadToken(tokens.LPAREN) {
+		return nil
+	}
+	p.readToken() // consume left paren
+
+	expr.Subject = p.parseExpression(LOWEST)
+	if expr.Subject == nil {
+		return nil
+	}
+
+	if !p.checkAndReadToken(tokens.RPAREN) {
+		return nil
+	}
+
+	if !p.checkAndReadToken(tokens.LBRACE) {
+		return nil
+	}
+
+	for !p.isNextToken(tokens.RBRACE) {
+		if !p.checkAndReadToken(tokens.IDENTIFIER) {
+			return nil
+		}
+
+		arm := p.parseMatchArm()
+		if arm == nil {
+			return nil
+		}
+		expr.Arms = append(expr.Arms, arm)
+
+		if p.isNextToken(tokens.COMMA) {
+			p.readToken() // land on comma
+		}
+	}
+
+	p.readToken() // land on right brace
+
+	return expr
+}
+
+func (p *Parser) parseMatchArm() *ast.MatchArm {
+	arm := &ast.MatchArm{
+		Token: p.currToken,
+		Variant: &ast.IdentifierExpression{
+			Token:   p.currToken,
+			Literal: p.currToken.Literal,
+		},
+		Bindings: []*ast.IdentifierExpression{},
+	}
+
+	if p.isNextToken(tokens.LPAREN) {
+		p.readToken() // land on left paren
+
+		for {
+			if !p.checkAndReadToken(tokens.IDENTIFIER) {
+				return nil
+			}
+
+			arm.Bindings = append(arm.Bindings, &ast.IdentifierExpression{
+				Token:   p.currToken,
+				Literal: p.currToken.Literal,
+			})
+
+			if !p.isNextToken(tokens.COMMA) {
+				break
+			}
+			p.readToken() // land on comma
+		}
+
+		if !p.checkAndReadToken(tokens.RPAREN) {
+			return nil
+		}
+	}
+
+	if !p.checkAndReadToken(tokens.FAT_ARROW) {
+		return nil
+	}
+	arrowToken := p.currToken
+
+	if p.isNextToken(tokens.LBRACE) {
+		p.readToken() // land on left brace
+		arm.Body = p.parseBlockStatement()
+		return arm
+	}
+
+	p.readToken()
+
+	body := p.parseExpression(LOWEST)
+	if body == nil {
+		p.addError(utils.ParserExpressionExpectedErrorBuilder(arrowToken))
+		return nil
+	}
+
+	arm.Body = &ast.BlockStatement{
+		Token:      arrowToken,
+		Statements: []ast.Statement{&ast.ExpressionStatement{Token: arrowToken, Expr: body}},
+	}
+
+	return arm
+}
+
+func (p *Parser) parseStructExpression(name *ast.IdentifierExpression) ast.Expression {
+	p.readToken() // land on left brace
+
+	expr := &ast.StructExpression{
+		Token:  name.Token,
+		Name:   name,
+		Fields: []*ast.StructFieldValue{},
+	}
+
+	for !p.isNextToken(tokens.RBRACE) {
+		if !p.checkAndReadToken(tokens.IDENTIFIER) {
+			return nil
+		}
+
+		field := &ast.StructFieldValue{
+			Identifier: &ast.IdentifierExpression{
+				Token:   p.currToken,
+				Literal: p.currToken.Literal,
+			},
+		}
+
+		if !p.checkAndReadToken(tokens.COLON) {
+			return nil
+		}
+		colonToken := p.currToken
+		p.readToken()
+
+		field.Value = p.parseExpression(LOWEST)
+		if field.Value == nil {
+			p.addError(utils.ParserExpressionExpectedErrorBuilder(colonToken))
+			return nil
+		}
+
+		expr.Fields = append(expr.Fields, field)
+
+		// trailing comma is allowed, since literals are often spread over multiple lines
+		if !p.isNextToken(tokens.COMMA) {
+			break
+		}
+		p.readToken() // land on comma
+	}
+
+	if !p.checkAndReadToken(tokens.RBRACE) {
+		return nil
+	}
+
+	return expr
 }
 
 func (p *Parser) parseStringExpression() ast.Expression {
@@ -192,6 +413,59 @@ func (p *Parser) parseStringExpression() ast.Expression {
 	}
 }
 
+// the lexer splits interpolated string literals at their embedded expressions, the literal parts are turned into string literals
+func (p *Parser) parseInterpolationExpression() ast.Expression {
+	expr := &ast.InterpolationExpression{Token: p.currToken}
+
+	addLiteral := func(tok tokens.Token, text string) {
+		if text != "" {
+			expr.Parts = append(expr.Parts, &ast.StringExpression{Token: tok, Value: "\"" + text + "\""})
+		}
+	}
+
+	// head keeps the opening quote and tail the closing one
+	addLiteral(p.currToken, p.currToken.Literal[1:])
+
+	for {
+		if p.isNextToken(tokens.STRING_MIDDLE) || p.isNextToken(tokens.STRING_TAIL) {
+			prevToken := p.currToken
+			p.readToken() // consume the literal part after the empty expression
+			p.addError(utils.ParserExpressionExpectedErrorBuilder(prevToken))
+			return nil
+		}
+
+		p.readToken()
+		part := p.parseExpression(LOWEST)
+		if part == nil {
+			return nil
+		}
+		expr.Parts = append(expr.Parts, part)
+
+		switch {
+		case p.isNextToken(tokens.STRING_MIDDLE):
+			p.readToken()
+			addLiteral(p.currToken, p.currToken.Literal)
+		case p.isNextToken(tokens.STRING_TAIL):
+			p.readToken()
+			addLiteral(p.currToken, p.currToken.Literal[:len(p.currToken.Literal)-1])
+			return expr
+		default:
+			p.addError(utils.ParserExpectedNextTokenToBeErrorBuilder(p.peekToken(), tokens.STRING_TAIL))
+			return nil
+		}
+	}
+}
+
+// the lexer has already replaced escape sequences and checked that the literal holds a single character
+func (p *Parser) parseCharExpression() ast.Expression {
+	value, _ := utf8.DecodeRuneInString(p.currToken.Literal[1:])
+
+	return &ast.CharExpression{
+		Token: p.currToken,
+		Value: value,
+	}
+}
+
 func (p *Parser) parseIntegerExpression() ast.Expression {
 	v, err := strconv.ParseInt(p.currToken.Literal, 10, 64)
 	if err != nil {
@@ -227,6 +501,12 @@ func (p *Parser) parseBooleanExpression() ast.Expression {
 	}
 }
 
+func (p *Parser) parseNoneExpression() ast.Expression {
+	return &ast.NoneExpression{
+		Token: p.currToken,
+	}
+}
+
 func (p *Parser) parseUnaryExpression() ast.Expression {
 	unaryOperator := p.currToken
 	expr := &ast.UnaryExpression{
@@ -243,6 +523,21 @@ func (p *Parser) parseUnaryExpression() ast.Expression {
 	return expr
 }
 
+func (p *Parser) parsePostfixExpression(left ast.Expression) ast.Expression {
+	return &ast.UnaryExpression{
+		Token:     p.currToken,
+		Expr:      left,
+		IsPostfix: true,
+	}
+}
+
+func (p *Parser) parseTryExpression(left ast.Expression) ast.Expression {
+	return &ast.TryExpression{
+		Token: p.currToken,
+		Expr:  left,
+	}
+}
+
 func (p *Parser) parseGroupedExpression() ast.Expression {
 	expr := &ast.GroupedExpression{}
 
@@ -256,6 +551,37 @@ func (p *Parser) parseGroupedExpression() ast.Expression {
 		return nil
 	}
 
+	// a comma after the first expression turns the grouped expression into a tuple
+	if p.isNextToken(tokens.COMMA) {
+		return p.parseTupleExpression(lParenToken, expr.Expr)
+	}
+
+	if !p.checkAndReadToken(tokens.RPAREN) {
+		return nil
+	}
+
+	return expr
+}
+
+func (p *Parser) parseTupleExpression(lParenToken tokens.Token, first ast.Expression) ast.Expression {
+	expr := &ast.TupleExpression{
+		Token:    lParenToken,
+		Elements: []ast.Expression{first},
+	}
+
+	for p.isNextToken(tokens.COMMA) {
+		p.readToken() // consume previous element
+		commaToken := p.currToken
+		p.readToken() // consume comma
+
+		element := p.parseExpression(LOWEST)
+		if element == nil {
+			p.addError(utils.ParserExpressionExpectedErrorBuilder(commaToken))
+			return nil
+		}
+		expr.Elements = append(expr.Elements, element)
+	}
+
 	if !p.checkAndReadToken(tokens.RPAREN) {
 		return nil
 	}
@@ -271,10 +597,16 @@ func (p *Parser) parseIfExpression() ast.Expression {
 		return nil
 	}
 
-	expr.Condition = p.parseExpression(LOWEST)
-	if expr.Condition == nil {
-		p.addError(utils.ParserExpressionExpectedErrorBuilder(expr.Token))
-		return nil
+	if p.isNextToken(tokens.LET) {
+		if !p.parseIfLetBinding(expr) {
+			return nil
+		}
+	} else {
+		expr.Condition = p.parseExpression(LOWEST)
+		if expr.Condition == nil {
+			p.addError(utils.ParserExpressionExpectedErrorBuilder(expr.Token))
+			return nil
+		}
 	}
 
 	if !p.checkAndReadToken(tokens.LBRACE) {
@@ -312,103 +644,530 @@ func (p *Parser) parseIfExpression() ast.Expression {
 	return expr
 }
 
-func (p *Parser) parseFunctionParameters() []*ast.IdentifierExpression {
-	parameters := []*ast.IdentifierExpression{}
+// parses "let <binding> = <optional>)" of an if let expression, starting at the opening paren
+func (p *Parser) parseIfLetBinding(expr *ast.IfExpression) bool {
+	p.readToken() // land on let
 
-	if p.isNextToken(tokens.RPAREN) {
-		p.readToken() // consume left paren
-		return parameters
+	if !p.checkAndReadToken(tokens.IDENTIFIER) {
+		return false
 	}
 
-	p.readToken()
-
-	parameters = append(parameters, &ast.IdentifierExpression{
+	expr.Binding = &ast.IdentifierExpression{
 		Token:   p.currToken,
 		Literal: p.currToken.Literal,
-	})
-
-	for p.isNextToken(tokens.COMMA) {
-		p.readToken() // consume previous parameter
-		p.readToken() // consume comma
+	}
 
-		parameters = append(parameters, &ast.IdentifierExpression{
-			Token:   p.currToken,
-			Literal: p.currToken.Literal,
-		})
+	if !p.checkAndReadToken(tokens.ASSIGN) {
+		return false
 	}
+	p.readToken()
 
-	if !p.checkAndReadToken(tokens.RPAREN) {
-		return nil
+	expr.Condition = p.parseExpression(LOWEST)
+	if expr.Condition == nil {
+		p.addError(utils.ParserExpressionExpectedErrorBuilder(expr.Token))
+		return false
 	}
 
-	return parameters
+	return p.checkAndReadToken(tokens.RPAREN)
 }
 
-func (p *Parser) parseFunctionExpression() ast.Expression {
-	expr := &ast.FunctionExpression{
+// [<type>; <size>] or []<type>
+func (p *Parser) parseArrayTypeAnnotation() ast.TypeAnnotation {
+	if p.isNextToken(tokens.RSQUARE) {
+		return p.parseListTypeAnnotation()
+	}
+
+	annotation := &ast.ArrayTypeAnnotation{
 		Token: p.currToken,
 	}
 
-	if !p.checkAndReadToken(tokens.LPAREN) {
+	p.readToken()
+
+	annotation.Element = p.parseTypeAnnotation()
+	if annotation.Element == nil {
 		return nil
 	}
-	expr.Parameters = p.parseFunctionParameters()
 
-	if !p.checkAndReadToken(tokens.LBRACE) {
+	if !p.checkAndReadToken(tokens.SEMICOLON) {
 		return nil
 	}
-	expr.Body = p.parseBlockStatement()
 
-	return expr
-}
+	if !p.checkAndReadToken(tokens.INTEGER) {
+		return nil
+	}
 
-func (p *Parser) parseCallArguments() []ast.Expression {
-	args := []ast.Expression{}
+	size, err := strconv.ParseInt(p.currToken.Literal, 10, 64)
+	if err != nil {
+		p.addError(utils.ParserFailedToParseExpressionErrorBuilder(p.currToken, err.Error()))
+		return nil
+	}
+	annotation.Size = size
 
-	if p.isNextToken(tokens.RPAREN) {
-		p.readToken() // consume left paren
-		return args
+	if !p.checkAndReadToken(tokens.RSQUARE) {
+		return nil
 	}
 
-	p.readToken()
+	return annotation
+}
 
-	args = append(args, p.parseExpression(LOWEST))
+// map[<key type>]<value type>
+func (p *Parser) parseMapTypeAnnotation() ast.TypeAnnotation {
+	annotation := &ast.MapTypeAnnotation{
+		Token: p.currToken,
+	}
 
-	for p.isNextToken(tokens.COMMA) {
-		p.readToken() // consume previous argument
-		p.readToken() // consume comma
+	if !p.checkAndReadToken(tokens.LSQUARE) {
+		return nil
+	}
+	p.readToken() // consume left square bracket
 
-		args = append(args, p.parseExpression(LOWEST))
+	annotation.Key = p.parseTypeAnnotation()
+	if annotation.Key == nil {
+		return nil
 	}
 
-	if !p.checkAndReadToken(tokens.RPAREN) {
+	if !p.checkAndReadToken(tokens.RSQUARE) {
+		return nil
+	}
+	p.readToken() // consume right square bracket
+
+	annotation.Value = p.parseTypeAnnotation()
+	if annotation.Value == nil {
 		return nil
 	}
 
-	return args
+	return annotation
 }
 
-func (p *Parser) parseCallExpression(left ast.Expression) ast.Expression {
-	expr := &ast.CallExpression{
+func (p *Parser) parseResultTypeAnnotation() ast.TypeAnnotation {
+	annotation := &ast.ResultTypeAnnotation{
 		Token: p.currToken,
 	}
 
-	identifier, ok := left.(*ast.IdentifierExpression)
-	if !ok {
-		p.addError(utils.ParseExpectedXExpressionErrorBuilder[*ast.IdentifierExpression](p.currToken, left))
+	p.readToken() // land on less than
+	p.readToken() // consume less than
+
+	annotation.Value = p.parseTypeAnnotation()
+	if annotation.Value == nil {
 		return nil
 	}
 
-	expr.Identifier = identifier
-	expr.Arguments = p.parseCallArguments()
+	if !p.checkAndReadToken(tokens.COMMA) {
+		return nil
+	}
+	p.readToken() // consume comma
 
-	return expr
-}
+	annotation.Error = p.parseTypeAnnotation()
+	if annotation.Error == nil {
+		return nil
+	}
 
-func (p *Parser) parseBinaryExpression(left ast.Expression) ast.Expression {
-	expr := &ast.BinaryExpression{
-		Left:     left,
-		Operator: p.currToken,
+	if !p.checkAndReadToken(tokens.GREATER_THAN) {
+		return nil
+	}
+
+	return annotation
+}
+
+// parses fn(<parameter types>) along with the optional ": <return type>" suffix
+func (p *Parser) parseFunctionTypeAnnotation() ast.TypeAnnotation {
+	annotation := &ast.FunctionTypeAnnotation{
+		Token:  p.currToken,
+		Params: []ast.TypeAnnotation{},
+	}
+
+	if !p.checkAndReadToken(tokens.LPAREN) {
+		return nil
+	}
+
+	if p.isNextToken(tokens.RPAREN) {
+		p.readToken() // land on right paren
+	} else {
+		for {
+			p.readToken() // consume left paren or comma
+
+			param := p.parseTypeAnnotation()
+			if param == nil {
+				return nil
+			}
+			annotation.Params = append(annotation.Params, param)
+
+			if !p.isNextToken(tokens.COMMA) {
+				break
+			}
+			p.readToken() // land on comma
+		}
+
+		if !p.checkAndReadToken(tokens.RPAREN) {
+			return nil
+		}
+	}
+
+	if p.isNextToken(tokens.COLON) {
+		annotation.Return = p.parseOptionalTypeAnnotation()
+		if annotation.Return == nil {
+			return nil
+		}
+	}
+
+	return annotation
+}
+
+// parses "(<type>, <type>, ...)". a single parenthesized type is only grouped i.e. "(fn(): int)?"
+func (p *Parser) parseTupleTypeAnnotation() ast.TypeAnnotation {
+	annotation := &ast.TupleTypeAnnotation{
+		Token:    p.currToken,
+		Elements: []ast.TypeAnnotation{},
+	}
+
+	for {
+		p.readToken() // consume left paren or comma
+
+		element := p.parseTypeAnnotation()
+		if element == nil {
+			return nil
+		}
+		annotation.Elements = append(annotation.Elements, element)
+
+		if !p.isNextToken(tokens.COMMA) {
+			break
+		}
+		p.readToken() // land on comma
+	}
+
+	if !p.checkAndReadToken(tokens.RPAREN) {
+		return nil
+	}
+
+	if len(annotation.Elements) == 1 {
+		return annotation.Elements[0]
+	}
+
+	return annotation
+}
+
+func (p *Parser) parseListTypeAnnotation() ast.TypeAnnotation {
+	annotation := &ast.ListTypeAnnotation{
+		Token: p.currToken,
+	}
+
+	p.readToken() // land on right square bracket
+	p.readToken() // consume right square bracket
+
+	annotation.Element = p.parseTypeAnnotation()
+	if annotation.Element == nil {
+		return nil
+	}
+
+	return annotation
+}
+
+func (p *Parser) parseTypeAnnotation() ast.TypeAnnotation {
+	annotation := p.parseNonOptionalTypeAnnotation()
+	if annotation == nil || !p.isNextToken(tokens.QUESTION) {
+		return annotation
+	}
+
+	p.readToken()
+
+	return &ast.OptionalTypeAnnotation{
+		Token: p.currToken,
+		Inner: annotation,
+	}
+}
+
+func (p *Parser) parseNonOptionalTypeAnnotation() ast.TypeAnnotation {
+	if p.isCurrentToken(tokens.LSQUARE) {
+		return p.parseArrayTypeAnnotation()
+	}
+
+	if p.isCurrentToken(tokens.MAP) {
+		return p.parseMapTypeAnnotation()
+	}
+
+	if p.isCurrentToken(tokens.FUNCTION) {
+		return p.parseFunctionTypeAnnotation()
+	}
+
+	if p.isCurrentToken(tokens.LPAREN) {
+		return p.parseTupleTypeAnnotation()
+	}
+
+	if !p.isCurrentToken(tokens.IDENTIFIER) {
+		p.addError(utils.ParserExpectedCurrentTokenToBeErrorBuilder(p.currToken, tokens.IDENTIFIER))
+		return nil
+	}
+
+	if p.currToken.Literal == "Result" && p.isNextToken(tokens.LESS_THAN) {
+		return p.parseResultTypeAnnotation()
+	}
+
+	return &ast.NamedTypeAnnotation{
+		Token: p.currToken,
+		Name:  p.currToken.Literal,
+	}
+}
+
+// parses the optional ": <type>" suffix after the current token
+func (p *Parser) parseOptionalTypeAnnotation() ast.TypeAnnotation {
+	if !p.isNextToken(tokens.COLON) {
+		return nil
+	}
+
+	p.readToken() // land on colon
+	p.readToken() // consume colon
+
+	return p.parseTypeAnnotation()
+}
+
+func (p *Parser) parseFunctionParameter() *ast.FunctionParameter {
+	if !p.isCurrentToken(tokens.IDENTIFIER) {
+		p.addError(utils.ParserExpectedCurrentTokenToBeErrorBuilder(p.currToken, tokens.IDENTIFIER))
+		return nil
+	}
+
+	param := &ast.FunctionParameter{
+		Identifier: &ast.IdentifierExpression{
+			Token:   p.currToken,
+			Literal: p.currToken.Literal,
+		},
+	}
+	if p.isNextToken(tokens.COLON) {
+		param.Annotation = p.parseOptionalTypeAnnotation()
+		if param.Annotation == nil {
+			return nil
+		}
+	}
+
+	return param
+}
+
+func (p *Parser) parseFunctionParameters() []*ast.FunctionParameter {
+	parameters := []*ast.FunctionParameter{}
+
+	if p.isNextToken(tokens.RPAREN) {
+		p.readToken() // consume left paren
+		return parameters
+	}
+
+	p.readToken()
+
+	param := p.parseFunctionParameter()
+	if param == nil {
+		return nil
+	}
+	parameters = append(parameters, param)
+
+	for p.isNextToken(tokens.COMMA) {
+		p.readToken() // consume previous parameter
+		p.readToken() // consume comma
+
+		param := p.parseFunctionParameter()
+		if param == nil {
+			return nil
+		}
+		parameters = append(parameters, param)
+	}
+
+	if !p.checkAndReadToken(tokens.RPAREN) {
+		return nil
+	}
+
+	return parameters
+}
+
+// parses everything after the fn keyword (and the name, in case of function statements)
+func (p *Parser) parseFunctionSignatureAndBody(expr *ast.FunctionExpression) *ast.FunctionExpression {
+	if !p.checkAndReadToken(tokens.LPAREN) {
+		return nil
+	}
+
+	expr.Parameters = p.parseFunctionParameters()
+	if expr.Parameters == nil {
+		return nil
+	}
+
+	if p.isNextToken(tokens.COLON) {
+		expr.ReturnAnnotation = p.parseOptionalTypeAnnotation()
+		if expr.ReturnAnnotation == nil {
+			return nil
+		}
+	}
+
+	if !p.checkAndReadToken(tokens.LBRACE) {
+		return nil
+	}
+	expr.Body = p.parseBlockStatement()
+
+	return expr
+}
+
+func (p *Parser) parseFunctionExpression() ast.Expression {
+	expr := p.parseFunctionSignatureAndBody(&ast.FunctionExpression{
+		Token: p.currToken,
+	})
+	if expr == nil {
+		return nil
+	}
+
+	return expr
+}
+
+func (p *Parser) parseCallArguments() []ast.Expression {
+	return p.parseExpressionList(tokens.RPAREN)
+}
+
+// parses comma separated expressions until the end token, starting at the token which opens the list
+func (p *Parser) parseExpressionList(end tokens.TokenType) []ast.Expression {
+	list := []ast.Expression{}
+
+	if p.isNextToken(end) {
+		p.readToken() // consume opening token
+		return list
+	}
+
+	p.readToken()
+
+	list = append(list, p.parseExpression(LOWEST))
+
+	for p.isNextToken(tokens.COMMA) {
+		p.readToken() // consume previous expression
+		p.readToken() // consume comma
+
+		list = append(list, p.parseExpression(LOWEST))
+	}
+
+	if !p.checkAndReadToken(end) {
+		return nil
+	}
+
+	return list
+}
+
+func (p *Parser) parseArrayExpression() ast.Expression {
+	expr := &ast.ArrayExpression{
+		Token: p.currToken,
+	}
+
+	expr.Elements = p.parseExpressionList(tokens.RSQUARE)
+	if expr.Elements == nil || slices.Contains(expr.Elements, nil) {
+		return nil
+	}
+
+	return expr
+}
+
+func (p *Parser) parseFieldExpression(left ast.Expression) ast.Expression {
+	expr := &ast.FieldExpression{
+		Token: p.currToken,
+		Left:  left,
+	}
+
+	// tuple elements are accessed by their position i.e. "t.0"
+	if p.isNextToken(tokens.INTEGER) {
+		p.readToken()
+	} else if !p.checkAndReadToken(tokens.IDENTIFIER) {
+		return nil
+	}
+
+	expr.Field = &ast.IdentifierExpression{
+		Token:   p.currToken,
+		Literal: p.currToken.Literal,
+	}
+
+	return expr
+}
+
+func (p *Parser) parseMapExpression() ast.Expression {
+	expr := &ast.MapExpression{
+		Token:  p.currToken,
+		Keys:   []ast.Expression{},
+		Values: []ast.Expression{},
+	}
+
+	if p.isNextToken(tokens.RBRACE) {
+		p.readToken() // land on right brace
+		return expr
+	}
+
+	for {
+		p.readToken()
+
+		key := p.parseExpression(LOWEST)
+		if key == nil {
+			return nil
+		}
+
+		if !p.checkAndReadToken(tokens.COLON) {
+			return nil
+		}
+		colonToken := p.currToken
+		p.readToken()
+
+		value := p.parseExpression(LOWEST)
+		if value == nil {
+			p.addError(utils.ParserExpressionExpectedErrorBuilder(colonToken))
+			return nil
+		}
+
+		expr.Keys = append(expr.Keys, key)
+		expr.Values = append(expr.Values, value)
+
+		if !p.isNextToken(tokens.COMMA) {
+			break
+		}
+		p.readToken() // land on comma
+	}
+
+	if !p.checkAndReadToken(tokens.RBRACE) {
+		return nil
+	}
+
+	return expr
+}
+
+func (p *Parser) parseIndexExpression(left ast.Expression) ast.Expression {
+	expr := &ast.IndexExpression{
+		Token: p.currToken,
+		Left:  left,
+	}
+
+	p.readToken()
+
+	expr.Index = p.parseExpression(LOWEST)
+	if expr.Index == nil {
+		p.addError(utils.ParserExpressionExpectedErrorBuilder(expr.Token))
+		return nil
+	}
+
+	if !p.checkAndReadToken(tokens.RSQUARE) {
+		return nil
+	}
+
+	return expr
+}
+
+func (p *Parser) parseCallExpression(left ast.Expression) ast.Expression {
+	expr := &ast.CallExpression{
+		Token: p.currToken,
+	}
+
+	// named functions and builtins are called through their identifier, anything else is called as a function value
+	if identifier, ok := left.(*ast.IdentifierExpression); ok {
+		expr.Identifier = identifier
+	} else {
+		expr.Callee = left
+	}
+
+	expr.Arguments = p.parseCallArguments()
+
+	return expr
+}
+
+func (p *Parser) parseBinaryExpression(left ast.Expression) ast.Expression {
+	expr := &ast.BinaryExpression{
+		Left:     left,
+		Operator: p.currToken,
 	}
 
 	precedence := p.currentPrecedence()
@@ -452,6 +1211,63 @@ func (p *Parser) parseExpression(precedence int) ast.Expression {
 	return expr
 }
 
+func (p *Parser) parseDestructuringStatement() *ast.DestructuringStatement {
+	stmt := &ast.DestructuringStatement{
+		Token:       p.currToken,
+		Identifiers: []*ast.IdentifierExpression{},
+	}
+
+	if !p.checkAndReadToken(tokens.LPAREN) {
+		return nil
+	}
+
+	for {
+		if !p.checkAndReadToken(tokens.IDENTIFIER) {
+			return nil
+		}
+
+		stmt.Identifiers = append(stmt.Identifiers, &ast.IdentifierExpression{
+			Token:   p.currToken,
+			Literal: p.currToken.Literal,
+		})
+
+		if !p.isNextToken(tokens.COMMA) {
+			break
+		}
+		p.readToken() // land on comma
+	}
+
+	if !p.checkAndReadToken(tokens.RPAREN) {
+		return nil
+	}
+
+	if p.isNextToken(tokens.COLON) {
+		stmt.Annotation = p.parseOptionalTypeAnnotation()
+		if stmt.Annotation == nil {
+			return nil
+		}
+	}
+
+	if !p.checkAndReadToken(tokens.ASSIGN) {
+		return nil
+	}
+
+	assignToken := p.currToken
+	p.readToken()
+
+	stmt.Value = p.parseExpression(LOWEST)
+	if stmt.Value == nil {
+		p.addError(utils.ParserExpressionExpectedErrorBuilder(assignToken))
+		return nil
+	}
+
+	if p.isNextToken(tokens.SEMICOLON) {
+		p.readToken()
+	}
+
+	return stmt
+}
+
 func (p *Parser) parseLetStatement() *ast.LetStatement {
 	stmt := &ast.LetStatement{
 		Token: p.currToken,
@@ -466,24 +1282,157 @@ func (p *Parser) parseLetStatement() *ast.LetStatement {
 		Literal: p.currToken.Literal,
 	}
 
-	if !p.checkAndReadToken(tokens.ASSIGN) {
+	if p.isNextToken(tokens.COLON) {
+		stmt.Annotation = p.parseOptionalTypeAnnotation()
+		if stmt.Annotation == nil {
+			return nil
+		}
+
+		// annotated declaration without an initializer
+		if !p.isNextToken(tokens.ASSIGN) {
+			if p.isNextToken(tokens.SEMICOLON) {
+				p.readToken()
+			}
+
+			return stmt
+		}
+	}
+
+	if !p.checkAndReadToken(tokens.ASSIGN) {
+		return nil
+	}
+
+	assignToken := p.currToken
+	p.readToken()
+
+	stmt.Value = p.parseExpression(LOWEST)
+	if stmt.Value == nil {
+		p.addError(utils.ParserExpressionExpectedErrorBuilder(assignToken))
+		return nil
+	}
+
+	if p.isNextToken(tokens.SEMICOLON) {
+		p.readToken()
+	}
+
+	return stmt
+}
+
+func (p *Parser) parseConstStatement() *ast.ConstStatement {
+	stmt := &ast.ConstStatement{
+		Token: p.currToken,
+	}
+
+	if !p.checkAndReadToken(tokens.IDENTIFIER) {
+		return nil
+	}
+
+	stmt.Identifier = &ast.IdentifierExpression{
+		Token:   p.currToken,
+		Literal: p.currToken.Literal,
+	}
+
+	if p.isNextToken(tokens.COLON) {
+		stmt.Annotation = p.parseOptionalTypeAnnotation()
+		if stmt.Annotation == nil {
+			return nil
+		}
+	}
+
+	if !p.checkAndReadToken(tokens.ASSIGN) {
+		return nil
+	}
+
+	assignToken := p.currToken
+	p.readToken()
+
+	stmt.Value = p.parseExpression(LOWEST)
+	if stmt.Value == nil {
+		p.addError(utils.ParserExpressionExpectedErrorBuilder(assignToken))
+		return nil
+	}
+
+	if p.isNextToken(tokens.SEMICOLON) {
+		p.readToken()
+	}
+
+	return stmt
+}
+
+func (p *Parser) parseFunctionStatement() *ast.FunctionStatement {
+	stmt := &ast.FunctionStatement{
+		Token: p.currToken,
+	}
+
+	if !p.checkAndReadToken(tokens.IDENTIFIER) {
+		return nil
+	}
+
+	stmt.Identifier = &ast.IdentifierExpression{
+		Token:   p.currToken,
+		Literal: p.currToken.Literal,
+	}
+
+	if p.isNextToken(tokens.LESS_THAN) {
+		p.readToken()
+
+		stmt.TypeParameters = p.parseTypeParameters()
+		if stmt.TypeParameters == nil {
+			return nil
+		}
+	}
+
+	stmt.Function = p.parseFunctionSignatureAndBody(&ast.FunctionExpression{
+		Token: stmt.Token,
+	})
+	if stmt.Function == nil {
 		return nil
 	}
 
-	assignToken := p.currToken
-	p.readToken()
+	return stmt
+}
 
-	stmt.Value = p.parseExpression(LOWEST)
-	if stmt.Value == nil {
-		p.addError(utils.ParserExpressionExpectedErrorBuilder(assignToken))
-		return nil
-	}
+// parses the type parameters of a generic function, starting at the opening angle bracket
+func (p *Parser) parseTypeParameters() []*ast.TypeParameter {
+	typeParams := []*ast.TypeParameter{}
 
-	if p.isNextToken(tokens.SEMICOLON) {
+	for {
+		if !p.checkAndReadToken(tokens.IDENTIFIER) {
+			return nil
+		}
+
+		typeParam := &ast.TypeParameter{
+			Identifier: &ast.IdentifierExpression{
+				Token:   p.currToken,
+				Literal: p.currToken.Literal,
+			},
+		}
+
+		if p.isNextToken(tokens.COLON) {
+			p.readToken()
+			if !p.checkAndReadToken(tokens.IDENTIFIER) {
+				return nil
+			}
+
+			typeParam.Constraint = &ast.IdentifierExpression{
+				Token:   p.currToken,
+				Literal: p.currToken.Literal,
+			}
+		}
+
+		typeParams = append(typeParams, typeParam)
+
+		if !p.isNextToken(tokens.COMMA) {
+			break
+		}
 		p.readToken()
 	}
 
-	return stmt
+	if !p.checkAndReadToken(tokens.GREATER_THAN) {
+		return nil
+	}
+
+	return typeParams
 }
 
 func (p *Parser) parseAssignmentStatement() *ast.AssignmentStatement {
@@ -495,11 +1444,15 @@ func (p *Parser) parseAssignmentStatement() *ast.AssignmentStatement {
 		},
 	}
 
-	if !p.checkAndReadToken(tokens.ASSIGN) {
+	// compound assignments (+=, -=, *=, /=) share the same form as plain assignments
+	if _, isCompound := tokens.COMPOUND_ASSIGNMENT_OPERATORS[p.nextToken.Type]; isCompound {
+		p.readToken()
+	} else if !p.checkAndReadToken(tokens.ASSIGN) {
 		return nil
 	}
 
 	assignToken := p.currToken
+	stmt.Operator = assignToken
 	p.readToken()
 
 	stmt.Value = p.parseExpression(LOWEST)
@@ -520,6 +1473,16 @@ func (p *Parser) parseReturnStatement() *ast.ReturnStatement {
 		Token: p.currToken,
 	}
 	returnToken := p.currToken
+
+	// bare return, used in functions without a return type
+	if p.isNextToken(tokens.SEMICOLON) || p.isNextToken(tokens.RBRACE) {
+		if p.isNextToken(tokens.SEMICOLON) {
+			p.readToken()
+		}
+
+		return stmt
+	}
+
 	p.readToken()
 
 	stmt.Expr = p.parseExpression(LOWEST)
@@ -559,6 +1522,46 @@ func (p *Parser) parseWhileStatement() *ast.WhileStatement {
 	return stmt
 }
 
+// for (<identifier> in ...) and for (<initialization>; ...) can only be told apart by the token after the identifier
+func (p *Parser) isForInStatement() bool {
+	return p.isNextToken(tokens.LPAREN) && p.peekTokenAt(1).Type == tokens.IDENTIFIER && p.peekTokenAt(2).Type == tokens.IN
+}
+
+func (p *Parser) parseForInStatement() *ast.ForInStatement {
+	stmt := &ast.ForInStatement{
+		Token: p.currToken,
+	}
+
+	p.readToken() // land on left paren
+	p.readToken() // land on identifier
+
+	stmt.Identifier = &ast.IdentifierExpression{
+		Token:   p.currToken,
+		Literal: p.currToken.Literal,
+	}
+
+	p.readToken() // land on in
+	inToken := p.currToken
+	p.readToken()
+
+	stmt.Iterable = p.parseExpression(LOWEST)
+	if stmt.Iterable == nil {
+		p.addError(utils.ParserExpressionExpectedErrorBuilder(inToken))
+		return nil
+	}
+
+	if !p.checkAndReadToken(tokens.RPAREN) {
+		return nil
+	}
+
+	if !p.checkAndReadToken(tokens.LBRACE) {
+		return nil
+	}
+
+	stmt.Body = p.parseBlockStatement()
+	return stmt
+}
+
 func (p *Parser) parseForStatement() *ast.ForStatement {
 	stmt := &ast.ForStatement{
 		Token: p.currToken,
@@ -591,10 +1594,13 @@ func (p *Parser) parseForStatement() *ast.ForStatement {
 	p.readToken()
 
 	if !p.isCurrentToken(tokens.RPAREN) {
-		// if update expression is not empty, then parse it
-		stmt.Update = p.parseExpression(LOWEST)
-		// consume right paren token
-		p.readToken()
+		// if update statement is not empty, then parse it
+		stmt.Update = p.parseStatement()
+
+		// land on right paren token
+		if !p.checkAndReadToken(tokens.RPAREN) {
+			return nil
+		}
 	}
 
 	if !p.checkAndReadToken(tokens.LBRACE) {
@@ -605,6 +1611,89 @@ func (p *Parser) parseForStatement() *ast.ForStatement {
 	return stmt
 }
 
+// parses the optional label after break and continue keywords, which must be on the same line
+func (p *Parser) parseOptionalLabel() *ast.IdentifierExpression {
+	if !p.isNextToken(tokens.IDENTIFIER) || p.peekToken().Line != p.currToken.Line {
+		return nil
+	}
+
+	p.readToken()
+
+	return &ast.IdentifierExpression{
+		Token:   p.currToken,
+		Literal: p.currToken.Literal,
+	}
+}
+
+func (p *Parser) parseBreakStatement() *ast.BreakStatement {
+	stmt := &ast.BreakStatement{
+		Token: p.currToken,
+	}
+	stmt.Label = p.parseOptionalLabel()
+
+	if p.isNextToken(tokens.SEMICOLON) {
+		p.readToken()
+	}
+
+	return stmt
+}
+
+func (p *Parser) parseContinueStatement() *ast.ContinueStatement {
+	stmt := &ast.ContinueStatement{
+		Token: p.currToken,
+	}
+	stmt.Label = p.parseOptionalLabel()
+
+	if p.isNextToken(tokens.SEMICOLON) {
+		p.readToken()
+	}
+
+	return stmt
+}
+
+// <label>: <loop statement>
+func (p *Parser) parseLabeledStatement() ast.Statement {
+	label := &ast.IdentifierExpression{
+		Token:   p.currToken,
+		Literal: p.currToken.Literal,
+	}
+
+	p.readToken() // land on colon
+	p.readToken() // consume colon
+
+	switch p.currToken.Type {
+	case tokens.WHILE:
+		stmt := p.parseWhileStatement()
+		if stmt == nil {
+			return nil
+		}
+
+		stmt.Label = label
+		return stmt
+	case tokens.FOR:
+		if p.isForInStatement() {
+			stmt := p.parseForInStatement()
+			if stmt == nil {
+				return nil
+			}
+
+			stmt.Label = label
+			return stmt
+		}
+
+		stmt := p.parseForStatement()
+		if stmt == nil {
+			return nil
+		}
+
+		stmt.Label = label
+		return stmt
+	default:
+		p.addError(utils.ParserErrorBuilder(p.currToken, "only loops can be labeled"))
+		return nil
+	}
+}
+
 func (p *Parser) parseBlockStatement() *ast.BlockStatement {
 	block := &ast.BlockStatement{
 		Token: p.currToken,
@@ -624,12 +1713,70 @@ func (p *Parser) parseBlockStatement() *ast.BlockStatement {
 	return block
 }
 
-func (p *Parser) parseExpressionStatement() *ast.ExpressionStatement {
+func (p *Parser) parseExpressionStatement() ast.Statement {
 	stmt := &ast.ExpressionStatement{
 		Token: p.currToken,
 	}
 	stmt.Expr = p.parseExpression(LOWEST)
 
+	// assignment target is only known to be an index or field expression after it is parsed
+	if p.isNextAssignmentOperator() {
+		switch target := stmt.Expr.(type) {
+		case *ast.IndexExpression:
+			return p.parseIndexAssignmentStatement(target)
+		case *ast.FieldExpression:
+			return p.parseFieldAssignmentStatement(target)
+		}
+	}
+
+	if p.isNextToken(tokens.SEMICOLON) {
+		p.readToken()
+	}
+
+	return stmt
+}
+
+func (p *Parser) parseIndexAssignmentStatement(target *ast.IndexExpression) ast.Statement {
+	p.readToken()
+
+	stmt := &ast.IndexAssignmentStatement{
+		Token:    target.Token,
+		Target:   target,
+		Operator: p.currToken,
+	}
+
+	p.readToken()
+
+	stmt.Value = p.parseExpression(LOWEST)
+	if stmt.Value == nil {
+		p.addError(utils.ParserExpressionExpectedErrorBuilder(stmt.Operator))
+		return nil
+	}
+
+	if p.isNextToken(tokens.SEMICOLON) {
+		p.readToken()
+	}
+
+	return stmt
+}
+
+func (p *Parser) parseFieldAssignmentStatement(target *ast.FieldExpression) ast.Statement {
+	p.readToken()
+
+	stmt := &ast.FieldAssignmentStatement{
+		Token:    target.Token,
+		Target:   target,
+		Operator: p.currToken,
+	}
+
+	p.readToken()
+
+	stmt.Value = p.parseExpression(LOWEST)
+	if stmt.Value == nil {
+		p.addError(utils.ParserExpressionExpectedErrorBuilder(stmt.Operator))
+		return nil
+	}
+
 	if p.isNextToken(tokens.SEMICOLON) {
 		p.readToken()
 	}
@@ -637,23 +1784,184 @@ func (p *Parser) parseExpressionStatement() *ast.ExpressionStatement {
 	return stmt
 }
 
+// enum <identifier> { <variant>(<type>, ...), <variant>, ... }
+func (p *Parser) parseEnumStatement() ast.Statement {
+	stmt := &ast.EnumStatement{
+		Token:    p.currToken,
+		Variants: []*ast.EnumVariant{},
+	}
+
+	if !p.checkAndReadToken(tokens.IDENTIFIER) {
+		return nil
+	}
+
+	stmt.Identifier = &ast.IdentifierExpression{
+		Token:   p.currToken,
+		Literal: p.currToken.Literal,
+	}
+
+	if !p.checkAndReadToken(tokens.LBRACE) {
+		return nil
+	}
+
+	for !p.isNextToken(tokens.RBRACE) {
+		if !p.checkAndReadToken(tokens.IDENTIFIER) {
+			return nil
+		}
+
+		variant := &ast.EnumVariant{
+			Identifier: &ast.IdentifierExpression{
+				Token:   p.currToken,
+				Literal: p.currToken.Literal,
+			},
+			Payload: []ast.TypeAnnotation{},
+		}
+
+		if p.isNextToken(tokens.LPAREN) {
+			p.readToken() // land on left paren
+
+			for {
+				p.readToken()
+
+				annotation := p.parseTypeAnnotation()
+				if annotation == nil {
+					return nil
+				}
+				variant.Payload = append(variant.Payload, annotation)
+
+				if !p.isNextToken(tokens.COMMA) {
+					break
+				}
+				p.readToken() // land on comma
+			}
+
+			if !p.checkAndReadToken(tokens.RPAREN) {
+				return nil
+			}
+		}
+
+		stmt.Variants = append(stmt.Variants, variant)
+
+		if !p.isNextToken(tokens.COMMA) {
+			break
+		}
+		p.readToken() // land on comma
+	}
+
+	if !p.checkAndReadToken(tokens.RBRACE) {
+		return nil
+	}
+
+	return stmt
+}
+
+// struct <identifier> { <field>: <type>, <field>: <type>, ... }
+func (p *Parser) parseStructStatement() ast.Statement {
+	stmt := &ast.StructStatement{
+		Token:  p.currToken,
+		Fields: []*ast.StructField{},
+	}
+
+	if !p.checkAndReadToken(tokens.IDENTIFIER) {
+		return nil
+	}
+
+	stmt.Identifier = &ast.IdentifierExpression{
+		Token:   p.currToken,
+		Literal: p.currToken.Literal,
+	}
+
+	if !p.checkAndReadToken(tokens.LBRACE) {
+		return nil
+	}
+
+	for !p.isNextToken(tokens.RBRACE) {
+		if !p.checkAndReadToken(tokens.IDENTIFIER) {
+			return nil
+		}
+
+		field := &ast.StructField{
+			Identifier: &ast.IdentifierExpression{
+				Token:   p.currToken,
+				Literal: p.currToken.Literal,
+			},
+		}
+
+		if !p.checkAndReadToken(tokens.COLON) {
+			return nil
+		}
+		p.readToken() // consume colon
+
+		field.Annotation = p.parseTypeAnnotation()
+		if field.Annotation == nil {
+			return nil
+		}
+
+		stmt.Fields = append(stmt.Fields, field)
+
+		if !p.isNextToken(tokens.COMMA) {
+			break
+		}
+		p.readToken() // land on comma
+	}
+
+	if !p.checkAndReadToken(tokens.RBRACE) {
+		return nil
+	}
+
+	return stmt
+}
+
+func (p *Parser) isNextAssignmentOperator() bool {
+	_, isCompound := tokens.COMPOUND_ASSIGNMENT_OPERATORS[p.nextToken.Type]
+	return isCompound || p.isNextToken(tokens.ASSIGN)
+}
+
 func (p *Parser) parseStatement() ast.Statement {
 	switch p.currToken.Type {
 	case tokens.LET:
+		if p.isNextToken(tokens.LPAREN) {
+			return p.parseDestructuringStatement()
+		}
+
 		return p.parseLetStatement()
+	case tokens.CONST:
+		return p.parseConstStatement()
+	case tokens.FUNCTION:
+		if p.isNextToken(tokens.IDENTIFIER) {
+			return p.parseFunctionStatement()
+		}
+
+		return p.parseExpressionStatement()
 	case tokens.RETURN:
 		return p.parseReturnStatement()
 	case tokens.WHILE:
 		return p.parseWhileStatement()
 	case tokens.FOR:
+		if p.isForInStatement() {
+			return p.parseForInStatement()
+		}
+
 		return p.parseForStatement()
+	case tokens.BREAK:
+		return p.parseBreakStatement()
+	case tokens.CONTINUE:
+		return p.parseContinueStatement()
 	case tokens.LBRACE:
 		return p.parseBlockStatement()
+	case tokens.STRUCT:
+		return p.parseStructStatement()
+	case tokens.ENUM:
+		return p.parseEnumStatement()
 	case tokens.IDENTIFIER:
-		if p.isNextToken(tokens.ASSIGN) {
+		if p.isNextAssignmentOperator() {
 			return p.parseAssignmentStatement()
 		}
 
+		if p.isNextToken(tokens.COLON) {
+			return p.parseLabeledStatement()
+		}
+
 		return p.parseExpressionStatement()
 	default:
 		return p.parseExpressionStatement()
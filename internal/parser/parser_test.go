@@ -21,6 +21,9 @@ func TestParser_Literals(t *testing.T) {
 		newParserTest("string literal newline", `"hello\nworld"`, newAstBuilder().addStringLiteralExpression(`"hello\nworld"`).toProgram()),
 		newParserTest("string literal tab", `"hello\tworld"`, newAstBuilder().addStringLiteralExpression(`"hello\tworld"`).toProgram()),
 		newParserTest("string literal escape newline", `"hello\\nworld"`, newAstBuilder().addStringLiteralExpression(`"hello\\nworld"`).toProgram()),
+		newParserTest("char literal simple", `'a'`, newAstBuilder().addCharLiteralExpression('a').toProgram()),
+		newParserTest("char literal escape", `'\n'`, newAstBuilder().addCharLiteralExpression('\n').toProgram()),
+		newParserTest("char literal multi-byte", `'€'`, newAstBuilder().addCharLiteralExpression('€').toProgram()),
 	}
 
 	for _, tt := range tests {
@@ -73,6 +76,22 @@ func TestParser_UnaryExpressions(t *testing.T) {
 			).toProgram(),
 		),
 		newParserTest("single minus", "-5", newAstBuilder().addUnaryExpression(tokens.NewMinimal(tokens.MINUS, "-"), ast.NewIntegerExpr(5)).toProgram()),
+		newParserTest("prefix increment", "++x", newAstBuilder().addUnaryExpression(tokens.NewMinimal(tokens.INCREMENT, "++"), ast.NewIdentifierExpr("x")).toProgram()),
+		newParserTest(
+			"postfix increment",
+			"x++",
+			newAstBuilder().addStatement(&ast.ExpressionStatement{
+				Expr: ast.NewPostfixExpr(tokens.NewMinimal(tokens.INCREMENT, "++"), ast.NewIdentifierExpr("x")),
+			}).toProgram(),
+		),
+		newParserTest(
+			"minus postfix decrement",
+			"-x--",
+			newAstBuilder().addUnaryExpression(
+				tokens.NewMinimal(tokens.MINUS, "-"),
+				ast.NewPostfixExpr(tokens.NewMinimal(tokens.DECREMENT, "--"), ast.NewIdentifierExpr("x")),
+			).toProgram(),
+		),
 		newParserTestFail("invalid unary bang", "!", expectParseFailure("expression expected after ! token")),
 		newParserTestFail("invalid unary minus", "-", expectParseFailure("expression expected after - token")),
 	}
@@ -171,6 +190,16 @@ func TestParser_Precedence(t *testing.T) {
 				ast.NewBinaryExpr(tokens.NewMinimal(tokens.NOT_EQUALS, "!="), ast.NewIntegerExpr(3), ast.NewIntegerExpr(2)),
 			).toProgram(),
 		),
+		// a || b && c == a || (b && c)
+		newParserTest(
+			"logical or + logical and",
+			"a || b && c",
+			newAstBuilder().addBinaryExpression(
+				tokens.NewMinimal(tokens.OR, "||"),
+				ast.NewIdentifierExpr("a"),
+				ast.NewBinaryExpr(tokens.NewMinimal(tokens.AND, "&&"), ast.NewIdentifierExpr("b"), ast.NewIdentifierExpr("c")),
+			).toProgram(),
+		),
 		// -5 + 3 = (-5) + 3
 		newParserTest(
 			"unary + binary",
@@ -304,3 +333,770 @@ func TestParser_IfExpressions(t *testing.T) {
 		})
 	}
 }
+
+func TestParser_FunctionStatements(t *testing.T) {
+	tests := []parserTestItem{
+		newParserTest(
+			"no params",
+			"fn hello() { return; }",
+			newAstBuilder().addStatement(
+				ast.NewFunctionStmt("hello", []*ast.FunctionParameter{}, nil, []ast.Statement{ast.NewReturnStmt(nil)}),
+			).toProgram(),
+		),
+		newParserTest(
+			"typed params and return type",
+			"fn add(a: int, b: int): int { return a + b; }",
+			newAstBuilder().addStatement(
+				ast.NewFunctionStmt(
+					"add",
+					[]*ast.FunctionParameter{
+						ast.NewFunctionParam("a", ast.NewNamedTypeAnnotation("int")),
+						ast.NewFunctionParam("b", ast.NewNamedTypeAnnotation("int")),
+					},
+					ast.NewNamedTypeAnnotation("int"),
+					[]ast.Statement{
+						ast.NewReturnStmt(ast.NewBinaryExpr(tokens.NewMinimal(tokens.PLUS, "+"), ast.NewIdentifierExpr("a"), ast.NewIdentifierExpr("b"))),
+					},
+				),
+			).toProgram(),
+		),
+		newParserTestFail(
+			"missing param type",
+			"fn add(a: int, b:",
+			expectParseFailure("expected type of current token to be IDENTIFIER, got EOF instead"),
+		),
+		newParserTestFail(
+			"missing body",
+			"fn add(a: int): int",
+			expectParseFailure("expected type of next token to be {, got EOF instead"),
+		),
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			runParserTest(t, tt)
+		})
+	}
+}
+
+func TestParser_Closures(t *testing.T) {
+	tests := []parserTestItem{
+		newParserTest(
+			"function literal with function type annotation",
+			"let inc: fn(int): int = fn(x: int): int { return x + 1; };",
+			newAstBuilder().addStatement(
+				ast.NewLetStmt(
+					"inc",
+					ast.NewFunctionTypeAnnotation([]ast.TypeAnnotation{ast.NewNamedTypeAnnotation("int")}, ast.NewNamedTypeAnnotation("int")),
+					ast.NewFunctionExpr(
+						[]*ast.FunctionParameter{ast.NewFunctionParam("x", ast.NewNamedTypeAnnotation("int"))},
+						ast.NewNamedTypeAnnotation("int"),
+						[]ast.Statement{
+							ast.NewReturnStmt(ast.NewBinaryExpr(tokens.NewMinimal(tokens.PLUS, "+"), ast.NewIdentifierExpr("x"), ast.NewIntegerExpr(1))),
+						},
+					),
+				),
+			).toProgram(),
+		),
+		newParserTest(
+			"function returning function",
+			"fn adder(n: int): fn(int): int { return f; }",
+			newAstBuilder().addStatement(
+				ast.NewFunctionStmt(
+					"adder",
+					[]*ast.FunctionParameter{ast.NewFunctionParam("n", ast.NewNamedTypeAnnotation("int"))},
+					ast.NewFunctionTypeAnnotation([]ast.TypeAnnotation{ast.NewNamedTypeAnnotation("int")}, ast.NewNamedTypeAnnotation("int")),
+					[]ast.Statement{ast.NewReturnStmt(ast.NewIdentifierExpr("f"))},
+				),
+			).toProgram(),
+		),
+		newParserTest(
+			"void function type",
+			"let f: fn();",
+			newAstBuilder().addStatement(
+				ast.NewLetStmt("f", ast.NewFunctionTypeAnnotation([]ast.TypeAnnotation{}, nil), nil),
+			).toProgram(),
+		),
+		newParserTest(
+			"call through non-identifier callees",
+			"adder(1)(2); ops[0](a, b); s.run();",
+			newAstBuilder().
+				addStatement(&ast.ExpressionStatement{
+					Expr: ast.NewCallExpr(ast.NewCallExpr(ast.NewIdentifierExpr("adder"), ast.NewIntegerExpr(1)), ast.NewIntegerExpr(2)),
+				}).
+				addStatement(&ast.ExpressionStatement{
+					Expr: ast.NewCallExpr(ast.NewIndexExpr(ast.NewIdentifierExpr("ops"), ast.NewIntegerExpr(0)), ast.NewIdentifierExpr("a"), ast.NewIdentifierExpr("b")),
+				}).
+				addStatement(&ast.ExpressionStatement{
+					Expr: ast.NewCallExpr(ast.NewFieldExpr(ast.NewIdentifierExpr("s"), "run")),
+				}).
+				toProgram(),
+		),
+		newParserTestFail("unterminated function type", "let f: fn(int", expectParseFailure("expected type of next token to be ), got EOF instead")),
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			runParserTest(t, tt)
+		})
+	}
+}
+
+func TestParser_Generics(t *testing.T) {
+	tests := []parserTestItem{
+		newParserTest(
+			"constrained type parameter",
+			"fn max<T: ordered>(a: T, b: T): T { return a; }",
+			newAstBuilder().addStatement(
+				ast.NewGenericFunctionStmt(
+					"max",
+					[]*ast.TypeParameter{ast.NewTypeParam("T", "ordered")},
+					[]*ast.FunctionParameter{
+						ast.NewFunctionParam("a", ast.NewNamedTypeAnnotation("T")),
+						ast.NewFunctionParam("b", ast.NewNamedTypeAnnotation("T")),
+					},
+					ast.NewNamedTypeAnnotation("T"),
+					[]ast.Statement{ast.NewReturnStmt(ast.NewIdentifierExpr("a"))},
+				),
+			).toProgram(),
+		),
+		newParserTest(
+			"multiple type parameters",
+			"fn apply<A, B: numeric>(x: A, f: fn(A): B): B { return f(x); }",
+			newAstBuilder().addStatement(
+				ast.NewGenericFunctionStmt(
+					"apply",
+					[]*ast.TypeParameter{ast.NewTypeParam("A", ""), ast.NewTypeParam("B", "numeric")},
+					[]*ast.FunctionParameter{
+						ast.NewFunctionParam("x", ast.NewNamedTypeAnnotation("A")),
+						ast.NewFunctionParam("f", ast.NewFunctionTypeAnnotation([]ast.TypeAnnotation{ast.NewNamedTypeAnnotation("A")}, ast.NewNamedTypeAnnotation("B"))),
+					},
+					ast.NewNamedTypeAnnotation("B"),
+					[]ast.Statement{ast.NewReturnStmt(ast.NewCallExpr(ast.NewIdentifierExpr("f"), ast.NewIdentifierExpr("x")))},
+				),
+			).toProgram(),
+		),
+		newParserTestFail("missing type parameter", "fn f<", expectParseFailure("expected type of next token to be IDENTIFIER, got EOF instead")),
+		newParserTestFail("unterminated type parameters", "fn f<T", expectParseFailure("expected type of next token to be >, got EOF instead")),
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			runParserTest(t, tt)
+		})
+	}
+}
+
+func TestParser_Optionals(t *testing.T) {
+	tests := []parserTestItem{
+		newParserTest(
+			"optional type annotation",
+			"let x: int? = none;",
+			newAstBuilder().addStatement(ast.NewLetStmt("x", ast.NewOptionalTypeAnnotation(ast.NewNamedTypeAnnotation("int")), ast.NewNoneExpr())).toProgram(),
+		),
+		newParserTest(
+			"optional element type",
+			"let xs: []string?;",
+			newAstBuilder().addStatement(ast.NewLetStmt("xs", ast.NewListTypeAnnotation(ast.NewOptionalTypeAnnotation(ast.NewNamedTypeAnnotation("string"))), nil)).toProgram(),
+		),
+		newParserTest(
+			"coalesce binds looser than arithmetic",
+			"x ?? 1 + 2",
+			newAstBuilder().addBinaryExpression(
+				tokens.NewMinimal(tokens.DOUBLE_QUESTION, "??"),
+				ast.NewIdentifierExpr("x"),
+				ast.NewBinaryExpr(tokens.NewMinimal(tokens.PLUS, "+"), ast.NewIntegerExpr(1), ast.NewIntegerExpr(2)),
+			).toProgram(),
+		),
+		newParserTest(
+			"coalesce binds tighter than comparison",
+			"x ?? 1 == 2",
+			newAstBuilder().addBinaryExpression(
+				tokens.NewMinimal(tokens.EQUALS, "=="),
+				ast.NewBinaryExpr(tokens.NewMinimal(tokens.DOUBLE_QUESTION, "??"), ast.NewIdentifierExpr("x"), ast.NewIntegerExpr(1)),
+				ast.NewIntegerExpr(2),
+			).toProgram(),
+		),
+		newParserTest(
+			"if let",
+			"if (let v = x) { v } else { 0 }",
+			newAstBuilder().addStatement(&ast.ExpressionStatement{
+				Expr: ast.NewIfLetExpr(
+					"v",
+					ast.NewIdentifierExpr("x"),
+					ast.WrapExprsAsStmts([]ast.Expression{ast.NewIdentifierExpr("v")}),
+					ast.WrapExprsAsStmts([]ast.Expression{ast.NewIntegerExpr(0)}),
+				),
+			}).toProgram(),
+		),
+		newParserTestFail("if let without binding", "if (let", expectParseFailure("expected type of next token to be IDENTIFIER, got EOF instead")),
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			runParserTest(t, tt)
+		})
+	}
+}
+
+func TestParser_Results(t *testing.T) {
+	tests := []parserTestItem{
+		newParserTest(
+			"result type annotation",
+			"let r: Result<[]int, string>;",
+			newAstBuilder().addStatement(
+				ast.NewLetStmt("r", ast.NewResultTypeAnnotation(ast.NewListTypeAnnotation(ast.NewNamedTypeAnnotation("int")), ast.NewNamedTypeAnnotation("string")), nil),
+			).toProgram(),
+		),
+		newParserTest(
+			"optional result",
+			"let r: Result<int, string>?;",
+			newAstBuilder().addStatement(
+				ast.NewLetStmt("r", ast.NewOptionalTypeAnnotation(ast.NewResultTypeAnnotation(ast.NewNamedTypeAnnotation("int"), ast.NewNamedTypeAnnotation("string"))), nil),
+			).toProgram(),
+		),
+		newParserTest(
+			"try binds tighter than arithmetic",
+			"f()? + 1",
+			newAstBuilder().addBinaryExpression(
+				tokens.NewMinimal(tokens.PLUS, "+"),
+				ast.NewTryExpr(ast.NewCallExpr(ast.NewIdentifierExpr("f"))),
+				ast.NewIntegerExpr(1),
+			).toProgram(),
+		),
+		newParserTest(
+			"try on field",
+			"x.y?",
+			newAstBuilder().addStatement(&ast.ExpressionStatement{
+				Expr: ast.NewTryExpr(ast.NewFieldExpr(ast.NewIdentifierExpr("x"), "y")),
+			}).toProgram(),
+		),
+		newParserTestFail("missing error type", "let r: Result<int", expectParseFailure("expected type of next token to be ,, got EOF instead")),
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			runParserTest(t, tt)
+		})
+	}
+}
+
+func TestParser_Tuples(t *testing.T) {
+	tests := []parserTestItem{
+		newParserTest(
+			"tuple literal",
+			"(1, \"a\", true)",
+			newAstBuilder().addStatement(&ast.ExpressionStatement{
+				Expr: ast.NewTupleExpr(ast.NewIntegerExpr(1), ast.NewStringExpr(`"a"`), ast.NewBooleanExpr(true)),
+			}).toProgram(),
+		),
+		newParserTest(
+			"single element is grouped",
+			"(1)",
+			newAstBuilder().addGroupedExpression(ast.NewIntegerExpr(1)).toProgram(),
+		),
+		newParserTest(
+			"tuple type annotation",
+			"let t: (int, []string);",
+			newAstBuilder().addStatement(
+				ast.NewLetStmt("t", ast.NewTupleTypeAnnotation(ast.NewNamedTypeAnnotation("int"), ast.NewListTypeAnnotation(ast.NewNamedTypeAnnotation("string"))), nil),
+			).toProgram(),
+		),
+		newParserTest(
+			"grouped type annotation",
+			"let f: (fn(): int)?;",
+			newAstBuilder().addStatement(
+				ast.NewLetStmt("f", ast.NewOptionalTypeAnnotation(ast.NewFunctionTypeAnnotation([]ast.TypeAnnotation{}, ast.NewNamedTypeAnnotation("int"))), nil),
+			).toProgram(),
+		),
+		newParserTest(
+			"element access",
+			"t.0.1",
+			newAstBuilder().addStatement(&ast.ExpressionStatement{
+				Expr: ast.NewFieldExpr(ast.NewFieldExpr(ast.NewIdentifierExpr("t"), "0"), "1"),
+			}).toProgram(),
+		),
+		newParserTest(
+			"destructuring",
+			"let (a, _) = f();",
+			newAstBuilder().addStatement(
+				ast.NewDestructuringStmt([]string{"a", "_"}, nil, ast.NewCallExpr(ast.NewIdentifierExpr("f"))),
+			).toProgram(),
+		),
+		newParserTest(
+			"annotated destructuring",
+			"let (a, b): (int, float) = (1, 2);",
+			newAstBuilder().addStatement(
+				ast.NewDestructuringStmt(
+					[]string{"a", "b"},
+					ast.NewTupleTypeAnnotation(ast.NewNamedTypeAnnotation("int"), ast.NewNamedTypeAnnotation("float")),
+					ast.NewTupleExpr(ast.NewIntegerExpr(1), ast.NewIntegerExpr(2)),
+				),
+			).toProgram(),
+		),
+		newParserTestFail("unterminated tuple", "(1, 2", expectParseFailure("expected type of next token to be ), got EOF instead")),
+		newParserTestFail("destructuring without value", "let (a, b)", expectParseFailure("expected type of next token to be =, got EOF instead")),
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			runParserTest(t, tt)
+		})
+	}
+}
+
+func TestParser_StringInterpolation(t *testing.T) {
+	tests := []parserTestItem{
+		newParserTest(
+			"single expression",
+			`"x = ${x + 1}"`,
+			newAstBuilder().addStatement(&ast.ExpressionStatement{
+				Expr: ast.NewInterpolationExpr(
+					ast.NewStringExpr(`"x = "`),
+					ast.NewBinaryExpr(tokens.NewMinimal(tokens.PLUS, "+"), ast.NewIdentifierExpr("x"), ast.NewIntegerExpr(1)),
+				),
+			}).toProgram(),
+		),
+		newParserTest(
+			"multiple expressions",
+			`"${a}, ${b}!"`,
+			newAstBuilder().addStatement(&ast.ExpressionStatement{
+				Expr: ast.NewInterpolationExpr(
+					ast.NewIdentifierExpr("a"),
+					ast.NewStringExpr(`", "`),
+					ast.NewIdentifierExpr("b"),
+					ast.NewStringExpr(`"!"`),
+				),
+			}).toProgram(),
+		),
+		newParserTest(
+			"nested",
+			`"a${"b${c}"}"`,
+			newAstBuilder().addStatement(&ast.ExpressionStatement{
+				Expr: ast.NewInterpolationExpr(
+					ast.NewStringExpr(`"a"`),
+					ast.NewInterpolationExpr(ast.NewStringExpr(`"b"`), ast.NewIdentifierExpr("c")),
+				),
+			}).toProgram(),
+		),
+		newParserTest(
+			"braces within expression",
+			`"${ {"k": 1} }"`,
+			newAstBuilder().addStatement(&ast.ExpressionStatement{
+				Expr: ast.NewInterpolationExpr(
+					ast.NewMapExpr(ast.NewStringExpr(`"k"`), ast.NewIntegerExpr(1)),
+				),
+			}).toProgram(),
+		),
+		newParserTestFail("empty expression", `"a ${}"`, expectParseFailure("expression expected after STRING_HEAD token")),
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			runParserTest(t, tt)
+		})
+	}
+}
+
+func TestParser_LetStatements(t *testing.T) {
+	tests := []parserTestItem{
+		newParserTest("simple", "let x = 5;", newAstBuilder().addStatement(ast.NewLetStmt("x", nil, ast.NewIntegerExpr(5))).toProgram()),
+		newParserTest(
+			"annotated",
+			"let x: float = 5;",
+			newAstBuilder().addStatement(ast.NewLetStmt("x", ast.NewNamedTypeAnnotation("float"), ast.NewIntegerExpr(5))).toProgram(),
+		),
+		newParserTest(
+			"annotated without initializer",
+			"let x: int;",
+			newAstBuilder().addStatement(ast.NewLetStmt("x", ast.NewNamedTypeAnnotation("int"), nil)).toProgram(),
+		),
+		newParserTestFail("missing type", "let x: = 5;", expectParseFailure("expected type of current token to be IDENTIFIER, got = instead")),
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			runParserTest(t, tt)
+		})
+	}
+}
+
+func TestParser_ForStatements(t *testing.T) {
+	tests := []parserTestItem{
+		newParserTest(
+			"full",
+			"for (let i = 0; i < 10; i = i + 1) { i }",
+			newAstBuilder().addStatement(
+				ast.NewForStmt(
+					ast.NewLetStmt("i", nil, ast.NewIntegerExpr(0)),
+					ast.NewBinaryExpr(tokens.NewMinimal(tokens.LESS_THAN, "<"), ast.NewIdentifierExpr("i"), ast.NewIntegerExpr(10)),
+					ast.NewAssignmentStmt("i", ast.NewBinaryExpr(tokens.NewMinimal(tokens.PLUS, "+"), ast.NewIdentifierExpr("i"), ast.NewIntegerExpr(1))),
+					ast.WrapExprsAsStmts([]ast.Expression{ast.NewIdentifierExpr("i")}),
+				),
+			).toProgram(),
+		),
+		newParserTest(
+			"empty clauses",
+			"for (;;) { i }",
+			newAstBuilder().addStatement(
+				ast.NewForStmt(nil, nil, nil, ast.WrapExprsAsStmts([]ast.Expression{ast.NewIdentifierExpr("i")})),
+			).toProgram(),
+		),
+		newParserTestFail("missing right paren", "for (;; i = i + 1 {}", expectParseFailure("expected type of next token to be ), got { instead")),
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			runParserTest(t, tt)
+		})
+	}
+}
+
+func TestParser_LoopControlStatements(t *testing.T) {
+	tests := []parserTestItem{
+		newParserTest(
+			"break",
+			"while (true) { break; }",
+			newAstBuilder().addStatement(
+				ast.NewWhileStmt(nil, ast.NewGroupedExpr(ast.NewBooleanExpr(true)), []ast.Statement{ast.NewBreakStmt(nil)}),
+			).toProgram(),
+		),
+		newParserTest(
+			"labeled continue",
+			"outer: while (true) { continue outer; }",
+			newAstBuilder().addStatement(
+				ast.NewWhileStmt(ast.NewLabel("outer"), ast.NewGroupedExpr(ast.NewBooleanExpr(true)), []ast.Statement{ast.NewContinueStmt(ast.NewLabel("outer"))}),
+			).toProgram(),
+		),
+		newParserTest(
+			"label on next line",
+			"while (true) { break\nx }",
+			newAstBuilder().addStatement(
+				ast.NewWhileStmt(nil, ast.NewGroupedExpr(ast.NewBooleanExpr(true)), []ast.Statement{
+					ast.NewBreakStmt(nil),
+					&ast.ExpressionStatement{Expr: ast.NewIdentifierExpr("x")},
+				}),
+			).toProgram(),
+		),
+		newParserTest(
+			"labeled for",
+			"outer: for (;;) { break outer }",
+			newAstBuilder().addStatement(
+				&ast.ForStatement{
+					Label: ast.NewLabel("outer"),
+					Body:  &ast.BlockStatement{Statements: []ast.Statement{ast.NewBreakStmt(ast.NewLabel("outer"))}},
+				},
+			).toProgram(),
+		),
+		newParserTestFail("labeled non-loop", "outer: x", expectParseFailure("only loops can be labeled")),
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			runParserTest(t, tt)
+		})
+	}
+}
+
+func TestParser_ConstStatements(t *testing.T) {
+	tests := []parserTestItem{
+		newParserTest("simple", "const N = 5;", newAstBuilder().addStatement(ast.NewConstStmt("N", nil, ast.NewIntegerExpr(5))).toProgram()),
+		newParserTest(
+			"annotated",
+			"const N: float = 5;",
+			newAstBuilder().addStatement(ast.NewConstStmt("N", ast.NewNamedTypeAnnotation("float"), ast.NewIntegerExpr(5))).toProgram(),
+		),
+		newParserTestFail("missing initializer", "const N: int", expectParseFailure("expected type of next token to be =, got EOF instead")),
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			runParserTest(t, tt)
+		})
+	}
+}
+
+func TestParser_AssignmentStatements(t *testing.T) {
+	tests := []parserTestItem{
+		newParserTest(
+			"assignment",
+			"x = 1;",
+			newAstBuilder().addStatement(ast.NewAssignmentStmt("x", ast.NewIntegerExpr(1))).toProgram(),
+		),
+		newParserTest(
+			"compound assignment",
+			"x += 1; x -= 2; x *= 3; x /= 4;",
+			newAstBuilder().
+				addStatement(ast.NewCompoundAssignmentStmt("x", tokens.NewMinimal(tokens.PLUS_EQUAL, "+="), ast.NewIntegerExpr(1))).
+				addStatement(ast.NewCompoundAssignmentStmt("x", tokens.NewMinimal(tokens.MINUS_EQUAL, "-="), ast.NewIntegerExpr(2))).
+				addStatement(ast.NewCompoundAssignmentStmt("x", tokens.NewMinimal(tokens.STAR_EQUAL, "*="), ast.NewIntegerExpr(3))).
+				addStatement(ast.NewCompoundAssignmentStmt("x", tokens.NewMinimal(tokens.SLASH_EQUAL, "/="), ast.NewIntegerExpr(4))).
+				toProgram(),
+		),
+		newParserTest(
+			"compound assignment with expression",
+			"x += y * 2",
+			newAstBuilder().addStatement(
+				ast.NewCompoundAssignmentStmt("x", tokens.NewMinimal(tokens.PLUS_EQUAL, "+="), ast.NewBinaryExpr(tokens.NewMinimal(tokens.STAR, "*"), ast.NewIdentifierExpr("y"), ast.NewIntegerExpr(2))),
+			).toProgram(),
+		),
+		newParserTestFail("missing value", "x +=", expectParseFailure("expression expected after += token")),
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			runParserTest(t, tt)
+		})
+	}
+}
+
+func TestParser_Arrays(t *testing.T) {
+	tests := []parserTestItem{
+		newParserTest(
+			"array literal",
+			"[1, 2 + 3, x];",
+			newAstBuilder().addStatement(&ast.ExpressionStatement{
+				Expr: ast.NewArrayExpr(
+					ast.NewIntegerExpr(1),
+					ast.NewBinaryExpr(tokens.NewMinimal(tokens.PLUS, "+"), ast.NewIntegerExpr(2), ast.NewIntegerExpr(3)),
+					ast.NewIdentifierExpr("x"),
+				),
+			}).toProgram(),
+		),
+		newParserTest(
+			"array type annotation",
+			"let a: [[int; 2]; 3];",
+			newAstBuilder().addStatement(
+				ast.NewLetStmt("a", ast.NewArrayTypeAnnotation(ast.NewArrayTypeAnnotation(ast.NewNamedTypeAnnotation("int"), 2), 3), nil),
+			).toProgram(),
+		),
+		newParserTest(
+			"index expression",
+			"a[i + 1][0] * 2;",
+			newAstBuilder().addStatement(&ast.ExpressionStatement{
+				Expr: ast.NewBinaryExpr(
+					tokens.NewMinimal(tokens.STAR, "*"),
+					ast.NewIndexExpr(
+						ast.NewIndexExpr(ast.NewIdentifierExpr("a"), ast.NewBinaryExpr(tokens.NewMinimal(tokens.PLUS, "+"), ast.NewIdentifierExpr("i"), ast.NewIntegerExpr(1))),
+						ast.NewIntegerExpr(0),
+					),
+					ast.NewIntegerExpr(2),
+				),
+			}).toProgram(),
+		),
+		newParserTest(
+			"index assignment",
+			"a[0] = 1; a[1] += 2;",
+			newAstBuilder().
+				addStatement(ast.NewIndexAssignmentStmt(ast.NewIndexExpr(ast.NewIdentifierExpr("a"), ast.NewIntegerExpr(0)), tokens.NewMinimal(tokens.ASSIGN, "="), ast.NewIntegerExpr(1))).
+				addStatement(ast.NewIndexAssignmentStmt(ast.NewIndexExpr(ast.NewIdentifierExpr("a"), ast.NewIntegerExpr(1)), tokens.NewMinimal(tokens.PLUS_EQUAL, "+="), ast.NewIntegerExpr(2))).
+				toProgram(),
+		),
+		newParserTestFail("missing array size", "let a: [int", expectParseFailure("expected type of next token to be ;, got EOF instead")),
+		newParserTestFail("unclosed index", "a[0", expectParseFailure("expected type of next token to be ], got EOF instead")),
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			runParserTest(t, tt)
+		})
+	}
+}
+
+func TestParser_Lists(t *testing.T) {
+	tests := []parserTestItem{
+		newParserTest(
+			"list type annotation",
+			"let xs: [][]int = [];",
+			newAstBuilder().addStatement(
+				ast.NewLetStmt("xs", ast.NewListTypeAnnotation(ast.NewListTypeAnnotation(ast.NewNamedTypeAnnotation("int"))), ast.NewArrayExpr()),
+			).toProgram(),
+		),
+		newParserTest(
+			"for in loop",
+			"for (x in xs) { x; }",
+			newAstBuilder().addStatement(
+				ast.NewForInStmt(nil, "x", ast.NewIdentifierExpr("xs"), []ast.Statement{
+					&ast.ExpressionStatement{Expr: ast.NewIdentifierExpr("x")},
+				}),
+			).toProgram(),
+		),
+		newParserTest(
+			"labeled for in loop",
+			"outer: for (row in grid[0]) { break outer; }",
+			newAstBuilder().addStatement(
+				ast.NewForInStmt(ast.NewLabel("outer"), "row", ast.NewIndexExpr(ast.NewIdentifierExpr("grid"), ast.NewIntegerExpr(0)), []ast.Statement{
+					ast.NewBreakStmt(ast.NewLabel("outer")),
+				}),
+			).toProgram(),
+		),
+		newParserTestFail("missing iterable", "for (x in) {}", expectParseFailure("expression expected after IN token")),
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			runParserTest(t, tt)
+		})
+	}
+}
+
+func TestParser_Maps(t *testing.T) {
+	tests := []parserTestItem{
+		newParserTest(
+			"map literal",
+			"let m = { \"a\": 1, \"b\": 2 + 3 };",
+			newAstBuilder().addStatement(
+				ast.NewLetStmt("m", nil, ast.NewMapExpr(
+					ast.NewStringExpr("\"a\""), ast.NewIntegerExpr(1),
+					ast.NewStringExpr("\"b\""), ast.NewBinaryExpr(tokens.NewMinimal(tokens.PLUS, "+"), ast.NewIntegerExpr(2), ast.NewIntegerExpr(3)),
+				)),
+			).toProgram(),
+		),
+		newParserTest(
+			"empty map literal with type annotation",
+			"let m: map[string][]int = {};",
+			newAstBuilder().addStatement(
+				ast.NewLetStmt("m", ast.NewMapTypeAnnotation(ast.NewNamedTypeAnnotation("string"), ast.NewListTypeAnnotation(ast.NewNamedTypeAnnotation("int"))), ast.NewMapExpr()),
+			).toProgram(),
+		),
+		newParserTest(
+			"nested map type annotation",
+			"let m: map[int]map[bool]float;",
+			newAstBuilder().addStatement(
+				ast.NewLetStmt("m", ast.NewMapTypeAnnotation(ast.NewNamedTypeAnnotation("int"), ast.NewMapTypeAnnotation(ast.NewNamedTypeAnnotation("bool"), ast.NewNamedTypeAnnotation("float"))), nil),
+			).toProgram(),
+		),
+		newParserTestFail("missing colon", "let m = { 1 2", expectParseFailure("expression expected after = token")),
+		newParserTestFail("missing key type", "let m: map int", expectParseFailure("expected type of next token to be [, got IDENTIFIER instead")),
+		newParserTestFail("unclosed key type", "let m: map[string", expectParseFailure("expected type of next token to be ], got EOF instead")),
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			runParserTest(t, tt)
+		})
+	}
+}
+
+func TestParser_Structs(t *testing.T) {
+	tests := []parserTestItem{
+		newParserTest(
+			"struct declaration",
+			"struct Point { x: float, y: float }",
+			newAstBuilder().addStatement(
+				ast.NewStructStmt("Point", ast.NewStructField("x", ast.NewNamedTypeAnnotation("float")), ast.NewStructField("y", ast.NewNamedTypeAnnotation("float"))),
+			).toProgram(),
+		),
+		newParserTest(
+			"struct declaration with trailing comma",
+			"struct Node {\n value: int,\n children: []Node,\n}",
+			newAstBuilder().addStatement(
+				ast.NewStructStmt("Node", ast.NewStructField("value", ast.NewNamedTypeAnnotation("int")), ast.NewStructField("children", ast.NewListTypeAnnotation(ast.NewNamedTypeAnnotation("Node")))),
+			).toProgram(),
+		),
+		newParserTest(
+			"empty struct declaration",
+			"struct Empty {}",
+			newAstBuilder().addStatement(ast.NewStructStmt("Empty")).toProgram(),
+		),
+		newParserTest(
+			"struct literal",
+			"let p = Point { x: 1.5, y: a + 1 };",
+			newAstBuilder().addStatement(
+				ast.NewLetStmt("p", nil, ast.NewStructExpr(
+					"Point",
+					ast.NewStructFieldValue("x", ast.NewFloatExpr(1.5)),
+					ast.NewStructFieldValue("y", ast.NewBinaryExpr(tokens.NewMinimal(tokens.PLUS, "+"), ast.NewIdentifierExpr("a"), ast.NewIntegerExpr(1))),
+				)),
+			).toProgram(),
+		),
+		newParserTest(
+			"field access",
+			"s.from.x * 2;",
+			newAstBuilder().addStatement(&ast.ExpressionStatement{
+				Expr: ast.NewBinaryExpr(
+					tokens.NewMinimal(tokens.STAR, "*"),
+					ast.NewFieldExpr(ast.NewFieldExpr(ast.NewIdentifierExpr("s"), "from"), "x"),
+					ast.NewIntegerExpr(2),
+				),
+			}).toProgram(),
+		),
+		newParserTest(
+			"field assignment",
+			"p.x = 1; pts[0].y += 2;",
+			newAstBuilder().
+				addStatement(ast.NewFieldAssignmentStmt(ast.NewFieldExpr(ast.NewIdentifierExpr("p"), "x"), tokens.NewMinimal(tokens.ASSIGN, "="), ast.NewIntegerExpr(1))).
+				addStatement(ast.NewFieldAssignmentStmt(
+					ast.NewFieldExpr(ast.NewIndexExpr(ast.NewIdentifierExpr("pts"), ast.NewIntegerExpr(0)), "y"),
+					tokens.NewMinimal(tokens.PLUS_EQUAL, "+="),
+					ast.NewIntegerExpr(2),
+				)).
+				toProgram(),
+		),
+		newParserTestFail("missing field type", "struct P { x", expectParseFailure("expected type of next token to be :, got EOF instead")),
+		newParserTestFail("missing field name", "p.", expectParseFailure("expected type of next token to be IDENTIFIER, got EOF instead")),
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			runParserTest(t, tt)
+		})
+	}
+}
+
+func TestParser_Enums(t *testing.T) {
+	tests := []parserTestItem{
+		newParserTest(
+			"enum declaration",
+			"enum Shape { Circle(float), Rect(float, float), Empty }",
+			newAstBuilder().addStatement(
+				ast.NewEnumStmt(
+					"Shape",
+					ast.NewEnumVariant("Circle", ast.NewNamedTypeAnnotation("float")),
+					ast.NewEnumVariant("Rect", ast.NewNamedTypeAnnotation("float"), ast.NewNamedTypeAnnotation("float")),
+					ast.NewEnumVariant("Empty"),
+				),
+			).toProgram(),
+		),
+		newParserTest(
+			"enum declaration with trailing comma",
+			"enum Tree {\n Leaf,\n Node([]Tree),\n}",
+			newAstBuilder().addStatement(
+				ast.NewEnumStmt("Tree", ast.NewEnumVariant("Leaf"), ast.NewEnumVariant("Node", ast.NewListTypeAnnotation(ast.NewNamedTypeAnnotation("Tree")))),
+			).toProgram(),
+		),
+		newParserTest(
+			"variant values",
+			"let a = Shape::Rect(1, w * 2); let b = Shape::Empty;",
+			newAstBuilder().
+				addStatement(ast.NewLetStmt("a", nil, ast.NewVariantExpr(
+					"Shape",
+					"Rect",
+					ast.NewIntegerExpr(1),
+					ast.NewBinaryExpr(tokens.NewMinimal(tokens.STAR, "*"), ast.NewIdentifierExpr("w"), ast.NewIntegerExpr(2)),
+				))).
+				addStatement(ast.NewLetStmt("b", nil, ast.NewVariantExpr("Shape", "Empty"))).
+				toProgram(),
+		),
+		newParserTest(
+			"match expression",
+			"let x = match (s) { Circle(r) => r * r, Rect(w, _) => w, _ => 0 };",
+			newAstBuilder().addStatement(
+				ast.NewLetStmt("x", nil, ast.NewMatchExpr(
+					ast.NewIdentifierExpr("s"),
+					ast.NewMatchArm("Circle", []string{"r"}, ast.NewBinaryExpr(tokens.NewMinimal(tokens.STAR, "*"), ast.NewIdentifierExpr("r"), ast.NewIdentifierExpr("r"))),
+					ast.NewMatchArm("Rect", []string{"w", "_"}, ast.NewIdentifierExpr("w")),
+					ast.NewMatchArm("_", nil, ast.NewIntegerExpr(0)),
+				)),
+			).toProgram(),
+		),
+		newParserTestFail("missing variant name", "Shape::", expectParseFailure("expected type of next token to be IDENTIFIER, got EOF instead")),
+		newParserTestFail("missing arrow", "match (s) { Empty", expectParseFailure("expected type of next token to be =>, got EOF instead")),
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			runParserTest(t, tt)
+		})
+	}
+}
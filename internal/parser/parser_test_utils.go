@@ -134,6 +134,14 @@ func (b astBuilder) addStringLiteralExpression(value string) astBuilder {
 	return b
 }
 
+func (b astBuilder) addCharLiteralExpression(value rune) astBuilder {
+	b.program.Statements = append(b.program.Statements, &ast.ExpressionStatement{
+		Expr: ast.NewCharExpr(value),
+	})
+
+	return b
+}
+
 func (b astBuilder) addIdentifierExpression(literal string) astBuilder {
 	b.program.Statements = append(b.program.Statements, &ast.ExpressionStatement{
 		Expr: ast.NewIdentifierExpr(literal),
@@ -174,6 +182,12 @@ func (b astBuilder) addIfExpression(condition ast.Expression, consquence, altern
 	return b
 }
 
+func (b astBuilder) addStatement(stmt ast.Statement) astBuilder {
+	b.program.Statements = append(b.program.Statements, stmt)
+
+	return b
+}
+
 func (b astBuilder) toProgram() *ast.Program {
 	return b.program
 }
@@ -206,11 +220,248 @@ func compareStatement(t *testing.T, idx int, expected, actual ast.Statement) {
 		for i, s := range exp.Statements {
 			compareStatement(t, idx, s, act.Statements[i])
 		}
+	case *ast.LetStatement:
+		act := assertType[*ast.LetStatement](t, idx, actual)
+		if exp.Identifier.Literal != act.Identifier.Literal {
+			t.Errorf("statement #%d: variable name mismatch: expected %s, got %s", idx, exp.Identifier.Literal, act.Identifier.Literal)
+		}
+
+		compareTypeAnnotation(t, idx, exp.Annotation, act.Annotation)
+
+		if exp.Value == nil {
+			if act.Value != nil {
+				t.Errorf("statement #%d: expected no initializer, got %s", idx, act.Value)
+			}
+
+			return
+		}
+
+		compareExpression(t, idx, exp.Value, act.Value)
+	case *ast.DestructuringStatement:
+		act := assertType[*ast.DestructuringStatement](t, idx, actual)
+		if len(exp.Identifiers) != len(act.Identifiers) {
+			t.Fatalf("statement #%d: num destructured variables mismatch: expected %d, got %d", idx, len(exp.Identifiers), len(act.Identifiers))
+		}
+
+		for i, identifier := range exp.Identifiers {
+			compareExpression(t, idx, identifier, act.Identifiers[i])
+		}
+
+		compareTypeAnnotation(t, idx, exp.Annotation, act.Annotation)
+		compareExpression(t, idx, exp.Value, act.Value)
+	case *ast.ConstStatement:
+		act := assertType[*ast.ConstStatement](t, idx, actual)
+		if exp.Identifier.Literal != act.Identifier.Literal {
+			t.Errorf("statement #%d: constant name mismatch: expected %s, got %s", idx, exp.Identifier.Literal, act.Identifier.Literal)
+		}
+
+		compareTypeAnnotation(t, idx, exp.Annotation, act.Annotation)
+		compareExpression(t, idx, exp.Value, act.Value)
+	case *ast.AssignmentStatement:
+		act := assertType[*ast.AssignmentStatement](t, idx, actual)
+		if exp.Identifier.Literal != act.Identifier.Literal {
+			t.Errorf("statement #%d: variable name mismatch: expected %s, got %s", idx, exp.Identifier.Literal, act.Identifier.Literal)
+		}
+
+		expOp, _ := exp.CompoundOperator()
+		actOp, _ := act.CompoundOperator()
+		if expOp != actOp {
+			t.Errorf("statement #%d: compound operator mismatch: expected %q, got %q", idx, expOp, actOp)
+		}
+
+		compareExpression(t, idx, exp.Value, act.Value)
+	case *ast.IndexAssignmentStatement:
+		act := assertType[*ast.IndexAssignmentStatement](t, idx, actual)
+		if exp.Operator.Literal != act.Operator.Literal {
+			t.Errorf("statement #%d: assignment operator mismatch: expected %s, got %s", idx, exp.Operator.Literal, act.Operator.Literal)
+		}
+
+		compareExpression(t, idx, exp.Target, act.Target)
+		compareExpression(t, idx, exp.Value, act.Value)
+	case *ast.FieldAssignmentStatement:
+		act := assertType[*ast.FieldAssignmentStatement](t, idx, actual)
+		if exp.Operator.Literal != act.Operator.Literal {
+			t.Errorf("statement #%d: assignment operator mismatch: expected %s, got %s", idx, exp.Operator.Literal, act.Operator.Literal)
+		}
+
+		compareExpression(t, idx, exp.Target, act.Target)
+		compareExpression(t, idx, exp.Value, act.Value)
+	case *ast.StructStatement:
+		act := assertType[*ast.StructStatement](t, idx, actual)
+		if exp.Identifier.Literal != act.Identifier.Literal {
+			t.Errorf("statement #%d: struct name mismatch: expected %s, got %s", idx, exp.Identifier.Literal, act.Identifier.Literal)
+		}
+
+		if len(exp.Fields) != len(act.Fields) {
+			t.Fatalf("statement #%d: num struct fields mismatch: expected %d, got %d", idx, len(exp.Fields), len(act.Fields))
+		}
+
+		for i, field := range exp.Fields {
+			if field.Identifier.Literal != act.Fields[i].Identifier.Literal {
+				t.Errorf("statement #%d: struct field name mismatch: expected %s, got %s", idx, field.Identifier.Literal, act.Fields[i].Identifier.Literal)
+			}
+
+			compareTypeAnnotation(t, idx, field.Annotation, act.Fields[i].Annotation)
+		}
+	case *ast.EnumStatement:
+		act := assertType[*ast.EnumStatement](t, idx, actual)
+		if exp.Identifier.Literal != act.Identifier.Literal {
+			t.Errorf("statement #%d: enum name mismatch: expected %s, got %s", idx, exp.Identifier.Literal, act.Identifier.Literal)
+		}
+
+		if len(exp.Variants) != len(act.Variants) {
+			t.Fatalf("statement #%d: num enum variants mismatch: expected %d, got %d", idx, len(exp.Variants), len(act.Variants))
+		}
+
+		for i, variant := range exp.Variants {
+			if variant.Identifier.Literal != act.Variants[i].Identifier.Literal {
+				t.Errorf("statement #%d: enum variant name mismatch: expected %s, got %s", idx, variant.Identifier.Literal, act.Variants[i].Identifier.Literal)
+			}
+
+			if len(variant.Payload) != len(act.Variants[i].Payload) {
+				t.Fatalf("statement #%d: num payload types of variant %s mismatch: expected %d, got %d", idx, variant.Identifier.Literal, len(variant.Payload), len(act.Variants[i].Payload))
+			}
+
+			for j, annotation := range variant.Payload {
+				compareTypeAnnotation(t, idx, annotation, act.Variants[i].Payload[j])
+			}
+		}
+	case *ast.ForStatement:
+		act := assertType[*ast.ForStatement](t, idx, actual)
+		compareLabel(t, idx, exp.Label, act.Label)
+		compareOptionalStatement(t, idx, exp.Initialization, act.Initialization)
+		compareOptionalExpression(t, idx, exp.Condition, act.Condition)
+		compareOptionalStatement(t, idx, exp.Update, act.Update)
+		compareStatement(t, idx, exp.Body, act.Body)
+	case *ast.ForInStatement:
+		act := assertType[*ast.ForInStatement](t, idx, actual)
+		compareLabel(t, idx, exp.Label, act.Label)
+		compareExpression(t, idx, exp.Identifier, act.Identifier)
+		compareExpression(t, idx, exp.Iterable, act.Iterable)
+		compareStatement(t, idx, exp.Body, act.Body)
+	case *ast.WhileStatement:
+		act := assertType[*ast.WhileStatement](t, idx, actual)
+		compareLabel(t, idx, exp.Label, act.Label)
+		compareExpression(t, idx, exp.Condition, act.Condition)
+		compareStatement(t, idx, exp.Body, act.Body)
+	case *ast.BreakStatement:
+		act := assertType[*ast.BreakStatement](t, idx, actual)
+		compareLabel(t, idx, exp.Label, act.Label)
+	case *ast.ContinueStatement:
+		act := assertType[*ast.ContinueStatement](t, idx, actual)
+		compareLabel(t, idx, exp.Label, act.Label)
+	case *ast.ReturnStatement:
+		act := assertType[*ast.ReturnStatement](t, idx, actual)
+		if exp.Expr == nil {
+			if act.Expr != nil {
+				t.Errorf("statement #%d: expected bare return, got return %s", idx, act.Expr)
+			}
+
+			return
+		}
+
+		compareExpression(t, idx, exp.Expr, act.Expr)
+	case *ast.FunctionStatement:
+		act := assertType[*ast.FunctionStatement](t, idx, actual)
+		if exp.Identifier.Literal != act.Identifier.Literal {
+			t.Errorf("statement #%d: function name mismatch: expected %s, got %s", idx, exp.Identifier.Literal, act.Identifier.Literal)
+		}
+
+		if len(exp.TypeParameters) != len(act.TypeParameters) {
+			t.Fatalf("statement #%d: type parameter count mismatch: expected %d, got %d", idx, len(exp.TypeParameters), len(act.TypeParameters))
+		}
+
+		for i, typeParam := range exp.TypeParameters {
+			if typeParam.String() != act.TypeParameters[i].String() {
+				t.Errorf("statement #%d: type parameter #%d mismatch: expected %s, got %s", idx, i, typeParam, act.TypeParameters[i])
+			}
+		}
+
+		compareFunction(t, idx, exp.Function, act.Function)
 	default:
 		t.Fatalf("unknown statement type %T", expected)
 	}
 }
 
+func compareLabel(t *testing.T, idx int, expected, actual *ast.IdentifierExpression) {
+	t.Helper()
+
+	if expected == nil || actual == nil {
+		if expected != actual {
+			t.Errorf("statement #%d: label mismatch: expected %v, got %v", idx, expected, actual)
+		}
+
+		return
+	}
+
+	if expected.Literal != actual.Literal {
+		t.Errorf("statement #%d: label mismatch: expected %s, got %s", idx, expected.Literal, actual.Literal)
+	}
+}
+
+func compareOptionalStatement(t *testing.T, idx int, expected, actual ast.Statement) {
+	t.Helper()
+
+	if expected == nil || actual == nil {
+		if expected != actual {
+			t.Errorf("statement #%d: statement mismatch: expected %v, got %v", idx, expected, actual)
+		}
+
+		return
+	}
+
+	compareStatement(t, idx, expected, actual)
+}
+
+func compareOptionalExpression(t *testing.T, idx int, expected, actual ast.Expression) {
+	t.Helper()
+
+	if expected == nil || actual == nil {
+		if expected != actual {
+			t.Errorf("statement #%d: expression mismatch: expected %v, got %v", idx, expected, actual)
+		}
+
+		return
+	}
+
+	compareExpression(t, idx, expected, actual)
+}
+
+func compareTypeAnnotation(t *testing.T, idx int, expected, actual ast.TypeAnnotation) {
+	t.Helper()
+
+	if expected == nil || actual == nil {
+		if expected != actual {
+			t.Errorf("statement #%d: type annotation mismatch: expected %v, got %v", idx, expected, actual)
+		}
+
+		return
+	}
+
+	if expected.String() != actual.String() {
+		t.Errorf("statement #%d: type annotation mismatch: expected %s, got %s", idx, expected, actual)
+	}
+}
+
+func compareFunction(t *testing.T, idx int, expected, actual *ast.FunctionExpression) {
+	t.Helper()
+
+	if len(expected.Parameters) != len(actual.Parameters) {
+		t.Fatalf("statement #%d: num parameters mismatch: expected %d, got %d", idx, len(expected.Parameters), len(actual.Parameters))
+	}
+
+	for i, param := range expected.Parameters {
+		if param.Identifier.Literal != actual.Parameters[i].Identifier.Literal {
+			t.Errorf("statement #%d: parameter name mismatch: expected %s, got %s", idx, param.Identifier.Literal, actual.Parameters[i].Identifier.Literal)
+		}
+
+		compareTypeAnnotation(t, idx, param.Annotation, actual.Parameters[i].Annotation)
+	}
+
+	compareTypeAnnotation(t, idx, expected.ReturnAnnotation, actual.ReturnAnnotation)
+	compareStatement(t, idx, expected.Body, actual.Body)
+}
+
 func compareExpression(t *testing.T, idx int, expected, actual ast.Expression) {
 	t.Helper()
 
@@ -235,6 +486,11 @@ func compareExpression(t *testing.T, idx int, expected, actual ast.Expression) {
 		if utils.NormalizeQuotedString(exp.Value) != act.Value {
 			t.Errorf("statement #%d: string value mismatch: expected %s, got %s", idx, exp.Value, act.Value)
 		}
+	case *ast.CharExpression:
+		act := assertType[*ast.CharExpression](t, idx, actual)
+		if exp.Value != act.Value {
+			t.Errorf("statement #%d: char value mismatch: expected %q, got %q", idx, exp.Value, act.Value)
+		}
 	case *ast.IdentifierExpression:
 		act := assertType[*ast.IdentifierExpression](t, idx, actual)
 		if exp.Literal != act.Literal {
@@ -247,6 +503,10 @@ func compareExpression(t *testing.T, idx int, expected, actual ast.Expression) {
 			t.Errorf("statament #%d: unary expression operator mismatch: expected %s, got %s", idx, exp.Token.Literal, act.Token.Literal)
 		}
 
+		if exp.IsPostfix != act.IsPostfix {
+			t.Errorf("statament #%d: unary expression postfix mismatch: expected %t, got %t", idx, exp.IsPostfix, act.IsPostfix)
+		}
+
 		compareExpression(t, idx, exp.Expr, act.Expr)
 	case *ast.BinaryExpression:
 		act := assertType[*ast.BinaryExpression](t, idx, actual)
@@ -260,10 +520,120 @@ func compareExpression(t *testing.T, idx int, expected, actual ast.Expression) {
 	case *ast.GroupedExpression:
 		act := assertType[*ast.GroupedExpression](t, idx, actual)
 		compareExpression(t, idx, exp.Expr, act.Expr)
+	case *ast.ArrayExpression:
+		act := assertType[*ast.ArrayExpression](t, idx, actual)
+		if len(exp.Elements) != len(act.Elements) {
+			t.Fatalf("statement #%d: num array elements mismatch: expected %d, got %d", idx, len(exp.Elements), len(act.Elements))
+		}
+
+		for i, e := range exp.Elements {
+			compareExpression(t, idx, e, act.Elements[i])
+		}
+	case *ast.InterpolationExpression:
+		act := assertType[*ast.InterpolationExpression](t, idx, actual)
+		if len(exp.Parts) != len(act.Parts) {
+			t.Fatalf("statement #%d: num interpolation parts mismatch: expected %d, got %d", idx, len(exp.Parts), len(act.Parts))
+		}
+
+		for i, part := range exp.Parts {
+			compareExpression(t, idx, part, act.Parts[i])
+		}
+	case *ast.TupleExpression:
+		act := assertType[*ast.TupleExpression](t, idx, actual)
+		if len(exp.Elements) != len(act.Elements) {
+			t.Fatalf("statement #%d: num tuple elements mismatch: expected %d, got %d", idx, len(exp.Elements), len(act.Elements))
+		}
+
+		for i, e := range exp.Elements {
+			compareExpression(t, idx, e, act.Elements[i])
+		}
+	case *ast.StructExpression:
+		act := assertType[*ast.StructExpression](t, idx, actual)
+		compareExpression(t, idx, exp.Name, act.Name)
+		if len(exp.Fields) != len(act.Fields) {
+			t.Fatalf("statement #%d: num struct literal fields mismatch: expected %d, got %d", idx, len(exp.Fields), len(act.Fields))
+		}
+
+		for i, field := range exp.Fields {
+			compareExpression(t, idx, field.Identifier, act.Fields[i].Identifier)
+			compareExpression(t, idx, field.Value, act.Fields[i].Value)
+		}
+	case *ast.FieldExpression:
+		act := assertType[*ast.FieldExpression](t, idx, actual)
+		compareExpression(t, idx, exp.Left, act.Left)
+		compareExpression(t, idx, exp.Field, act.Field)
+	case *ast.FunctionExpression:
+		act := assertType[*ast.FunctionExpression](t, idx, actual)
+		compareFunction(t, idx, exp, act)
+	case *ast.CallExpression:
+		act := assertType[*ast.CallExpression](t, idx, actual)
+		if exp.Identifier != nil {
+			compareExpression(t, idx, exp.Identifier, act.Identifier)
+		} else {
+			compareExpression(t, idx, exp.Callee, act.Callee)
+		}
+
+		if len(exp.Arguments) != len(act.Arguments) {
+			t.Fatalf("statement #%d: num call arguments mismatch: expected %d, got %d", idx, len(exp.Arguments), len(act.Arguments))
+		}
+
+		for i, arg := range exp.Arguments {
+			compareExpression(t, idx, arg, act.Arguments[i])
+		}
+	case *ast.VariantExpression:
+		act := assertType[*ast.VariantExpression](t, idx, actual)
+		compareExpression(t, idx, exp.Enum, act.Enum)
+		compareExpression(t, idx, exp.Variant, act.Variant)
+		if len(exp.Arguments) != len(act.Arguments) {
+			t.Fatalf("statement #%d: num variant values mismatch: expected %d, got %d", idx, len(exp.Arguments), len(act.Arguments))
+		}
+
+		for i, arg := range exp.Arguments {
+			compareExpression(t, idx, arg, act.Arguments[i])
+		}
+	case *ast.MatchExpression:
+		act := assertType[*ast.MatchExpression](t, idx, actual)
+		compareExpression(t, idx, exp.Subject, act.Subject)
+		if len(exp.Arms) != len(act.Arms) {
+			t.Fatalf("statement #%d: num match arms mismatch: expected %d, got %d", idx, len(exp.Arms), len(act.Arms))
+		}
+
+		for i, arm := range exp.Arms {
+			compareExpression(t, idx, arm.Variant, act.Arms[i].Variant)
+			if len(arm.Bindings) != len(act.Arms[i].Bindings) {
+				t.Fatalf("statement #%d: num bindings of match arm %s mismatch: expected %d, got %d", idx, arm.Variant, len(arm.Bindings), len(act.Arms[i].Bindings))
+			}
+
+			for j, binding := range arm.Bindings {
+				compareExpression(t, idx, binding, act.Arms[i].Bindings[j])
+			}
+
+			compareStatement(t, idx, arm.Body, act.Arms[i].Body)
+		}
+	case *ast.MapExpression:
+		act := assertType[*ast.MapExpression](t, idx, actual)
+		if len(exp.Keys) != len(act.Keys) {
+			t.Fatalf("statement #%d: num map entries mismatch: expected %d, got %d", idx, len(exp.Keys), len(act.Keys))
+		}
+
+		for i := range exp.Keys {
+			compareExpression(t, idx, exp.Keys[i], act.Keys[i])
+			compareExpression(t, idx, exp.Values[i], act.Values[i])
+		}
+	case *ast.IndexExpression:
+		act := assertType[*ast.IndexExpression](t, idx, actual)
+		compareExpression(t, idx, exp.Left, act.Left)
+		compareExpression(t, idx, exp.Index, act.Index)
+	case *ast.NoneExpression:
+		assertType[*ast.NoneExpression](t, idx, actual)
+	case *ast.TryExpression:
+		act := assertType[*ast.TryExpression](t, idx, actual)
+		compareExpression(t, idx, exp.Expr, act.Expr)
 	case *ast.IfExpression:
 		act := assertType[*ast.IfExpression](t, idx, actual)
 
 		compareExpression(t, idx, exp.Condition, act.Condition)
+		compareLabel(t, idx, exp.Binding, act.Binding)
 		compareStatement(t, idx, exp.Consequence, act.Consequence)
 
 		if exp.Alternative != nil {
@@ -47,11 +47,25 @@ const (
 	COMMA     = ","
 	SEMICOLON = ";"
 	COLON     = ":"
+	DOT       = "."
+
+	DOUBLE_COLON = "::"
+	FAT_ARROW    = "=>"
+
+	QUESTION        = "?"
+	DOUBLE_QUESTION = "??"
 
 	IDENTIFIER = "IDENTIFIER"
 	INTEGER    = "INTEGER"
 	FLOAT      = "FLOAT"
 	STRING     = "STRING"
+	CHAR       = "CHAR"
+
+	// parts of an interpolated string literal, eg. "a ${x} b ${y} c" is lexed into STRING_HEAD ("a ), the tokens of x,
+	// STRING_MIDDLE ( b ), the tokens of y and STRING_TAIL ( c")
+	STRING_HEAD   = "STRING_HEAD"
+	STRING_MIDDLE = "STRING_MIDDLE"
+	STRING_TAIL   = "STRING_TAIL"
 
 	LET      = "LET"
 	CONST    = "CONST"
@@ -65,7 +79,13 @@ const (
 	FOR      = "FOR"
 	BREAK    = "BREAK"
 	CONTINUE = "CONTINUE"
+	IN       = "IN"
+	MAP      = "MAP"
+	STRUCT   = "STRUCT"
+	ENUM     = "ENUM"
+	MATCH    = "MATCH"
 	EXIT     = "EXIT"
+	NONE     = "NONE"
 
 	EOF     = "EOF"
 	ILLEGAL = "ILLEGAL"
@@ -84,6 +104,20 @@ var PROGRAM_KEYWORDS = map[string]TokenType{
 	"while":    WHILE,
 	"break":    BREAK,
 	"continue": CONTINUE,
+	"in":       IN,
+	"map":      MAP,
+	"struct":   STRUCT,
+	"enum":     ENUM,
+	"match":    MATCH,
+	"none":     NONE,
+}
+
+// compound assignment operators mapped to the binary operator they apply
+var COMPOUND_ASSIGNMENT_OPERATORS = map[TokenType]TokenType{
+	PLUS_EQUAL:  PLUS,
+	MINUS_EQUAL: MINUS,
+	STAR_EQUAL:  STAR,
+	SLASH_EQUAL: SLASH,
 }
 
 func New(tokenType TokenType, literal string, line, startColumn, endColumn int) Token {
@@ -0,0 +1,449 @@
+package typechecker
+
+import (
+	"fmt"
+	"math"
+	"strconv"
+	"unicode/utf8"
+
+	"github.com/0xmukesh/coco/internal/ast"
+	"github.com/0xmukesh/coco/internal/tokens"
+	cotypes "github.com/0xmukesh/coco/internal/types"
+)
+
+// evaluates an already type checked expression at compile time and returns the result as a literal expression.
+// fails if the expression depends on anything other than literals and constants
+func (tc *TypeChecker) foldConstant(expr ast.Expression) (ast.Expression, error) {
+	switch e := expr.(type) {
+	case *ast.IntegerExpression, *ast.FloatExpression, *ast.BooleanExpression, *ast.StringExpression, *ast.CharExpression:
+		return expr, nil
+	case *ast.IdentifierExpression:
+		sym, found := tc.env.Get(e.String())
+		if !found || !sym.isConst {
+			return nil, fmt.Errorf("%s is not a constant", e.String())
+		}
+
+		return sym.value, nil
+	case *ast.GroupedExpression:
+		return tc.foldConstant(e.Expr)
+	case *ast.CallExpression:
+		if !e.IsBuiltin || *e.BuiltinKind != ast.BuiltinFuncConvert {
+			return nil, fmt.Errorf("%s cannot be evaluated at compile time", expr)
+		}
+
+		value, err := tc.foldConstant(e.Arguments[0])
+		if err != nil {
+			return nil, err
+		}
+
+		return foldConversion(value, e.GetType())
+	case *ast.CastExpression:
+		value, err := tc.foldConstant(e.Expr)
+		if err != nil {
+			return nil, err
+		}
+
+		if isNumeric(e.Type) {
+			return convertConstant(value, e.Type)
+		}
+
+		return nil, fmt.Errorf("cannot cast %s to %s", value.GetType(), e.Type)
+	case *ast.UnaryExpression:
+		operand, err := tc.foldConstant(e.Expr)
+		if err != nil {
+			return nil, err
+		}
+
+		folded, err := foldUnary(e.Token, operand)
+		if err != nil {
+			return nil, err
+		}
+
+		return keepNumericType(folded, e.GetType())
+	case *ast.BinaryExpression:
+		left, err := tc.foldConstant(e.Left)
+		if err != nil {
+			return nil, err
+		}
+
+		right, err := tc.foldConstant(e.Right)
+		if err != nil {
+			return nil, err
+		}
+
+		folded, err := foldBinary(e.Operator, left, right)
+		if err != nil {
+			return nil, err
+		}
+
+		return keepNumericType(folded, e.GetType())
+	default:
+		return nil, fmt.Errorf("%s cannot be evaluated at compile time", expr)
+	}
+}
+
+// reports whether the expression, which is not type checked yet, is made up of literals and already declared
+// constants only, see Transform
+func (tc *TypeChecker) refersToConstantsOnly(expr ast.Expression) bool {
+	switch e := expr.(type) {
+	case *ast.IntegerExpression, *ast.FloatExpression, *ast.BooleanExpression, *ast.StringExpression, *ast.CharExpression:
+		return true
+	case *ast.IdentifierExpression:
+		sym, found := tc.env.Get(e.String())
+		return found && sym.isConst
+	case *ast.GroupedExpression:
+		return tc.refersToConstantsOnly(e.Expr)
+	case *ast.CallExpression:
+		if e.Identifier == nil || len(e.Arguments) != 1 {
+			return false
+		}
+
+		builtin, isBuiltin := tc.builtins[e.Identifier.String()]
+		return isBuiltin && builtin.kind == ast.BuiltinFuncConvert && tc.refersToConstantsOnly(e.Arguments[0])
+	case *ast.UnaryExpression:
+		return tc.refersToConstantsOnly(e.Expr)
+	case *ast.BinaryExpression:
+		return tc.refersToConstantsOnly(e.Left) && tc.refersToConstantsOnly(e.Right)
+	default:
+		return false
+	}
+}
+
+func foldUnary(op tokens.Token, operand ast.Expression) (ast.Expression, error) {
+	switch o := operand.(type) {
+	case *ast.IntegerExpression:
+		if op.Type == tokens.MINUS {
+			return newIntegerLiteral(op, -o.Value), nil
+		}
+	case *ast.FloatExpression:
+		if op.Type == tokens.MINUS {
+			return newFloatLiteral(op, -o.Value), nil
+		}
+	case *ast.BooleanExpression:
+		if op.Type == tokens.BANG {
+			return newBooleanLiteral(op, !o.Value), nil
+		}
+	}
+
+	return nil, fmt.Errorf("cannot evaluate %s operation on %s", op.Type, operand.GetType())
+}
+
+func foldBinary(op tokens.Token, left, right ast.Expression) (ast.Expression, error) {
+	switch l := left.(type) {
+	case *ast.IntegerExpression:
+		r, ok := right.(*ast.IntegerExpression)
+		if !ok {
+			break
+		}
+
+		// int constants are 64 bits wide like int, results which do not fit are reported instead of wrapping around
+		overflow := &constantOverflowError{value: &ast.BinaryExpression{Left: l, Operator: op, Right: r}, typ: cotypes.IntType{}}
+
+		switch op.Type {
+		case tokens.PLUS:
+			result, ok := addInt64(l.Value, r.Value)
+			if !ok {
+				return nil, overflow
+			}
+
+			return newIntegerLiteral(op, result), nil
+		case tokens.MINUS:
+			result, ok := subInt64(l.Value, r.Value)
+			if !ok {
+				return nil, overflow
+			}
+
+			return newIntegerLiteral(op, result), nil
+		case tokens.STAR:
+			result, ok := mulInt64(l.Value, r.Value)
+			if !ok {
+				return nil, overflow
+			}
+
+			return newIntegerLiteral(op, result), nil
+		case tokens.SLASH:
+			if r.Value == 0 {
+				return nil, fmt.Errorf("division by zero")
+			}
+
+			return newIntegerLiteral(op, l.Value/r.Value), nil
+		case tokens.MODULO:
+			if r.Value == 0 {
+				return nil, fmt.Errorf("division by zero")
+			}
+
+			return newIntegerLiteral(op, l.Value%r.Value), nil
+		case tokens.DOUBLE_STAR:
+			base, exp := l.Value, r.Value
+
+			// same semantics as the runtime, x ** -n is (1 / x) ** n with integer division
+			if exp < 0 {
+				if base == 0 {
+					return nil, fmt.Errorf("division by zero")
+				}
+
+				base, exp = 1/base, -exp
+			}
+
+			// exponentiation by squaring, the base is only squared if a higher bit of the exponent still needs it
+			result, ok := int64(1), true
+			for exp > 0 {
+				if exp&1 == 1 {
+					if result, ok = mulInt64(result, base); !ok {
+						return nil, overflow
+					}
+				}
+
+				exp >>= 1
+				if exp > 0 {
+					if base, ok = mulInt64(base, base); !ok {
+						return nil, overflow
+					}
+				}
+			}
+
+			return newIntegerLiteral(op, result), nil
+		default:
+			if result, ok := compareOrdered(op, l.Value, r.Value); ok {
+				return newBooleanLiteral(op, result), nil
+			}
+		}
+	case *ast.FloatExpression:
+		r, ok := right.(*ast.FloatExpression)
+		if !ok {
+			break
+		}
+
+		switch op.Type {
+		case tokens.PLUS:
+			return newFloatLiteral(op, l.Value+r.Value), nil
+		case tokens.MINUS:
+			return newFloatLiteral(op, l.Value-r.Value), nil
+		case tokens.STAR:
+			return newFloatLiteral(op, l.Value*r.Value), nil
+		case tokens.SLASH:
+			return newFloatLiteral(op, l.Value/r.Value), nil
+		case tokens.MODULO:
+			return newFloatLiteral(op, math.Mod(l.Value, r.Value)), nil
+		case tokens.DOUBLE_STAR:
+			return newFloatLiteral(op, math.Pow(l.Value, r.Value)), nil
+		default:
+			if result, ok := compareOrdered(op, l.Value, r.Value); ok {
+				return newBooleanLiteral(op, result), nil
+			}
+		}
+	case *ast.StringExpression:
+		r, ok := right.(*ast.StringExpression)
+		if !ok {
+			break
+		}
+
+		// string literals keep their surrounding quotes
+		lValue, rValue := l.Value[1:len(l.Value)-1], r.Value[1:len(r.Value)-1]
+
+		if op.Type == tokens.PLUS {
+			value := "\"" + lValue + rValue + "\""
+
+			return &ast.StringExpression{
+				Token: tokens.New(tokens.STRING, value, op.Line, op.StartColumn, op.EndColumn),
+				Value: value,
+				Type:  cotypes.StringType{},
+			}, nil
+		}
+
+		if result, ok := compareOrdered(op, lValue, rValue); ok {
+			return newBooleanLiteral(op, result), nil
+		}
+	case *ast.CharExpression:
+		r, ok := right.(*ast.CharExpression)
+		if !ok {
+			break
+		}
+
+		if result, ok := compareOrdered(op, l.Value, r.Value); ok {
+			return newBooleanLiteral(op, result), nil
+		}
+	case *ast.BooleanExpression:
+		r, ok := right.(*ast.BooleanExpression)
+		if !ok {
+			break
+		}
+
+		switch op.Type {
+		case tokens.EQUALS:
+			return newBooleanLiteral(op, l.Value == r.Value), nil
+		case tokens.NOT_EQUALS:
+			return newBooleanLiteral(op, l.Value != r.Value), nil
+		case tokens.AND:
+			return newBooleanLiteral(op, l.Value && r.Value), nil
+		case tokens.OR:
+			return newBooleanLiteral(op, l.Value || r.Value), nil
+		}
+	}
+
+	return nil, fmt.Errorf("cannot evaluate %s operation on %s and %s", op.Type, left.GetType(), right.GetType())
+}
+
+// arithmetic on int64 which reports whether the result fits instead of wrapping around
+func addInt64(a, b int64) (int64, bool) {
+	result := a + b
+	return result, (result > a) == (b > 0)
+}
+
+func subInt64(a, b int64) (int64, bool) {
+	result := a - b
+	return result, (result < a) == (b > 0)
+}
+
+func mulInt64(a, b int64) (int64, bool) {
+	if a == 0 || b == 0 {
+		return 0, true
+	}
+
+	result := a * b
+	return result, result/b == a && !(b == -1 && a == math.MinInt64)
+}
+
+// error for constants which are used as a numeric type that cannot hold their value
+type constantOverflowError struct {
+	value ast.Expression
+	typ   cotypes.Type
+}
+
+func (e *constantOverflowError) Error() string {
+	return fmt.Sprintf("constant %s overflows %s", e.value, e.typ)
+}
+
+// converts an integer or float literal into a literal of the numeric type, failing if the type cannot hold its value
+func convertConstant(value ast.Expression, target cotypes.Type) (ast.Expression, error) {
+	switch v := value.(type) {
+	case *ast.IntegerExpression:
+		if cotypes.IsInteger(target) {
+			if !fitsInteger(v.Value, target) {
+				return nil, &constantOverflowError{value: v, typ: target}
+			}
+
+			return &ast.IntegerExpression{Token: v.Token, Value: v.Value, Type: target}, nil
+		}
+
+		if cotypes.IsFloat(target) {
+			literal := newFloatLiteral(v.Token, float64(v.Value)).(*ast.FloatExpression)
+			literal.Type = target
+			return literal, nil
+		}
+	case *ast.FloatExpression:
+		if cotypes.IsFloat(target) {
+			return &ast.FloatExpression{Token: v.Token, Value: v.Value, Type: target}, nil
+		}
+	}
+
+	return nil, fmt.Errorf("cannot convert %s to %s", value.GetType(), target)
+}
+
+// converts a literal like the conversion builtin named after the target type does, eg. u8(300) fails as u8 cannot
+// hold 300. chars convert to and from integers holding their code point
+func foldConversion(value ast.Expression, target cotypes.Type) (ast.Expression, error) {
+	isChar := target.Equals(cotypes.CharType{})
+	switch v := value.(type) {
+	case *ast.CharExpression:
+		if isChar {
+			return v, nil
+		}
+
+		return convertConstant(newIntegerLiteral(v.Token, int64(v.Value)), target)
+	case *ast.IntegerExpression:
+		if !isChar {
+			break
+		}
+
+		if v.Value < 0 || v.Value > utf8.MaxRune || !utf8.ValidRune(rune(v.Value)) {
+			return nil, fmt.Errorf("constant %d is not a valid char", v.Value)
+		}
+
+		return &ast.CharExpression{Token: v.Token, Value: rune(v.Value), Type: cotypes.CharType{}}, nil
+	case *ast.FloatExpression:
+		if cotypes.IsInteger(target) {
+			return nil, fmt.Errorf("conversion of float constant %s to %s cannot be evaluated at compile time", v, target)
+		}
+	}
+
+	return convertConstant(value, target)
+}
+
+// literals folded from operands of a sized numeric type keep that type, eg. 200 + 100 folds into a u8 literal if
+// the operands are u8 constants, which fails as u8 cannot hold 300
+func keepNumericType(folded ast.Expression, t cotypes.Type) (ast.Expression, error) {
+	if !isSizedNumeric(t) {
+		return folded, nil
+	}
+
+	return convertConstant(folded, t)
+}
+
+func compareOrdered[T int64 | float64 | rune | string](op tokens.Token, l, r T) (result bool, ok bool) {
+	switch op.Type {
+	case tokens.LESS_THAN:
+		return l < r, true
+	case tokens.GREATER_THAN:
+		return l > r, true
+	case tokens.LESS_THAN_EQUALS:
+		return l <= r, true
+	case tokens.GREATER_THAN_EQUALS:
+		return l >= r, true
+	case tokens.EQUALS:
+		return l == r, true
+	case tokens.NOT_EQUALS:
+		return l != r, true
+	default:
+		return false, false
+	}
+}
+
+func newIntegerLiteral(at tokens.Token, value int64) ast.Expression {
+	literal := strconv.FormatInt(value, 10)
+
+	return &ast.IntegerExpression{
+		Token: tokens.New(tokens.INTEGER, literal, at.Line, at.StartColumn, at.EndColumn),
+		Value: value,
+		Type:  cotypes.IntType{},
+	}
+}
+
+func newFloatLiteral(at tokens.Token, value float64) ast.Expression {
+	literal := strconv.FormatFloat(value, 'g', -1, 64)
+
+	return &ast.FloatExpression{
+		Token: tokens.New(tokens.FLOAT, literal, at.Line, at.StartColumn, at.EndColumn),
+		Value: value,
+		Type:  cotypes.FloatType{},
+	}
+}
+
+func newBooleanLiteral(at tokens.Token, value bool) ast.Expression {
+	literal := strconv.FormatBool(value)
+	tt := tokens.TokenType(tokens.FALSE)
+	if value {
+		tt = tokens.TRUE
+	}
+
+	return &ast.BooleanExpression{
+		Token: tokens.New(tt, literal, at.Line, at.StartColumn, at.EndColumn),
+		Value: value,
+		Type:  cotypes.BoolType{},
+	}
+}
+
+// returns the value of an index expression if it can be evaluated at compile time
+func (tc *TypeChecker) foldIndex(expr ast.Expression) (int64, bool) {
+	value, err := tc.foldConstant(expr)
+	if err != nil {
+		return 0, false
+	}
+
+	intLit, ok := value.(*ast.IntegerExpression)
+	if !ok {
+		return 0, false
+	}
+
+	return intLit.Value, true
+}
@@ -29,3 +29,653 @@ if (a) {
 		}
 	}
 }
+
+func TestTypeChecker_Functions(t *testing.T) {
+	tests := []typeCheckerTestItem{
+		newTypeCheckerTest("simple", "fn add(a: int, b: int): int { return a + b; } let x = add(1, 2);"),
+		newTypeCheckerTest("hoisted call", "let x = twice(2); fn twice(a: int): int { return a * 2; }"),
+		newTypeCheckerTest("recursion", "fn fact(n: int): int { if (n <= 1) { return 1; } else { return n * fact(n - 1); } }"),
+		newTypeCheckerTest("void with bare return", "fn hello(a: int) { if (a > 1) { return; } print(a); }"),
+		newTypeCheckerTestFail("wrong argument count", "fn id(a: int): int { return a; } id(1, 2);", "wrong number of arguments to id"),
+		newTypeCheckerTestFail("wrong argument type", "fn id(a: int): int { return a; } id(true);", "invalid argument at 0 idx to id"),
+		newTypeCheckerTestFail("wrong return type", "fn id(a: int): bool { return a; }", "cannot return int from function id"),
+		newTypeCheckerTestFail("missing return", "fn id(a: int): int { if (a > 1) { return a; } }", "must return a value of type int on all paths"),
+		newTypeCheckerTestFail("return outside function", "return 1;", "return statement outside of function"),
+		newTypeCheckerTest("top level variable", "let g = 1; fn f(): int { g += 1; return g; }"),
+		newTypeCheckerTest("top level variable called after initialization", "let g = 1; print(f()); fn f(): int { return g; }"),
+		newTypeCheckerTestFail("top level variable called early", "print(f()); let g = 1; fn f(): int { return g; }", "function f uses variable g before it is initialized"),
+		newTypeCheckerTestFail("top level variable called early through another function", "print(f()); let g = 1; fn h(): int { return g; } fn f(): int { return h(); }", "function f uses variable g before it is initialized"),
+		newTypeCheckerTestFail("top level variable used early as value", "let h = f; let g = 1; fn f(): int { return g; }", "function f uses variable g before it is initialized"),
+		newTypeCheckerTestFail("top level variable called in own initializer", "let g = f(); fn f(): int { return g; }", "function f uses variable g before it is initialized"),
+		newTypeCheckerTestFail("top level variable declared after", "fn f(): int { return g; } let g = 1;", "unknown identifier: g"),
+		newTypeCheckerTestFail("nested variable", "{ let g = 1; } fn f(): int { return g; }", "unknown identifier: g"),
+		newTypeCheckerTestFail("redeclare", "fn f() {} fn f() {}", "cannot redeclare function: f"),
+		newTypeCheckerTestFail("redeclare builtin", "fn print() {}", "cannot redeclare builtin function: print"),
+		newTypeCheckerTestFail("nested", "fn f() { fn g() {} }", "function g must be declared at top level"),
+		newTypeCheckerTestFail("void value", "fn f() {} let x = f();", "cannot assign void value to variable: x"),
+		newTypeCheckerTestFail("unknown type", "fn f(a: foo) {}", "unknown type: foo"),
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			runTypeCheckerTest(t, tt)
+		})
+	}
+}
+
+func TestTypeChecker_Closures(t *testing.T) {
+	tests := []typeCheckerTestItem{
+		newTypeCheckerTest("function literal", "let inc = fn(x: int): int { return x + 1; }; let y: int = inc(1);"),
+		newTypeCheckerTest("annotated function value", "let f: fn(int, int): bool = fn(a: int, b: int): bool { return a < b; };"),
+		newTypeCheckerTest("capture", "let total = 0; let add = fn(x: int) { total += x; }; add(1);"),
+		newTypeCheckerTest("returned closure", "fn counter(): fn(): int { let n = 0; return fn(): int { n += 1; return n; }; } let c = counter(); c();"),
+		newTypeCheckerTest("function as value", "fn twice(x: int): int { return x * 2; } fn apply(f: fn(int): int, x: int): int { return f(x); } apply(twice, 1);"),
+		newTypeCheckerTest("call through expression", "fn adder(n: int): fn(int): int { return fn(x: int): int { return x + n; }; } let x: int = adder(1)(2);"),
+		newTypeCheckerTest("nested capture", "let a = 1; let f = fn(): fn(): int { return fn(): int { return a; }; };"),
+		newTypeCheckerTestFail("wrong argument type", "let f = fn(x: int) {}; f(true);", "invalid argument at 0 idx to f. expected int, got bool"),
+		newTypeCheckerTestFail("wrong argument count", "let f = fn(x: int) {}; f();", "wrong number of arguments to f"),
+		newTypeCheckerTestFail("call non-function", "let x = 1; x();", "cannot call value of type int"),
+		newTypeCheckerTestFail("mismatched function type", "let f: fn(int): int = fn(x: float): int { return 1; };", "cannot assign fn(float): int to variable f of type fn(int): int"),
+		newTypeCheckerTestFail("missing parameter type", "let f = fn(x) {};", "missing type annotation for parameter x of function literal"),
+		newTypeCheckerTestFail("missing return", "let f = fn(): int {};", "function literal must return a value of type int on all paths"),
+		newTypeCheckerTestFail("wrong return type", "let f = fn(): int { return true; };", "cannot return bool from function literal with return type int"),
+		newTypeCheckerTestFail("break out of closure", "while (true) { let f = fn() { break; }; }", "break statement outside of loop"),
+		newTypeCheckerTestFail("builtin as value", "let p = print;", "builtin function print cannot be used as a value"),
+		newTypeCheckerTestFail("captured variable out of scope", "let f = fn() { let x = 1; }; print(x);", "unknown identifier: x"),
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			runTypeCheckerTest(t, tt)
+		})
+	}
+}
+
+func TestTypeChecker_Generics(t *testing.T) {
+	tests := []typeCheckerTestItem{
+		newTypeCheckerTest("ordered", "fn max<T: ordered>(a: T, b: T): T { if (a > b) { return a; } return b; } let x: int = max(1, 2); let s: string = max(\"a\", \"b\");"),
+		newTypeCheckerTest("unconstrained", "struct P { x: int } fn id<T>(x: T): T { return x; } let p: P = id(P { x: 1 });"),
+		newTypeCheckerTest("numeric", "fn sum<T: numeric>(xs: []T): T { let total: T; for (x in xs) { total += x; } return total; } let x: float = sum([1.5, 2.5]);"),
+		newTypeCheckerTest("int and float unify to float", "fn max<T: ordered>(a: T, b: T): T { if (a > b) { return a; } return b; } let x: float = max(1, 2.5);"),
+		newTypeCheckerTest("recursion", "fn count<T: numeric>(n: T, step: T): int { if (n <= step) { return 1; } return 1 + count(n - step, step); } count(1.0, 0.25);"),
+		newTypeCheckerTest("generic calling generic", "fn id<T>(x: T): T { return x; } fn twice<T>(x: T): []T { return [id(x), id(x)]; } let xs: []bool = twice(true);"),
+		newTypeCheckerTest("inferred through function type", "fn apply<A, B>(x: A, f: fn(A): B): B { return f(x); } let s: string = apply(1, fn(x: int): string { return \"x\"; });"),
+		newTypeCheckerTest("print comparable", "fn show<T: comparable>(x: T) { print(x); } show(true);"),
+		newTypeCheckerTestFail("operation not permitted by constraint", "fn add<T>(a: T, b: T): T { return a + b; }", "cannot perform + operation on T and T"),
+		newTypeCheckerTestFail("ordering on comparable", "fn less<T: comparable>(a: T, b: T): bool { return a < b; }", "cannot perform < operation on T and T"),
+		newTypeCheckerTestFail("mixing type parameter and concrete type", "fn inc<T: numeric>(a: T): T { return a + 1; }", "cannot perform + operation on T and int"),
+		newTypeCheckerTestFail("unsatisfied constraint", "fn neg<T: numeric>(a: T): T { return -a; } neg(\"x\");", "type string does not satisfy constraint numeric of type parameter T of function neg"),
+		newTypeCheckerTestFail("conflicting type arguments", "fn pair<T>(a: T, b: T) {} pair(1, true);", "conflicting types int and bool for type parameter T of function pair"),
+		newTypeCheckerTestFail("uninferable type parameter", "fn zero<T>(): int { return 0; } zero();", "cannot infer type parameter T of function zero"),
+		newTypeCheckerTestFail("unknown constraint", "fn f<T: foo>(a: T) {}", "unknown constraint foo of type parameter T"),
+		newTypeCheckerTestFail("duplicate type parameter", "fn f<T, T>(a: T) {}", "duplicate type parameter T in function f"),
+		newTypeCheckerTestFail("type parameter shadowing type", "fn f<int>(a: int) {}", "type parameter int of function f shadows type int"),
+		newTypeCheckerTestFail("print unconstrained", "fn show<T>(x: T) { print(x); }", "invalid argument at 0 idx to print"),
+		newTypeCheckerTestFail("generic function as value", "fn id<T>(x: T): T { return x; } let f = id;", "generic function id cannot be used as a value"),
+		newTypeCheckerTestFail("unbounded instantiation", "fn nest<T>(x: T) { nest([x]); } nest(1);", "too many instances of generic function nest"),
+		newTypeCheckerTestFail("growing type arguments", "fn f<T>(x: T, n: int): int { if (n == 0) { return 0; } return f((x, x), n - 1); } f(1, 3);", "type arguments of generic function f grow too large"),
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			runTypeCheckerTest(t, tt)
+		})
+	}
+}
+
+func TestTypeChecker_Optionals(t *testing.T) {
+	tests := []typeCheckerTestItem{
+		newTypeCheckerTest("none and values", "let x: int? = none; x = 1; let y: float? = 2;"),
+		newTypeCheckerTest("returned", "fn find(xs: []int, v: int): int? { for (x in xs) { if (x == v) { return x; } } return none; }"),
+		newTypeCheckerTest("coalesce", "let x: int? = none; let y: int = x ?? 0;"),
+		newTypeCheckerTest("none branch", "let c = true; let y: int? = if (c) { none } else { 3 };"),
+		newTypeCheckerTest("returned none branch", "fn f(c: bool): int? { return if (c) { none } else { 3 }; }"),
+		newTypeCheckerTest("none arm", "enum E { A, B } fn f(e: E): float? { return match (e) { A => none, B => 1 }; }"),
+		newTypeCheckerTestFail("mismatched branch", "let c = true; let y: int? = if (c) { none } else { \"a\" };", "cannot use string as value of if expression branch of type int?"),
+		newTypeCheckerTest("coalesce chain", "let x: int? = none; let y: int? = 2; let z: int = x ?? y ?? 0;"),
+		newTypeCheckerTest("if let", "let x: int? = 1; let y = if (let v = x) { v + 1 } else { 0 };"),
+		newTypeCheckerTest("narrowed by condition", "let x: int? = 1; if (x != none) { print(x + 1); }"),
+		newTypeCheckerTest("narrowed by else branch", "let x: int? = 1; if (x == none) { print(0); } else { print(x + 1); }"),
+		newTypeCheckerTest("narrowed by &&", "let x: int? = 1; let y = x != none && x > 0;"),
+		newTypeCheckerTest("narrowed by ||", "let x: int? = 1; let y = x == none || x > 0;"),
+		newTypeCheckerTest("narrowed by negation", "let x: int? = 1; if (!(x == none)) { print(x + 1); }"),
+		newTypeCheckerTestFail("narrowed global assigned by function", "let x: int? = 1; fn clear() { x = none; } if (x != none) { clear(); let y: int = x; }", "cannot assign int? to variable y of type int"),
+		newTypeCheckerTest("narrowed after early return", "fn f(x: int?): int { if (x == none) { return 0; } return x; }"),
+		newTypeCheckerTest("narrowed after break", "let x: int? = 1; while (true) { if (x == none) { break; } print(x); x = none; }"),
+		newTypeCheckerTest("narrowed by loop condition", "let x: int? = 3; while (x != none && x > 0) { x -= 1; }"),
+		newTypeCheckerTest("stays narrowed when assigned a value", "let x: int? = 1; if (x != none) { x = 2; print(x); }"),
+		newTypeCheckerTestFail("used without check", "let x: int? = 1; let y = x + 1;", "optional values must be checked for none before they are used"),
+		newTypeCheckerTestFail("printed without check", "let x: int? = 1; print(x);", "optional values must be checked for none before they are used"),
+		newTypeCheckerTestFail("as condition", "let x: bool? = true; if (x) {}", "optional values must be checked for none before they are used"),
+		newTypeCheckerTestFail("assigned to non-optional", "let x: int? = 1; let y: int = x;", "cannot assign int? to variable y of type int"),
+		newTypeCheckerTestFail("narrowing outlives branch", "let x: int? = 1; if (x != none) { print(x); } print(x);", "optional values must be checked for none before they are used"),
+		newTypeCheckerTestFail("assigned none while narrowed", "let x: int? = 1; if (x != none) { x = none; print(x); }", "optional values must be checked for none before they are used"),
+		newTypeCheckerTestFail("assigned in loop body", "let x: int? = 1; if (x != none) { while (true) { print(x); x = none; } }", "optional values must be checked for none before they are used"),
+		newTypeCheckerTestFail("assigned in closure", "let x: int? = 1; let reset = fn() { x = none; }; if (x != none) { print(x); }", "optional values must be checked for none before they are used"),
+		newTypeCheckerTestFail("not narrowed in closure", "let x: int? = 1; if (x != none) { let f = fn(): int { return x; }; }", "cannot return int? from function"),
+		newTypeCheckerTestFail("inferred from none", "let x = none;", "cannot infer type of variable x from none"),
+		newTypeCheckerTestFail("coalesce on non-optional", "let x = 1; let y = x ?? 2;", "left operand of ?? must be optional, got int"),
+		newTypeCheckerTestFail("coalesce mismatch", "let x: int? = 1; let y = x ?? \"a\";", "cannot use string as default value for int?"),
+		newTypeCheckerTestFail("if let on non-optional", "let x = 1; if (let v = x) {}", "if let requires an optional value, got int"),
+		newTypeCheckerTestFail("if let binding scope", "let x: int? = 1; if (let v = x) {} print(v);", "unknown identifier: v"),
+		newTypeCheckerTestFail("optional void", "fn f(): void? {}", "invalid optional type: void?"),
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			runTypeCheckerTest(t, tt)
+		})
+	}
+}
+
+func TestTypeChecker_Results(t *testing.T) {
+	tests := []typeCheckerTestItem{
+		newTypeCheckerTest("ok and err", "fn f(x: int): Result<int, string> { if (x < 0) { return err(\"negative\"); } return ok(x); }"),
+		newTypeCheckerTest("widened value", "let r: Result<float, string> = ok(1);"),
+		newTypeCheckerTest("propagated", "fn f(): Result<int, string> { return ok(1); } fn g(): Result<bool, string> { let x = f()?; return ok(x > 0); }"),
+		newTypeCheckerTest("propagated from closure", "fn f(): Result<int, string> { return ok(1); } let g = fn(): Result<int, string> { return ok(f()? + 1); };"),
+		newTypeCheckerTest("matched", "let r: Result<int, string> = err(\"x\"); let n = match (r) { ok(v) => v, err(e) => 0 };"),
+		newTypeCheckerTest("if branches", "let c = true; let r: Result<int, string> = if (c) { ok(1) } else { err(\"x\") };"),
+		newTypeCheckerTest("match arms", "enum E { A, B } fn f(e: E): Result<int, string> { return match (e) { A => ok(1), B => err(\"b\") }; }"),
+		newTypeCheckerTest("generic", "fn unwrapOr<T, E>(r: Result<T, E>, d: T): T { return match (r) { ok(v) => v, err(e) => d }; } let r: Result<int, string>; let x: int = unwrapOr(r, 0);"),
+		newTypeCheckerTestFail("ok without expected type", "let r = ok(1);", "cannot infer type of result created by ok, it can only be used where a Result type is expected"),
+		newTypeCheckerTestFail("mismatched value", "let r: Result<int, string> = ok(true);", "cannot use bool as value of Result<int, string>"),
+		newTypeCheckerTestFail("mismatched error", "let r: Result<int, string> = err(1);", "cannot use int as error of Result<int, string>"),
+		newTypeCheckerTestFail("wrong number of arguments", "let r: Result<int, string> = err();", "wrong number of arguments to err. expected 1 arguments, got 0 arguments"),
+		newTypeCheckerTestFail("try on non-result", "fn f(): Result<int, string> { let x = 1?; return ok(x); }", "? operator requires a Result value, got int"),
+		newTypeCheckerTestFail("try outside of function", "let r: Result<int, string> = ok(1); let x = r?;", "? operator can only be used inside of a function"),
+		newTypeCheckerTestFail("try in function not returning result", "fn f(r: Result<int, string>): int { return r?; }", "? operator can only be used in a function returning a Result, function f returns int"),
+		newTypeCheckerTestFail("try with different error type", "fn f(r: Result<int, int>): Result<int, string> { return ok(r?); }", "cannot propagate error of type int from function f with return type Result<int, string>"),
+		newTypeCheckerTestFail("non-exhaustive match", "let r: Result<int, string> = ok(1); match (r) { ok(v) => { print(v); } }", "missing variants: err"),
+		newTypeCheckerTestFail("void value", "let r: Result<void, string>;", "invalid result type: Result<void, string>"),
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			runTypeCheckerTest(t, tt)
+		})
+	}
+}
+
+func TestTypeChecker_Tuples(t *testing.T) {
+	tests := []typeCheckerTestItem{
+		newTypeCheckerTest("returned tuple", "fn divmod(a: int, b: int): (int, int) { return (a / b, a % b); } let (q, r) = divmod(7, 2); let x: int = q + r;"),
+		newTypeCheckerTest("element access", "let t = (1, \"a\", (true, 2.5)); let s: string = t.1; let f: float = t.2.1;"),
+		newTypeCheckerTest("converted elements", "let t: (float, string?) = (1, none);"),
+		newTypeCheckerTest("element assignment", "let t = (1, 2); t.0 = 3; t.1 += 1;"),
+		newTypeCheckerTest("ignored element", "let (_, b) = (1, \"b\"); let s: string = b;"),
+		newTypeCheckerTest("generic", "fn swap<T, U>(p: (T, U)): (U, T) { return (p.1, p.0); } let s: (string, int) = swap((1, \"a\"));"),
+		newTypeCheckerTest("destructured in for loop", "for (let (i, n) = (0, 3); i < n; i++) { print(i); }"),
+		newTypeCheckerTestFail("element out of range", "let t = (1, 2); let x = t.2;", "tuple (int, int) has no element 2"),
+		newTypeCheckerTestFail("mismatched element", "let t: (int, string) = (1, 2);", "cannot use int as element 1 of (int, string)"),
+		newTypeCheckerTestFail("mismatched size", "let t: (int, int) = (1, 2, 3);", "cannot assign (int, int, int) to variable t of type (int, int)"),
+		newTypeCheckerTestFail("none element", "let t = (1, none);", "cannot infer type of tuple element at 1 idx from none"),
+		newTypeCheckerTestFail("destructuring wrong count", "let (a, b) = (1, 2, 3);", "cannot destructure (int, int, int) into 2 variables"),
+		newTypeCheckerTestFail("destructuring non-tuple", "let (a, b) = 1;", "cannot destructure value of type int, only tuples can be destructured"),
+		newTypeCheckerTestFail("destructuring duplicate", "let (a, a) = (1, 2);", "cannot redeclare variable: a"),
+		newTypeCheckerTestFail("void element type", "let t: (int, void);", "invalid tuple element type: void"),
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			runTypeCheckerTest(t, tt)
+		})
+	}
+}
+
+func TestTypeChecker_SizedNumerics(t *testing.T) {
+	tests := []typeCheckerTestItem{
+		newTypeCheckerTest("fitting literals", "let a: u8 = 255; let b: i8 = -128; let c: u32 = 4000000000; let d: f32 = 1.5;"),
+		newTypeCheckerTest("widening", "let a: u8 = 1; let b: i32 = a; let c: u16 = a; let d: f32 = 2.5; let e: float = d; let f: f32 = a;"),
+		newTypeCheckerTest("explicit conversions", "let n = 200; let a: i8 = i8(n); let b: u8 = u8(n - 201); let c: int = int(b); let d: f32 = f32(1.5); let e: i64 = i64(d);"),
+		newTypeCheckerTest("constant conversions", "const A = u8(255); const B = i8(-128); const C = f32(1); let x: u8 = A; let c: char = char(A); let f: int = i8(2.5);"),
+		newTypeCheckerTest("untyped constant operands", "let x: u8 = 1; let y: u8 = x + 1; let z: u8 = 1 + x; let w: bool = x < 10;"),
+		newTypeCheckerTest("sized array literal", "let xs: [u8; 3] = [1, 2, 3];"),
+		newTypeCheckerTest("typed constant", "const A: u8 = 200; const B = A + 55; let x: u8 = B;"),
+		newTypeCheckerTest("sized indices", "let i: u8 = 1; let j: i32 = 0; let a = [1, 2]; let l: []int = [1]; let s = \"ab\"; let x: int = a[i] + l[j]; let c: char = s[i];"),
+		newTypeCheckerTest("sized map keys", "let m: map[i32]int = {}; let k: i32 = -1; set(m, k, 1); let n: map[u64]string = {1: \"a\"}; let s: string = get(n, 1);"),
+		newTypeCheckerTest("sized if branches", "let c = true; let a: u8 = 1; let x = if (c) { a } else { 2 }; let b: i16 = 1; let d: i32 = 2; let y = if (c) { b } else { d }; let f: f32 = 1.5; let z = if (c) { f } else { 2.5 }; let v: (u8, i32, f32) = (x, y, z);"),
+		newTypeCheckerTest("sized array elements", "let a: u8 = 1; let u: u16 = 2; let xs = [a, 2]; let ys = [a, u]; let zs = [a, 300]; let v: (u8, u16, int) = (xs[0], ys[0], zs[0]);"),
+		newTypeCheckerTest("sized map values", "let a: u8 = 1; let m = { 1: a, 2: 2 }; let v: u8 = get(m, 1);"),
+		newTypeCheckerTest("sized match arms", "enum E { A, B } let e = E::A; let a: u8 = 1; let b: i16 = 2; let x = match (e) { A => a, B => b }; let y = match (e) { A => 1, B => a }; let v: (i16, u8) = (x, y);"),
+		newTypeCheckerTestFail("mismatched sized branches", "let c = true; let a: u32 = 1; let b: i32 = 2; let x = if (c) { a } else { b };", "mismatched types of if expression branches: u32 and i32"),
+		newTypeCheckerTestFail("f32 map key", "let m: map[f32]int;", "type f32 cannot be used as map key"),
+		newTypeCheckerTestFail("literal overflow", "let x: u8 = 256;", "constant 256 overflows u8"),
+		newTypeCheckerTestFail("negative unsigned literal", "let x: u8 = -1;", "constant -1 overflows u8"),
+		newTypeCheckerTestFail("array element overflow", "let xs: [i8; 2] = [1, 128];", "constant 128 overflows i8"),
+		newTypeCheckerTestFail("constant overflow", "const A: u8 = 200; const B = A + 100;", "constant 300 overflows u8"),
+		newTypeCheckerTestFail("conversion overflow", "let z = u8(300);", "constant 300 overflows u8"),
+		newTypeCheckerTestFail("constant conversion overflow", "const Z = u8(300);", "constant 300 overflows u8"),
+		newTypeCheckerTestFail("negative unsigned conversion", "let z = u8(-1);", "constant -1 overflows u8"),
+		newTypeCheckerTestFail("char conversion overflow", "let z = i8('é');", "constant 233 overflows i8"),
+		newTypeCheckerTestFail("narrowing", "let x: i32 = 1; let y: i16 = x;", "cannot assign i32 to variable y of type i16"),
+		newTypeCheckerTestFail("unsigned to signed of same size", "let x: u32 = 1; let y: i32 = x;", "cannot assign u32 to variable y of type i32"),
+		newTypeCheckerTestFail("float narrowing", "let x: float = 1.5; let y: f32 = x;", "cannot assign float to variable y of type f32"),
+		newTypeCheckerTestFail("negated unsigned", "let x: u8 = 1; let y = -x;", "cannot perform - operation on u8"),
+		newTypeCheckerTestFail("invalid conversion", "let x = u8(true);", "cannot convert bool to u8"),
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			runTypeCheckerTest(t, tt)
+		})
+	}
+}
+
+func TestTypeChecker_Chars(t *testing.T) {
+	tests := []typeCheckerTestItem{
+		newTypeCheckerTest("literal", "let c: char = 'a'; let d = '\\n';"),
+		newTypeCheckerTest("comparison", "let c = 'a'; let b: bool = c < 'b' && c != 'z';"),
+		newTypeCheckerTest("conversions", "let n: int = int('a'); let c: char = char(n + 1); let u: u8 = u8(c);"),
+		newTypeCheckerTest("string indexing", "let s = \"abc\"; let c: char = s[0]; let b = s[len(s) - 1] == 'c';"),
+		newTypeCheckerTest("map key", "let m: map[char]int = {'a': 1};"),
+		newTypeCheckerTest("string iteration", "let n = 0; for (c in \"héllo\") { if (c == 'é') { n += 1; } }"),
+		newTypeCheckerTestFail("string iteration as int", "for (c in \"abc\") { let n: int = c; }", "cannot assign char to variable n of type int"),
+		newTypeCheckerTest("constant", "const C = 'a'; const B = C < 'b'; let b: bool = B;"),
+		newTypeCheckerTest("ordered type argument", "fn max<T: ordered>(a: T, b: T): T { if (a > b) { return a; } return b; } let c: char = max('a', 'b');"),
+		newTypeCheckerTestFail("arithmetic", "let c = 'a' + 'b';", "cannot perform + operation on char and char"),
+		newTypeCheckerTestFail("int as char", "let c: char = 97;", "cannot assign int to variable c of type char"),
+		newTypeCheckerTestFail("compared with int", "let b = 'a' == 97;", "cannot perform == operation on char and int"),
+		newTypeCheckerTestFail("invalid code point", "let c = char(55296);", "constant 55296 is not a valid char"),
+		newTypeCheckerTestFail("float conversion", "let f = float('a');", "cannot convert char to float"),
+		newTypeCheckerTestFail("assignment to string", "let s = \"abc\"; s[0] = 'x';", "cannot assign to char of string s, strings are immutable"),
+		newTypeCheckerTestFail("negative string index", "let s = \"abc\"; let c = s[-1];", "negative index -1 into string"),
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			runTypeCheckerTest(t, tt)
+		})
+	}
+}
+
+func TestTypeChecker_StringInterpolation(t *testing.T) {
+	tests := []typeCheckerTestItem{
+		newTypeCheckerTest("printable types", "let x = 1; let u: u8 = 2; let s: string = \"${x} ${u} ${1.5} ${true} ${'c'} ${\"s\"}\";"),
+		newTypeCheckerTest("expressions", "fn f(x: int): int { return x * 2; } let s = \"${f(1) + 1} ${if (true) { 1 } else { 2 }}\"; let n: int = len(s);"),
+		newTypeCheckerTest("nested", "let x = 1; let s = \"a ${\"b ${x}\"}\";"),
+		newTypeCheckerTest("concatenation", "let x = 1; let s = \"x = \" + \"${x}\";"),
+		newTypeCheckerTestFail("array", "let s = \"${[1, 2]}\";", "cannot interpolate value of type [int; 2]"),
+		newTypeCheckerTestFail("void", "fn f() {} let s = \"${f()}\";", "cannot interpolate value of type void"),
+		newTypeCheckerTestFail("optional", "let o: int? = 1; let s = \"${o}\";", "cannot interpolate value of type int? (optional values must be checked for none before they are used)"),
+		newTypeCheckerTestFail("unknown identifier", "let s = \"${x}\";", "unknown identifier: x"),
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			runTypeCheckerTest(t, tt)
+		})
+	}
+}
+
+func TestTypeChecker_TypeAnnotations(t *testing.T) {
+	tests := []typeCheckerTestItem{
+		newTypeCheckerTest("annotated", "let x: int = 5;"),
+		newTypeCheckerTest("int literal widening", "let x: float = 5;"),
+		newTypeCheckerTest("int variable widening", "let i = 5; let x: float = i;"),
+		newTypeCheckerTest("mixed arithmetic", "let i = 5; let x = i * 1.5;"),
+		newTypeCheckerTest("mixed comparison", "let i = 5; let x = i < 1.5;"),
+		newTypeCheckerTest("zero initialized", "let x: bool; x = true;"),
+		newTypeCheckerTest("widening argument", "fn half(x: float): float { return x / 2; } half(3);"),
+		newTypeCheckerTestFail("narrowing", "let x: int = 5.5;", "cannot assign float to variable x of type int"),
+		newTypeCheckerTestFail("mismatch", "let x: bool = 1;", "cannot assign int to variable x of type bool"),
+		newTypeCheckerTestFail("assignment mismatch", "let x = 1; x = true;", "cannot assign bool to variable x of type int"),
+		newTypeCheckerTestFail("unknown type", "let x: foo = 1;", "unknown type: foo"),
+		newTypeCheckerTestFail("void", "let x: void;", "cannot assign void value to variable: x"),
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			runTypeCheckerTest(t, tt)
+		})
+	}
+}
+
+func TestTypeChecker_WhileLoops(t *testing.T) {
+	tests := []typeCheckerTestItem{
+		newTypeCheckerTest("simple", "let i = 0; while (i < 10) { i = i + 1; }"),
+		newTypeCheckerTest("infinite loop returns", "fn f(): int { let i = 0; while (true) { if (i > 5) { return i; } i = i + 1; } }"),
+		newTypeCheckerTestFail("non-boolean condition", "while (1) { print(1); }", "non-boolean condition in while loop, got int"),
+		newTypeCheckerTestFail("body scope", "while (true) { let x = 1; } x = 2;", "unknown identifier: x"),
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			runTypeCheckerTest(t, tt)
+		})
+	}
+}
+
+func TestTypeChecker_ForLoops(t *testing.T) {
+	tests := []typeCheckerTestItem{
+		newTypeCheckerTest("simple", "for (let i = 0; i < 10; i = i + 1) { print(i); }"),
+		newTypeCheckerTest("empty clauses", "let i = 0; for (;;) { i = i + 1; }"),
+		newTypeCheckerTest("shadowing initializer", "let i = true; for (let i = 0; i < 10; i = i + 1) { print(i); } i = false;"),
+		newTypeCheckerTest("infinite loop returns", "fn f(): int { for (let i = 0;; i = i + 1) { if (i > 5) { return i; } } }"),
+		newTypeCheckerTestFail("non-boolean condition", "for (let i = 0; i; i = i + 1) { print(i); }", "non-boolean condition in for loop, got int"),
+		newTypeCheckerTestFail("initializer scope", "for (let i = 0; i < 10; i = i + 1) { print(i); } i = 2;", "unknown identifier: i"),
+		newTypeCheckerTestFail("update mismatch", "for (let i = 0; i < 10; i = true) { print(i); }", "cannot assign bool to variable i of type int"),
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			runTypeCheckerTest(t, tt)
+		})
+	}
+}
+
+func TestTypeChecker_LoopControl(t *testing.T) {
+	tests := []typeCheckerTestItem{
+		newTypeCheckerTest("break", "while (true) { break; }"),
+		newTypeCheckerTest("continue", "for (let i = 0; i < 10; i = i + 1) { continue; }"),
+		newTypeCheckerTest("labeled", "outer: while (true) { for (;;) { break outer; } }"),
+		newTypeCheckerTest("break in nested loop still returns", "fn f(): int { while (true) { while (true) { break; } return 1; } }"),
+		newTypeCheckerTestFail("break outside loop", "break;", "break statement outside of loop"),
+		newTypeCheckerTestFail("continue outside loop", "if (true) { continue; }", "continue statement outside of loop"),
+		newTypeCheckerTestFail("loop in caller", "fn f() { break; } while (true) { f(); }", "break statement outside of loop"),
+		newTypeCheckerTestFail("unknown label", "while (true) { break outer; }", "unknown loop label: outer"),
+		newTypeCheckerTestFail("duplicate label", "a: while (true) { a: while (true) { break a; } }", "duplicate loop label: a"),
+		newTypeCheckerTestFail("break out of infinite loop", "fn f(): int { while (true) { break; } }", "must return a value of type int on all paths"),
+		newTypeCheckerTestFail("labeled break out of infinite loop", "fn f(): int { a: for (;;) { while (true) { break a; } } }", "must return a value of type int on all paths"),
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			runTypeCheckerTest(t, tt)
+		})
+	}
+}
+
+func TestTypeChecker_Constants(t *testing.T) {
+	tests := []typeCheckerTestItem{
+		newTypeCheckerTest("literal", "const N = 5;"),
+		newTypeCheckerTest("constant expression", "const N = 5; const M = (N * 2 + 1) ** 2; const OK = M > N;"),
+		newTypeCheckerTest("annotated widening", "const N: float = 5;"),
+		newTypeCheckerTest("visible in functions", "const N = 5; fn f(): int { return N; }"),
+		newTypeCheckerTest("declared after function", "fn f(): int { return N; } const N = 5;"),
+		newTypeCheckerTest("declared out of order", "fn f(): int { return M; } const M = N * 2; const N = 5;"),
+		newTypeCheckerTest("shadowed by variable", "const N = 5; { let N = 1; N = 2; }"),
+		newTypeCheckerTestFail("assignment", "const N = 5; N = 6;", "cannot assign to constant: N"),
+		newTypeCheckerTestFail("variable initializer", "let x = 1; const N = x;", "initializer of constant N is not a compile-time constant: x is not a constant"),
+		newTypeCheckerTestFail("call initializer", "fn f(): int { return 1; } const N = f();", "cannot be evaluated at compile time"),
+		newTypeCheckerTestFail("division by zero", "const N = 1 / 0;", "division by zero"),
+		newTypeCheckerTest("largest int", "const N = 9223372036854775806 + 1; const M = -9223372036854775807 - 1; const P = 2 ** 62 * -2;"),
+		newTypeCheckerTestFail("int overflow", "const N = 2 ** 62 * 4;", "constant (4611686018427387904 * 4) overflows int"),
+		newTypeCheckerTest("large exponent", "const N = 1 ** 9000000000000000000; const M = (-1) ** 9000000000000000001; let a = [1]; let x = a[0 * (1 ** 9000000000000000000)];"),
+		newTypeCheckerTest("largest power", "const N = 2 ** 62; const M = (-2) ** 63;"),
+		newTypeCheckerTestFail("power overflow", "const N = 2 ** 63;", "overflows int"),
+		newTypeCheckerTestFail("int underflow", "const N = -9223372036854775807 - 2;", "overflows int"),
+		newTypeCheckerTestFail("annotation mismatch", "const N: bool = 1;", "cannot assign int to constant N of type bool"),
+		newTypeCheckerTestFail("redeclare", "let N = 1; const N = 2;", "cannot redeclare variable: N"),
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			runTypeCheckerTest(t, tt)
+		})
+	}
+}
+
+func TestTypeChecker_UnaryExpressions(t *testing.T) {
+	tests := []typeCheckerTestItem{
+		newTypeCheckerTest("negation", "let x = 1; let y: int = -x; let z: float = -1.5;"),
+		newTypeCheckerTest("logical not", "let x: bool = !(1 > 2);"),
+		newTypeCheckerTest("increment", "let x = 1; x++; ++x; let y: int = x--;"),
+		newTypeCheckerTest("float increment", "let x = 1.5; x++;"),
+		newTypeCheckerTest("constant folding", "const N = -5; const B = !true;"),
+		newTypeCheckerTestFail("negate bool", "-true;", "cannot perform - operation on bool"),
+		newTypeCheckerTestFail("not int", "!1;", "cannot perform ! operation on int"),
+		newTypeCheckerTestFail("increment literal", "5++;", "operand of ++ operation must be a variable"),
+		newTypeCheckerTestFail("increment bool", "let b = true; b++;", "cannot perform ++ operation on bool"),
+		newTypeCheckerTestFail("increment constant", "const N = 1; --N;", "cannot assign to constant: N"),
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			runTypeCheckerTest(t, tt)
+		})
+	}
+}
+
+func TestTypeChecker_LogicalExpressions(t *testing.T) {
+	tests := []typeCheckerTestItem{
+		newTypeCheckerTest("and", "let x: bool = 1 < 2 && 2 < 3;"),
+		newTypeCheckerTest("or", "let x: bool = true || false;"),
+		newTypeCheckerTest("guard", "fn check(i: int): bool { return i > 0; } let i = 0; let ok = i < 10 && check(i);"),
+		newTypeCheckerTest("constant folding", "const B = true && !false || false;"),
+		newTypeCheckerTestFail("int operands", "1 && 2;", "cannot perform && operation on int and int"),
+		newTypeCheckerTestFail("mixed operands", "true || 1;", "cannot perform || operation on bool and int"),
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			runTypeCheckerTest(t, tt)
+		})
+	}
+}
+
+func TestTypeChecker_ArithmeticExpressions(t *testing.T) {
+	tests := []typeCheckerTestItem{
+		newTypeCheckerTest("modulo", "let x = 17; let y: int = x % 5; let z: float = 7.5 % 2;"),
+		newTypeCheckerTest("exponent", "let x = 2; let y: int = x ** 10; let z: float = x ** 0.5;"),
+		newTypeCheckerTest("constant folding", "const M = 17 % 5; const P = 2 ** 3 ** 2; const N = 2 ** -1; const F = 7.5 % 2;"),
+		newTypeCheckerTestFail("modulo bool", "true % 2;", "cannot perform % operation on bool and int"),
+		newTypeCheckerTestFail("constant modulo by zero", "const N = 1 % 0;", "division by zero"),
+		newTypeCheckerTestFail("int exponent narrowing", "let x: int = 2 ** 0.5;", "cannot assign float to variable x of type int"),
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			runTypeCheckerTest(t, tt)
+		})
+	}
+}
+
+func TestTypeChecker_CompoundAssignments(t *testing.T) {
+	tests := []typeCheckerTestItem{
+		newTypeCheckerTest("int", "let x = 1; x += 2; x -= 1; x *= 3; x /= 2;"),
+		newTypeCheckerTest("float widening", "let x = 1.5; x += 1; x /= 2;"),
+		newTypeCheckerTest("in loop update", "let sum = 0; for (let i = 0; i < 10; i += 1) { sum += i; }"),
+		newTypeCheckerTestFail("int narrowing", "let x = 1; x += 1.5;", "cannot perform += operation on int and float"),
+		newTypeCheckerTestFail("bool", "let b = true; b *= 2;", "cannot perform *= operation on bool"),
+		newTypeCheckerTestFail("constant", "const N = 1; N += 1;", "cannot assign to constant: N"),
+		newTypeCheckerTestFail("unknown variable", "x -= 1;", "unknown identifier: x"),
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			runTypeCheckerTest(t, tt)
+		})
+	}
+}
+
+func TestTypeChecker_Strings(t *testing.T) {
+	tests := []typeCheckerTestItem{
+		newTypeCheckerTest("concatenation", "let s: string = \"a\" + \"b\"; s += \"c\";"),
+		newTypeCheckerTest("comparison", "let s = \"a\"; let b: bool = s < \"b\" && s != \"c\";"),
+		newTypeCheckerTest("print", "print(\"value:\", 1, true);"),
+		newTypeCheckerTest("function", "fn greet(name: string): string { return \"hi \" + name; } print(greet(\"x\"));"),
+		newTypeCheckerTestFail("concatenate int", "let s = \"a\" + 1;", "cannot perform + operation on string and int"),
+		newTypeCheckerTestFail("compound subtraction", "let s = \"a\"; s -= \"b\";", "cannot perform -= operation on string"),
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			runTypeCheckerTest(t, tt)
+		})
+	}
+}
+
+func TestTypeChecker_IfExpressions(t *testing.T) {
+	tests := []typeCheckerTestItem{
+		newTypeCheckerTest("value", "let c = true; let y: int = if (c) { 1 } else { 2 };"),
+		newTypeCheckerTest("else if chain", "let n = 5; let s: string = if (n < 0) { \"neg\" } else if (n == 0) { \"zero\" } else { \"pos\" };"),
+		newTypeCheckerTest("widening", "let c = true; let y: float = if (c) { 1 } else { 2.5 };"),
+		newTypeCheckerTest("statements before value", "let c = true; let y = if (c) { let x = 2; x * 2 } else { 0 };"),
+		newTypeCheckerTest("returning branch", "fn f(n: int): int { let v = if (n < 0) { return 0; } else { n }; return v; }"),
+		newTypeCheckerTest("continuing branch", "for (n in [1, 2, 3]) { let r = if (n == 2) { continue; } else { n }; print(r); }"),
+		newTypeCheckerTest("breaking arm", "enum E { A, B } for (e in [E::A, E::B]) { let v = match (e) { A => 1, B => { break; } }; print(v); }"),
+		newTypeCheckerTestFail("all branches exit", "for (n in [1]) { let r = if (n == 2) { continue; } else { break; }; }", "if expression does not produce a value, both of its branches exit"),
+		newTypeCheckerTest("statement without else", "let c = true; if (c) { print(1); }"),
+		newTypeCheckerTestFail("missing else", "let c = true; let y = if (c) { 1 };", "if expression must have an else branch to produce a value"),
+		newTypeCheckerTestFail("mismatched branches", "let c = true; let y = if (c) { 1 } else { \"a\" };", "mismatched types of if expression branches: int and string"),
+		newTypeCheckerTestFail("branch without value", "let c = true; let y = if (c) { let x = 1; } else { 2 };", "if expression branch must end with an expression"),
+		newTypeCheckerTestFail("void branch", "let c = true; let y = if (c) { print(1) } else { 2 };", "if expression branch does not produce a value"),
+		newTypeCheckerTestFail("non-boolean condition", "let y = if (1) { 1 } else { 2 };", "non-boolean condition in if expression"),
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			runTypeCheckerTest(t, tt)
+		})
+	}
+}
+
+func TestTypeChecker_Arrays(t *testing.T) {
+	tests := []typeCheckerTestItem{
+		newTypeCheckerTest("literal", "let a: [int; 3] = [1, 2, 3];"),
+		newTypeCheckerTest("element widening", "let a: [float; 2] = [1, 2.5]; let b: [float; 2] = [1, 2];"),
+		newTypeCheckerTest("nested", "let grid: [[int; 2]; 2] = [[1, 2], [3, 4]]; let x: int = grid[1][0];"),
+		newTypeCheckerTest("indexing", "let a = [1, 2, 3]; let i = 1; let x: int = a[i] + a[0];"),
+		newTypeCheckerTest("index assignment", "let a = [1, 2, 3]; a[0] = 5; a[1] += 2;"),
+		newTypeCheckerTest("zero initialized", "let a: [bool; 4]; a[3] = true;"),
+		newTypeCheckerTest("function parameter", "fn first(a: [int; 2]): int { return a[0]; } print(first([1, 2]));"),
+		newTypeCheckerTestFail("empty literal", "let a = [];", "cannot infer element type of empty array literal"),
+		newTypeCheckerTestFail("mismatched elements", "let a = [1, true];", "mismatched types of array elements: int and bool"),
+		newTypeCheckerTestFail("size mismatch", "let a: [int; 2] = [1, 2, 3];", "cannot assign [int; 3] to variable a of type [int; 2]"),
+		newTypeCheckerTestFail("index non-array", "let x = 1; x[0];", "cannot index into value of type int"),
+		newTypeCheckerTestFail("float index", "let a = [1, 2]; a[1.5];", "array index must be of an integer type, got float"),
+		newTypeCheckerTestFail("constant index out of bounds", "const N = 3; let a = [1, 2, 3]; a[N];", "index 3 out of bounds for array of length 3"),
+		newTypeCheckerTestFail("element type mismatch", "let a = [1, 2]; a[0] = \"x\";", "cannot assign string to element of type int"),
+		newTypeCheckerTestFail("assign to temporary", "fn f(): [int; 1] { return [1]; } f()[0] = 2;", "it is not a variable"),
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			runTypeCheckerTest(t, tt)
+		})
+	}
+}
+
+func TestTypeChecker_Lists(t *testing.T) {
+	tests := []typeCheckerTestItem{
+		newTypeCheckerTest("literal", "let xs: []int = [1, 2, 3]; let x: int = xs[0];"),
+		newTypeCheckerTest("empty literal", "let xs: []string = []; push(xs, \"a\");"),
+		newTypeCheckerTest("element widening", "let xs: []float = [1, 2.5]; push(xs, 3);"),
+		newTypeCheckerTest("push pop len", "let xs: []int; push(xs, 1); let x: int = pop(xs); let n: int = len(xs);"),
+		newTypeCheckerTest("len of array and string", "let a: int = len([1, 2]) + len(\"abc\");"),
+		newTypeCheckerTest("index assignment", "let xs: []int = [1]; xs[0] = 2; xs[0] *= 3;"),
+		newTypeCheckerTest("nested", "let grid: [][]int = [[1], [2, 3]]; push(grid[0], 4);"),
+		newTypeCheckerTest("function parameter", "fn sum(xs: []int): int { let s = 0; for (x in xs) { s += x; } return s; } print(sum([1, 2]));"),
+		newTypeCheckerTest("for in array", "let total = 0.0; for (x in [1.5, 2.5]) { total += x; }"),
+		newTypeCheckerTest("for in with break", "outer: for (x in [1, 2]) { for (y in [3]) { if (x == y) { break outer; } continue; } }"),
+		newTypeCheckerTestFail("push wrong type", "let xs: []int = []; push(xs, \"a\");", "cannot push string to []int"),
+		newTypeCheckerTestFail("pop non-list", "let a = [1]; pop(a);", "first argument of pop must be a list, got [int; 1]"),
+		newTypeCheckerTestFail("len of int", "len(1);", "cannot get length of int"),
+		newTypeCheckerTestFail("negative index", "let xs: []int = [1]; xs[-1];", "negative index -1 into list"),
+		newTypeCheckerTestFail("mismatched element", "let xs: []int = [1, true];", "cannot use bool as element of []int"),
+		newTypeCheckerTestFail("iterate int", "for (x in 5) {}", "cannot iterate over value of type int"),
+		newTypeCheckerTestFail("loop variable scope", "for (x in [1]) {} print(x);", "unknown identifier: x"),
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			runTypeCheckerTest(t, tt)
+		})
+	}
+}
+
+func TestTypeChecker_Maps(t *testing.T) {
+	tests := []typeCheckerTestItem{
+		newTypeCheckerTest("literal", "let m = { \"a\": 1, \"b\": 2 }; let x: int = get(m, \"a\");"),
+		newTypeCheckerTest("empty literal", "let m: map[int]string = {}; set(m, 1, \"one\");"),
+		newTypeCheckerTest("zero initialized", "let m: map[bool]int; set(m, true, 1); let n: int = len(m);"),
+		newTypeCheckerTest("value widening", "let m = { 1: 1, 2: 2.5 }; let x: float = get(m, 1); set(m, 3, 4);"),
+		newTypeCheckerTest("annotated value widening", "let m: map[string]float = { \"a\": 1 };"),
+		newTypeCheckerTest("has and delete", "let m = { 1: true }; if (has(m, 1)) { delete(m, 1); }"),
+		newTypeCheckerTest("list values", "let m: map[string][]int = {}; set(m, \"a\", []); push(get(m, \"a\"), 1);"),
+		newTypeCheckerTest("function parameter", "fn size(m: map[string]int): int { return len(m); } print(size({ \"a\": 1 }));"),
+		newTypeCheckerTestFail("empty literal without type", "let m = {};", "cannot infer key and value types of empty map literal"),
+		newTypeCheckerTestFail("float key literal", "let m = { 1.5: 1 };", "type float cannot be used as map key"),
+		newTypeCheckerTestFail("list key annotation", "let m: map[[]int]int;", "type []int cannot be used as map key"),
+		newTypeCheckerTestFail("mismatched keys", "let m = { 1: 1, \"a\": 2 };", "mismatched types of map keys: int and string"),
+		newTypeCheckerTestFail("mismatched values", "let m = { 1: 1, 2: true };", "mismatched types of map values: int and bool"),
+		newTypeCheckerTestFail("wrong key type", "let m = { 1: 2 }; get(m, \"a\");", "cannot use string as key of map[int]int"),
+		newTypeCheckerTestFail("wrong value type", "let m = { 1: 2 }; set(m, 1, \"x\");", "cannot use string as value of map[int]int"),
+		newTypeCheckerTestFail("literal value type", "let m: map[string]int = { \"a\": true };", "cannot use bool as value of map[string]int"),
+		newTypeCheckerTestFail("get on non-map", "get([1], 0);", "first argument of get must be a map, got [int; 1]"),
+		newTypeCheckerTestFail("wrong number of arguments", "let m = { 1: 2 }; set(m, 1);", "wrong number of arguments to set. expected 3 arguments, got 2 arguments"),
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			runTypeCheckerTest(t, tt)
+		})
+	}
+}
+
+func TestTypeChecker_Structs(t *testing.T) {
+	tests := []typeCheckerTestItem{
+		newTypeCheckerTest("literal and field access", "struct P { x: float, y: float } let p = P { y: 2, x: 1.5 }; let x: float = p.x + p.y;"),
+		newTypeCheckerTest("nested", "struct P { x: int } struct S { from: P, to: P } let s = S { from: P { x: 1 }, to: P { x: 2 } }; let x: int = s.to.x;"),
+		newTypeCheckerTest("declared after use", "fn origin(): P { return P { x: 0 }; } struct P { x: int }"),
+		newTypeCheckerTest("field assignment", "struct P { x: int, xs: [int; 2] } let p = P { x: 1, xs: [1, 2] }; p.x = 2; p.x *= 3; p.xs[0] = 5;"),
+		newTypeCheckerTest("element field assignment", "struct P { x: int } let ps = [P { x: 1 }]; ps[0].x += 1;"),
+		newTypeCheckerTest("recursive through list", "struct Node { value: int, children: []Node } let n: Node; push(n.children, Node { value: 1, children: [] });"),
+		newTypeCheckerTest("function parameter", "struct P { x: int } fn getX(p: P): int { return p.x; } print(getX(P { x: 1 }));"),
+		newTypeCheckerTestFail("unknown struct", "let p = Q { x: 1 };", "unknown struct type: Q"),
+		newTypeCheckerTestFail("unknown field in literal", "struct P { x: int } let p = P { x: 1, y: 2 };", "struct P has no field y"),
+		newTypeCheckerTestFail("missing field", "struct P { x: int, y: int } let p = P { x: 1 };", "missing field y in struct literal of P"),
+		newTypeCheckerTestFail("duplicate field in literal", "struct P { x: int } let p = P { x: 1, x: 2 };", "duplicate field x in struct literal"),
+		newTypeCheckerTestFail("field type mismatch", "struct P { x: int } let p = P { x: \"a\" };", "cannot use string as field x of type int"),
+		newTypeCheckerTestFail("unknown field access", "struct P { x: int } let p = P { x: 1 }; p.z;", "struct P has no field z"),
+		newTypeCheckerTestFail("field of non-struct", "let a = 1; a.x;", "cannot access field x of value of type int"),
+		newTypeCheckerTestFail("field assignment mismatch", "struct P { x: int } let p = P { x: 1 }; p.x = true;", "cannot assign bool to field x of type int"),
+		newTypeCheckerTestFail("directly recursive", "struct P { p: P }", "struct P cannot contain itself"),
+		newTypeCheckerTestFail("mutually recursive", "struct A { b: B } struct B { a: [A; 2] }", "struct A cannot contain itself"),
+		newTypeCheckerTestFail("duplicate field", "struct P { x: int, x: float }", "duplicate field x in struct P"),
+		newTypeCheckerTestFail("redeclared struct", "struct P { x: int } struct P { y: int }", "cannot redeclare type: P"),
+		newTypeCheckerTestFail("builtin type name", "struct int { x: int }", "cannot redeclare type: int"),
+		newTypeCheckerTestFail("nested declaration", "fn f() { struct P { x: int } }", "struct P must be declared at top level"),
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			runTypeCheckerTest(t, tt)
+		})
+	}
+}
+
+func TestTypeChecker_Enums(t *testing.T) {
+	tests := []typeCheckerTestItem{
+		newTypeCheckerTest("match expression", "enum S { Circle(float), Rect(float, float), Empty } let s = S::Rect(1, 2.5); let a: float = match (s) { Circle(r) => r * r, Rect(w, h) => w * h, Empty => 0 };"),
+		newTypeCheckerTest("wildcard arm", "enum S { A, B, C } let s = S::A; let x: int = match (s) { A => 1, _ => 2 };"),
+		newTypeCheckerTest("ignored binding", "enum S { P(int, int) } let s = S::P(1, 2); let x: int = match (s) { P(_, y) => y };"),
+		newTypeCheckerTest("match statement", "enum S { A, B(int) } let s = S::B(1); let x = 0; match (s) { B(v) => { x = v; } A => {} }"),
+		newTypeCheckerTest("returning arms", "enum S { A, B(int) } fn f(s: S): int { match (s) { A => { return 0; } B(v) => { return v; } } }"),
+		newTypeCheckerTest("recursive through list", "enum T { Leaf, Node([]T) } let t = T::Node([T::Leaf]);"),
+		newTypeCheckerTest("declared after use", "fn f(): S { return S::A; } enum S { A }"),
+		newTypeCheckerTestFail("unknown enum", "let s = S::A;", "unknown enum type: S"),
+		newTypeCheckerTestFail("unknown variant", "enum S { A } let s = S::B;", "enum S has no variant B"),
+		newTypeCheckerTestFail("wrong number of values", "enum S { A(int) } let s = S::A;", "wrong number of values for variant S::A. expected 1 values, got 0 values"),
+		newTypeCheckerTestFail("value type mismatch", "enum S { A(int) } let s = S::A(\"a\");", "invalid value at 0 idx to variant S::A. expected int, got string"),
+		newTypeCheckerTestFail("non-exhaustive", "enum S { A, B, C } let s = S::A; let x = match (s) { B => 1 };", "non-exhaustive match on S, missing variants: A, C"),
+		newTypeCheckerTestFail("duplicate arm", "enum S { A, B } let s = S::A; let x = match (s) { A => 1, A => 2, B => 3 };", "duplicate match arm for variant A"),
+		newTypeCheckerTestFail("arm after wildcard", "enum S { A, B } let s = S::A; let x = match (s) { _ => 1, B => 2 };", "unreachable match arm B, it comes after the wildcard arm"),
+		newTypeCheckerTestFail("wrong number of bindings", "enum S { A(int, int) } let s = S::A(1, 2); let x = match (s) { A(a) => a };", "wrong number of bindings for variant A. expected 2 bindings, got 1 bindings"),
+		newTypeCheckerTestFail("mismatched arms", "enum S { A, B } let s = S::A; let x = match (s) { A => 1, B => true };", "mismatched types of match arms: int and bool"),
+		newTypeCheckerTestFail("non-enum subject", "let x = match (1) { _ => 1 };", "cannot match on value of type int"),
+		newTypeCheckerTestFail("binding out of scope", "enum S { A(int) } let s = S::A(1); match (s) { A(v) => {} } print(v);", "unknown identifier: v"),
+		newTypeCheckerTestFail("directly recursive", "enum T { Leaf, Node(T) }", "enum T cannot contain itself"),
+		newTypeCheckerTestFail("recursive through struct", "enum T { Leaf, Node(N) } struct N { t: T }", "enum T cannot contain itself"),
+		newTypeCheckerTestFail("duplicate variant", "enum S { A, A(int) }", "duplicate variant A in enum S"),
+		newTypeCheckerTestFail("redeclared enum", "struct S { x: int } enum S { A }", "cannot redeclare type: S"),
+		newTypeCheckerTestFail("nested declaration", "fn f() { enum S { A } }", "enum S must be declared at top level"),
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			runTypeCheckerTest(t, tt)
+		})
+	}
+}
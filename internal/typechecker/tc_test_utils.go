@@ -0,0 +1,80 @@
+package typechecker
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/0xmukesh/coco/internal/lexer"
+	"github.com/0xmukesh/coco/internal/parser"
+)
+
+type typeCheckerTestItem struct {
+	name          string
+	input         string
+	shouldFail    bool
+	expectedError string
+}
+
+func newTypeCheckerTest(name, input string) typeCheckerTestItem {
+	return typeCheckerTestItem{
+		name:       name,
+		input:      input,
+		shouldFail: false,
+	}
+}
+
+func newTypeCheckerTestFail(name, input, expectedError string) typeCheckerTestItem {
+	return typeCheckerTestItem{
+		name:          name,
+		input:         input,
+		shouldFail:    true,
+		expectedError: expectedError,
+	}
+}
+
+func runTypeCheckerTest(t *testing.T, tt typeCheckerTestItem) {
+	t.Helper()
+
+	l := lexer.New(tt.input)
+	p := parser.New(l.Lex())
+	program := p.ParseProgram()
+
+	if p.HasErrors() {
+		for _, e := range p.Errors() {
+			t.Log(e)
+		}
+
+		t.Fatalf("failed to parse program without errors. got %d errors", len(p.Errors()))
+	}
+
+	tc := New()
+	tc.Transform(program)
+
+	if !tt.shouldFail {
+		if tc.HasErrors() {
+			for _, e := range tc.Errors() {
+				t.Log(e)
+			}
+
+			t.Fatalf("failed to type check program without errors. got %d errors", len(tc.Errors()))
+		}
+
+		return
+	}
+
+	if !tc.HasErrors() {
+		t.Fatalf("expected program to have typechecker errors")
+	}
+
+	for _, e := range tc.Errors() {
+		if strings.Contains(e.Error(), tt.expectedError) {
+			return
+		}
+	}
+
+	for _, e := range tc.Errors() {
+		t.Log(e)
+	}
+
+	t.Fatalf("expected typechecker error containing %q", tt.expectedError)
+}
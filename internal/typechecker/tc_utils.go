@@ -1,6 +1,9 @@
 package typechecker
 
 import (
+	"fmt"
+	"slices"
+
 	"github.com/0xmukesh/coco/internal/ast"
 	cotypes "github.com/0xmukesh/coco/internal/types"
 )
@@ -22,14 +25,562 @@ func (tc *TypeChecker) registerBuiltins() {
 		kind:    ast.BuiltinFuncExit,
 		checker: tc.checkExitBuiltin,
 	}
-	tc.builtins["int"] = &builtinsInfo{
-		name:    "int",
-		kind:    ast.BuiltinFuncInt,
-		checker: tc.checkIntBuiltin,
+	// conversions between numeric types and chars are named after the type they convert to
+	for _, name := range []string{"int", "float", "i8", "i16", "i32", "i64", "u8", "u16", "u32", "u64", "f32", "char"} {
+		tc.builtins[name] = &builtinsInfo{
+			name:    name,
+			kind:    ast.BuiltinFuncConvert,
+			checker: tc.checkConversionBuiltin,
+		}
+	}
+	tc.builtins["len"] = &builtinsInfo{
+		name:    "len",
+		kind:    ast.BuiltinFuncLen,
+		checker: tc.checkLenBuiltin,
+	}
+	tc.builtins["push"] = &builtinsInfo{
+		name:    "push",
+		kind:    ast.BuiltinFuncPush,
+		checker: tc.checkPushBuiltin,
+	}
+	tc.builtins["pop"] = &builtinsInfo{
+		name:    "pop",
+		kind:    ast.BuiltinFuncPop,
+		checker: tc.checkPopBuiltin,
+	}
+	tc.builtins["get"] = &builtinsInfo{
+		name:    "get",
+		kind:    ast.BuiltinFuncGet,
+		checker: tc.checkGetBuiltin,
+	}
+	tc.builtins["set"] = &builtinsInfo{
+		name:    "set",
+		kind:    ast.BuiltinFuncSet,
+		checker: tc.checkSetBuiltin,
+	}
+	tc.builtins["has"] = &builtinsInfo{
+		name:    "has",
+		kind:    ast.BuiltinFuncHas,
+		checker: tc.checkHasBuiltin,
+	}
+	tc.builtins["delete"] = &builtinsInfo{
+		name:    "delete",
+		kind:    ast.BuiltinFuncDelete,
+		checker: tc.checkDeleteBuiltin,
+	}
+	tc.builtins["ok"] = &builtinsInfo{
+		name:    "ok",
+		kind:    ast.BuiltinFuncOk,
+		checker: tc.checkResultBuiltin,
+	}
+	tc.builtins["err"] = &builtinsInfo{
+		name:    "err",
+		kind:    ast.BuiltinFuncErr,
+		checker: tc.checkResultBuiltin,
+	}
+}
+
+// reports whether executing the block is guaranteed to hit a return statement
+func alwaysReturns(block *ast.BlockStatement) bool {
+	if block == nil {
+		return false
+	}
+
+	for _, stmt := range block.Statements {
+		switch s := stmt.(type) {
+		case *ast.ReturnStatement:
+			return true
+		case *ast.BlockStatement:
+			if alwaysReturns(s) {
+				return true
+			}
+		case *ast.WhileStatement:
+			// an infinite loop never falls through to the statements after it, unless it is broken out of
+			if isTrueLiteral(s.Condition) && !breaksOutOf(s.Body, loopLabel(s.Label), false) {
+				return true
+			}
+		case *ast.ForStatement:
+			if (s.Condition == nil || isTrueLiteral(s.Condition)) && !breaksOutOf(s.Body, loopLabel(s.Label), false) {
+				return true
+			}
+		case *ast.ExpressionStatement:
+			if ifExpr, ok := s.Expr.(*ast.IfExpression); ok && ifExpr.Alternative != nil {
+				if alwaysReturns(ifExpr.Consequence) && alwaysReturns(ifExpr.Alternative) {
+					return true
+				}
+			}
+
+			// match expressions are exhaustive, so one of the arms is always taken
+			if matchExpr, ok := s.Expr.(*ast.MatchExpression); ok && len(matchExpr.Arms) > 0 {
+				if !slices.ContainsFunc(matchExpr.Arms, func(arm *ast.MatchArm) bool { return !alwaysReturns(arm.Body) }) {
+					return true
+				}
+			}
+		}
+	}
+
+	return false
+}
+
+// converts an int expression into a float expression. literals are converted in place,
+// everything else is wrapped in a cast expression
+func widenToFloat(expr ast.Expression) ast.Expression {
+	if intLit, ok := expr.(*ast.IntegerExpression); ok {
+		return &ast.FloatExpression{
+			Token: intLit.Token,
+			Value: float64(intLit.Value),
+			Type:  cotypes.FloatType{},
+		}
+	}
+
+	return &ast.CastExpression{
+		Expr: expr,
+		Type: cotypes.FloatType{},
+	}
+}
+
+// returns the value of an if expression branch or match arm, which is its last expression, so that it can be replaced
+func branchValue(block *ast.BlockStatement) *ast.Expression {
+	last := block.Statements[len(block.Statements)-1].(*ast.ExpressionStatement)
+	return &last.Expr
+}
+
+// returns the variable whose element is assigned to, nil if the target is not stored in a variable (eg. a[0] where a is a function call)
+func assignmentRoot(expr ast.Expression) *ast.IdentifierExpression {
+	switch e := expr.(type) {
+	case *ast.IdentifierExpression:
+		return e
+	case *ast.IndexExpression:
+		return assignmentRoot(e.Left)
+	case *ast.FieldExpression:
+		return assignmentRoot(e.Left)
+	case *ast.GroupedExpression:
+		return assignmentRoot(e.Expr)
+	default:
+		return nil
+	}
+}
+
+// reports whether values of the type hold a value of the named struct or enum inline, either directly or through
+// arrays, fields of other structs and payloads of other enums. visited guards against cycles between other types
+func embedsType(t cotypes.Type, name string, visited map[string]bool) bool {
+	switch t := t.(type) {
+	case cotypes.ArrayType:
+		return embedsType(t.Element, name, visited)
+	case cotypes.OptionalType:
+		return embedsType(t.Inner, name, visited)
+	case cotypes.ResultType:
+		return embedsType(t.Value, name, visited) || embedsType(t.Error, name, visited)
+	case cotypes.TupleType:
+		for _, element := range t.Elements {
+			if embedsType(element, name, visited) {
+				return true
+			}
+		}
+	case *cotypes.StructType:
+		if t.Name == name {
+			return true
+		}
+
+		if visited[t.Name] {
+			return false
+		}
+		visited[t.Name] = true
+
+		for _, field := range t.Fields {
+			if embedsType(field.Type, name, visited) {
+				return true
+			}
+		}
+	case *cotypes.EnumType:
+		if t.Name == name {
+			return true
+		}
+
+		if visited[t.Name] {
+			return false
+		}
+		visited[t.Name] = true
+
+		for _, variant := range t.Variants {
+			for _, payloadType := range variant.Payload {
+				if embedsType(payloadType, name, visited) {
+					return true
+				}
+			}
+		}
+	}
+
+	return false
+}
+
+// reports whether the type of the expression depends on the type it is used as, such expressions are
+// type checked against the expected type directly instead of being converted into it
+func isContextuallyTyped(expr ast.Expression) bool {
+	switch e := expr.(type) {
+	case *ast.ArrayExpression, *ast.MapExpression, *ast.TupleExpression:
+		return true
+	case *ast.CallExpression:
+		return isResultConstructor(e)
+	default:
+		return false
+	}
+}
+
+func isResultConstructor(expr *ast.CallExpression) bool {
+	return expr.Identifier != nil && (expr.Identifier.String() == "ok" || expr.Identifier.String() == "err")
+}
+
+// reports whether executing the block is guaranteed to leave it through a return, break or continue statement
+func alwaysExits(block *ast.BlockStatement) bool {
+	if alwaysReturns(block) {
+		return true
+	}
+
+	if block == nil || len(block.Statements) == 0 {
+		return false
+	}
+
+	switch block.Statements[len(block.Statements)-1].(type) {
+	case *ast.BreakStatement, *ast.ContinueStatement:
+		return true
+	default:
+		return false
+	}
+}
+
+// describes a type in errors about values which cannot be used as they are, pointing out
+// that optionals need to be checked for none before their value can be used
+func describeType(t cotypes.Type) string {
+	if _, ok := t.(cotypes.OptionalType); ok {
+		return fmt.Sprintf("%s (optional values must be checked for none before they are used)", t)
+	}
+
+	return t.String()
+}
+
+func isTrueLiteral(expr ast.Expression) bool {
+	switch e := expr.(type) {
+	case *ast.BooleanExpression:
+		return e.Value
+	case *ast.GroupedExpression:
+		return isTrueLiteral(e.Expr)
+	default:
+		return false
+	}
+}
+
+// reports whether the statement can be used as initialization or update statement of a for loop
+func isSimpleStatement(stmt ast.Statement) bool {
+	switch stmt.(type) {
+	case *ast.LetStatement, *ast.DestructuringStatement, *ast.AssignmentStatement, *ast.IndexAssignmentStatement, *ast.FieldAssignmentStatement, *ast.ExpressionStatement:
+		return true
+	default:
+		return false
+	}
+}
+
+// reports whether the statement declares variables
+func isDeclaration(stmt ast.Statement) bool {
+	switch stmt.(type) {
+	case *ast.LetStatement, *ast.DestructuringStatement:
+		return true
+	default:
+		return false
 	}
-	tc.builtins["float"] = &builtinsInfo{
-		name:    "float",
-		kind:    ast.BuiltinFuncFloat,
-		checker: tc.checkFloatBuiltin,
+}
+
+func loopLabel(label *ast.IdentifierExpression) string {
+	if label == nil {
+		return ""
+	}
+
+	return label.String()
+}
+
+// reports whether the statement contains a break statement which exits the loop with the given label.
+// nested is true when the statement is inside of another loop within that loop
+func breaksOutOf(stmt ast.Statement, label string, nested bool) bool {
+	switch s := stmt.(type) {
+	case *ast.BreakStatement:
+		if s.Label == nil {
+			return !nested
+		}
+
+		return label != "" && s.Label.String() == label
+	case *ast.BlockStatement:
+		if s == nil {
+			return false
+		}
+
+		for _, stmt := range s.Statements {
+			if breaksOutOf(stmt, label, nested) {
+				return true
+			}
+		}
+	case *ast.ExpressionStatement:
+		if ifExpr, ok := s.Expr.(*ast.IfExpression); ok {
+			return breaksOutOf(ifExpr.Consequence, label, nested) || breaksOutOf(ifExpr.Alternative, label, nested)
+		}
+
+		if matchExpr, ok := s.Expr.(*ast.MatchExpression); ok {
+			return slices.ContainsFunc(matchExpr.Arms, func(arm *ast.MatchArm) bool { return breaksOutOf(arm.Body, label, nested) })
+		}
+	case *ast.WhileStatement:
+		return breaksOutOf(s.Body, label, true)
+	case *ast.ForStatement:
+		return breaksOutOf(s.Body, label, true)
+	case *ast.ForInStatement:
+		return breaksOutOf(s.Body, label, true)
+	}
+
+	return false
+}
+
+// reports whether scope is the given environment or one of its parents
+func isScopeOrParent(scope, environment TypeEnvironment) bool {
+	for e := environment; e != nil; e = e.Parent() {
+		if e == scope {
+			return true
+		}
+	}
+
+	return false
+}
+
+// reports whether the type is numeric, including type parameters which are constrained to numeric types
+func isNumeric(t cotypes.Type) bool {
+	return cotypes.ConstraintNumeric.SatisfiedBy(t)
+}
+
+// reports whether the type is one of the fixed size numeric types other than int and float, eg. u8 or f32
+func isSizedNumeric(t cotypes.Type) bool {
+	switch t.(type) {
+	case cotypes.SizedIntType, cotypes.Float32Type:
+		return true
+	default:
+		return false
+	}
+}
+
+// reports whether every value of the numeric type from can be represented by the numeric type to
+func widensTo(from, to cotypes.Type) bool {
+	switch {
+	case cotypes.IsInteger(from) && cotypes.IsInteger(to):
+		fromBits, fromSigned := cotypes.IntegerLayout(from)
+		toBits, toSigned := cotypes.IntegerLayout(to)
+
+		// unsigned integers fit into signed integers which have more bits, but never the other way around
+		return fromBits < toBits && (fromSigned == toSigned || toSigned)
+	case cotypes.IsInteger(from) && cotypes.IsFloat(to):
+		// floats hold integers exactly as long as they fit into their mantissa, which has 24 bits for f32 and 53 for float
+		fromBits, _ := cotypes.IntegerLayout(from)
+		return fromBits <= cotypes.FloatBits(to)/2
+	case cotypes.IsFloat(from) && cotypes.IsFloat(to):
+		return cotypes.FloatBits(from) < cotypes.FloatBits(to)
+	default:
+		return false
+	}
+}
+
+// reports whether values of the numeric type from are converted to the numeric type to implicitly, which is
+// int to float on top of the conversions which do not lose information
+func widensImplicitly(from, to cotypes.Type) bool {
+	return widensTo(from, to) || from.Equals(cotypes.IntType{}) && to.Equals(cotypes.FloatType{})
+}
+
+// reports whether the integer type can hold the value
+func fitsInteger(value int64, t cotypes.Type) bool {
+	bits, signed := cotypes.IntegerLayout(t)
+	if bits == 64 {
+		return signed || value >= 0
+	}
+
+	if signed {
+		return value >= -(1<<(bits-1)) && value < 1<<(bits-1)
+	}
+
+	return value >= 0 && value < 1<<bits
+}
+
+func resolveConstraint(name string) (cotypes.Constraint, bool) {
+	switch name {
+	case "any":
+		return cotypes.ConstraintAny, true
+	case "comparable":
+		return cotypes.ConstraintComparable, true
+	case "ordered":
+		return cotypes.ConstraintOrdered, true
+	case "numeric":
+		return cotypes.ConstraintNumeric, true
+	default:
+		return cotypes.ConstraintAny, false
+	}
+}
+
+// reports whether the type refers to a type parameter, either directly or through its element, key, value or parameter types
+func containsTypeParam(t cotypes.Type) bool {
+	switch t := t.(type) {
+	case cotypes.TypeParamType:
+		return true
+	case cotypes.ArrayType:
+		return containsTypeParam(t.Element)
+	case cotypes.ListType:
+		return containsTypeParam(t.Element)
+	case cotypes.MapType:
+		return containsTypeParam(t.Key) || containsTypeParam(t.Value)
+	case cotypes.OptionalType:
+		return containsTypeParam(t.Inner)
+	case cotypes.ResultType:
+		return containsTypeParam(t.Value) || containsTypeParam(t.Error)
+	case cotypes.TupleType:
+		return slices.ContainsFunc(t.Elements, containsTypeParam)
+	case cotypes.FunctionType:
+		return containsTypeParam(t.Return) || slices.ContainsFunc(t.Params, containsTypeParam)
+	default:
+		return false
+	}
+}
+
+// counts the types the type is made of, including itself
+func typeSize(t cotypes.Type) int {
+	sum := func(types []cotypes.Type) int {
+		size := 0
+		for _, t := range types {
+			size += typeSize(t)
+		}
+
+		return size
+	}
+
+	switch t := t.(type) {
+	case cotypes.ArrayType:
+		return 1 + typeSize(t.Element)
+	case cotypes.ListType:
+		return 1 + typeSize(t.Element)
+	case cotypes.MapType:
+		return 1 + typeSize(t.Key) + typeSize(t.Value)
+	case cotypes.OptionalType:
+		return 1 + typeSize(t.Inner)
+	case cotypes.ResultType:
+		return 1 + typeSize(t.Value) + typeSize(t.Error)
+	case cotypes.TupleType:
+		return 1 + sum(t.Elements)
+	case cotypes.FunctionType:
+		return 1 + typeSize(t.Return) + sum(t.Params)
+	default:
+		return 1
+	}
+}
+
+// replaces the type parameters within the type by the types they are bound to
+func substituteTypeParams(t cotypes.Type, typeArgs map[string]cotypes.Type) cotypes.Type {
+	switch t := t.(type) {
+	case cotypes.TypeParamType:
+		if typeArg, ok := typeArgs[t.Name]; ok {
+			return typeArg
+		}
+
+		return t
+	case cotypes.ArrayType:
+		return cotypes.ArrayType{Element: substituteTypeParams(t.Element, typeArgs), Size: t.Size}
+	case cotypes.ListType:
+		return cotypes.ListType{Element: substituteTypeParams(t.Element, typeArgs)}
+	case cotypes.MapType:
+		return cotypes.MapType{Key: substituteTypeParams(t.Key, typeArgs), Value: substituteTypeParams(t.Value, typeArgs)}
+	case cotypes.OptionalType:
+		return cotypes.OptionalType{Inner: substituteTypeParams(t.Inner, typeArgs)}
+	case cotypes.ResultType:
+		return cotypes.ResultType{Value: substituteTypeParams(t.Value, typeArgs), Error: substituteTypeParams(t.Error, typeArgs)}
+	case cotypes.TupleType:
+		elements := []cotypes.Type{}
+		for _, element := range t.Elements {
+			elements = append(elements, substituteTypeParams(element, typeArgs))
+		}
+
+		return cotypes.TupleType{Elements: elements}
+	case cotypes.FunctionType:
+		params := []cotypes.Type{}
+		for _, param := range t.Params {
+			params = append(params, substituteTypeParams(param, typeArgs))
+		}
+
+		return cotypes.FunctionType{Params: params, Return: substituteTypeParams(t.Return, typeArgs)}
+	default:
+		return t
+	}
+}
+
+// matches the type of a parameter against the type of the argument passed for it, binding the type parameters
+// within the parameter type. fails if the types differ in shape or a type parameter is bound to two different
+// types, except for int and float which are unified to float. array arguments match list parameters, since
+// array literals are converted to lists
+func inferTypeArguments(param, arg cotypes.Type, typeArgs map[string]cotypes.Type) bool {
+	switch p := param.(type) {
+	case cotypes.TypeParamType:
+		bound, ok := typeArgs[p.Name]
+		if !ok || (bound.Equals(cotypes.IntType{}) && arg.Equals(cotypes.FloatType{})) {
+			typeArgs[p.Name] = arg
+			return true
+		}
+
+		return bound.Equals(arg) || (bound.Equals(cotypes.FloatType{}) && arg.Equals(cotypes.IntType{}))
+	case cotypes.ArrayType:
+		a, ok := arg.(cotypes.ArrayType)
+		return ok && a.Size == p.Size && inferTypeArguments(p.Element, a.Element, typeArgs)
+	case cotypes.ListType:
+		switch a := arg.(type) {
+		case cotypes.ListType:
+			return inferTypeArguments(p.Element, a.Element, typeArgs)
+		case cotypes.ArrayType:
+			return inferTypeArguments(p.Element, a.Element, typeArgs)
+		default:
+			return false
+		}
+	case cotypes.MapType:
+		a, ok := arg.(cotypes.MapType)
+		return ok && inferTypeArguments(p.Key, a.Key, typeArgs) && inferTypeArguments(p.Value, a.Value, typeArgs)
+	case cotypes.OptionalType:
+		// values are wrapped into optionals implicitly, none does not tell anything about the inner type
+		if a, ok := arg.(cotypes.OptionalType); ok {
+			return inferTypeArguments(p.Inner, a.Inner, typeArgs)
+		}
+
+		if arg.Equals(cotypes.NoneType{}) {
+			return true
+		}
+
+		return inferTypeArguments(p.Inner, arg, typeArgs)
+	case cotypes.ResultType:
+		a, ok := arg.(cotypes.ResultType)
+		return ok && inferTypeArguments(p.Value, a.Value, typeArgs) && inferTypeArguments(p.Error, a.Error, typeArgs)
+	case cotypes.TupleType:
+		a, ok := arg.(cotypes.TupleType)
+		if !ok || len(a.Elements) != len(p.Elements) {
+			return false
+		}
+
+		for i := range p.Elements {
+			if !inferTypeArguments(p.Elements[i], a.Elements[i], typeArgs) {
+				return false
+			}
+		}
+
+		return true
+	case cotypes.FunctionType:
+		a, ok := arg.(cotypes.FunctionType)
+		if !ok || len(a.Params) != len(p.Params) {
+			return false
+		}
+
+		for i := range p.Params {
+			if !inferTypeArguments(p.Params[i], a.Params[i], typeArgs) {
+				return false
+			}
+		}
+
+		return inferTypeArguments(p.Return, a.Return, typeArgs)
+	default:
+		return param.Equals(arg)
 	}
 }
@@ -2,6 +2,9 @@ package typechecker
 
 import (
 	"fmt"
+	"maps"
+	"slices"
+	"strings"
 
 	"github.com/0xmukesh/coco/internal/ast"
 	"github.com/0xmukesh/coco/internal/env"
@@ -9,20 +12,113 @@ import (
 	cotypes "github.com/0xmukesh/coco/internal/types"
 )
 
-type TypeEnvironment = *env.Environent[cotypes.Type]
+type TypeEnvironment = *env.Environent[symbol]
+
+type symbol struct {
+	typ     cotypes.Type
+	isConst bool
+	// folded literal value, only set for constants
+	value ast.Expression
+	// identifier which declares the variable, marked once the variable is captured by a closure. nil for constants
+	decl *ast.IdentifierExpression
+}
+
+// function literal whose body is currently being type checked
+type closureInfo struct {
+	expr *ast.FunctionExpression
+	// scope in which the function literal appears, variables declared in it or in its parents are captured
+	outer TypeEnvironment
+	// optional variables narrowed where the function literal appears
+	narrowed map[*ast.IdentifierExpression]bool
+}
+
+// maximum number of instances of a single generic function, which stops generic functions calling
+// themselves with ever growing type arguments from being instantiated endlessly
+const maxInstances = 64
+
+// maximum size of a type argument of a generic function instance. type arguments which grow with every
+// recursive call, eg. (T, T), would otherwise double in size long before the instance limit is reached
+const maxTypeArgumentSize = 64
+
+type functionInfo struct {
+	name       string
+	params     []cotypes.Type
+	returnType cotypes.Type
+	// type parameters of generic functions
+	typeParams []cotypes.TypeParamType
+	// declaration of a generic function as it was before being type checked, which every instance is copied from
+	generic *ast.FunctionStatement
+	// declaration of a generic function in the program, which the instances are attached to
+	decl *ast.FunctionStatement
+	// types which the type parameters are bound to, only set for instances of generic functions
+	typeArgs map[string]cotypes.Type
+	// top level variables used in the body, including within the function literals in it
+	globalsUsed []*ast.IdentifierExpression
+	// functions called or used as values in the body
+	functionsUsed []*functionInfo
+}
+
+// use of a function in top level code, which requires the top level variables used by the function to be initialized
+type topLevelFunctionUse struct {
+	node     ast.Node
+	function *functionInfo
+	// number of top level variables initialized at the point of use
+	initialized int
+}
 
 type TypeChecker struct {
-	env      TypeEnvironment
-	builtins map[string]*builtinsInfo
+	env TypeEnvironment
+	// top level constants and variables, which are visible inside of function bodies as well
+	globals   TypeEnvironment
+	builtins  map[string]*builtinsInfo
+	functions map[string]*functionInfo
+	structs   map[string]*cotypes.StructType
+	enums     map[string]*cotypes.EnumType
+
+	// function whose body is currently being type checked, nil at top level
+	currentFunction *functionInfo
+	// function statement whose body is currently being type checked, which stays set within the function literals
+	// in it. nil at top level
+	enclosingFunction *functionInfo
+	// enclosing function literals, innermost last
+	closures []*closureInfo
+	// labels of the enclosing loops, innermost last. unlabeled loops have an empty label
+	loopLabels []string
+	// types which the type parameters in scope resolve to. they are opaque while a generic function is
+	// checked, and bound to the type arguments while one of its instances is checked
+	typeParams map[string]cotypes.Type
+	// instances of generic functions which are yet to be type checked
+	pendingInstances []*ast.FunctionStatement
+	// optional variables which are known to hold a value at the current point, keyed by the identifier which
+	// declares them. conditions which compare them against none narrow them in the code they guard
+	narrowed map[*ast.IdentifierExpression]bool
+	// optional variables which are assigned within function literals, they are never narrowed since the
+	// function literal can be called at any point. same goes for top level variables assigned within functions
+	closureAssigned map[*ast.IdentifierExpression]bool
+	// names of the variables which are assigned within the bodies of functions, see Transform
+	functionAssigned map[string]bool
+	// top level variables in the order in which they are initialized
+	globalsOrder map[*ast.IdentifierExpression]int
+	// uses of functions in top level code, which are checked once all function bodies are, see checkFunctionUses
+	topLevelUses []topLevelFunctionUse
 
 	errors []error
 }
 
 func New() *TypeChecker {
 	tc := &TypeChecker{
-		env:      env.NewEnvironment[cotypes.Type](),
-		builtins: make(map[string]*builtinsInfo),
-		errors:   []error{},
+		env:       env.NewEnvironment[symbol](),
+		globals:   env.NewEnvironment[symbol](),
+		builtins:  make(map[string]*builtinsInfo),
+		functions: make(map[string]*functionInfo),
+		structs:   make(map[string]*cotypes.StructType),
+		enums:     make(map[string]*cotypes.EnumType),
+		errors:    []error{},
+
+		narrowed:         make(map[*ast.IdentifierExpression]bool),
+		closureAssigned:  make(map[*ast.IdentifierExpression]bool),
+		functionAssigned: make(map[string]bool),
+		globalsOrder:     make(map[*ast.IdentifierExpression]int),
 	}
 
 	tc.registerBuiltins()
@@ -38,23 +134,50 @@ func (tc *TypeChecker) checkExpression(expr ast.Expression) (t cotypes.Type, err
 		t = cotypes.FloatType{}
 	case *ast.StringExpression:
 		t = cotypes.StringType{}
+	case *ast.InterpolationExpression:
+		t, err = tc.checkInterpolationExpression(e)
+	case *ast.CharExpression:
+		t = cotypes.CharType{}
 	case *ast.BooleanExpression:
 		t = cotypes.BoolType{}
+	case *ast.NoneExpression:
+		t = cotypes.NoneType{}
 	case *ast.IdentifierExpression:
-		identType, found := tc.env.Get(e.String())
-		if !found {
-			err = fmt.Errorf("unknown identifier: %s", e.String())
-		} else {
-			t = identType
-		}
+		t, err = tc.checkIdentifierExpression(e)
+	case *ast.UnaryExpression:
+		t, err = tc.checkUnaryExpression(e)
+	case *ast.TryExpression:
+		t, err = tc.checkTryExpression(e)
 	case *ast.BinaryExpression:
 		t, err = tc.checkBinaryExpression(e)
 	case *ast.CallExpression:
 		t, err = tc.checkCallExpression(e)
 	case *ast.GroupedExpression:
 		t, err = tc.checkExpression(e.Expr)
+	case *ast.CastExpression:
+		if _, err = tc.checkExpression(e.Expr); err == nil {
+			t = e.Type
+		}
 	case *ast.IfExpression:
-		t, err = tc.checkIfExpression(e)
+		t, err = tc.checkIfExpression(e, nil)
+	case *ast.ArrayExpression:
+		t, err = tc.checkArrayExpression(e)
+	case *ast.TupleExpression:
+		t, err = tc.checkTupleExpression(e)
+	case *ast.IndexExpression:
+		t, err = tc.checkIndexExpression(e)
+	case *ast.MapExpression:
+		t, err = tc.checkMapExpression(e)
+	case *ast.StructExpression:
+		t, err = tc.checkStructExpression(e)
+	case *ast.FieldExpression:
+		t, err = tc.checkFieldExpression(e)
+	case *ast.VariantExpression:
+		t, err = tc.checkVariantExpression(e)
+	case *ast.MatchExpression:
+		t, err = tc.checkMatchExpression(e, nil)
+	case *ast.FunctionExpression:
+		t, err = tc.checkFunctionExpression(e)
 	default:
 		err = fmt.Errorf("unknown expression of type %T", expr)
 	}
@@ -71,223 +194,2871 @@ func (tc *TypeChecker) checkExpression(expr ast.Expression) (t cotypes.Type, err
 func (tc *TypeChecker) checkStatement(stmt ast.Statement) (err error) {
 	switch s := stmt.(type) {
 	case *ast.ExpressionStatement:
-		tc.checkExpression(s.Expr)
-	case *ast.LetStatement:
-		varName := s.Identifier.String()
-		if tc.env.Has(varName) {
-			tc.addError("cannot redeclare variable: %s", varName)
-			return
+		// an if expression in statement position does not produce a value
+		if ifExpr, ok := s.Expr.(*ast.IfExpression); ok {
+			return tc.checkIfStatement(ifExpr)
 		}
 
-		varType, err := tc.checkExpression(s.Value)
-		if err != nil {
-			return err
+		// same goes for match expressions, whose arms then do not need to produce a value
+		if matchExpr, ok := s.Expr.(*ast.MatchExpression); ok {
+			return tc.checkMatchStatement(matchExpr)
 		}
 
-		tc.env.Set(s.Identifier.String(), varType)
+		tc.checkExpression(s.Expr)
+	case *ast.LetStatement:
+		return tc.checkLetStatement(s)
+	case *ast.DestructuringStatement:
+		return tc.checkDestructuringStatement(s)
+	case *ast.ConstStatement:
+		return tc.checkConstStatement(s)
 	case *ast.AssignmentStatement:
-		_, exists := tc.env.Get(s.Identifier.String())
-		if !exists {
-			err = fmt.Errorf("unknown identifier: %s", s.Identifier.String())
-		}
-
-		tc.checkExpression(s.Value)
+		return tc.checkAssignmentStatement(s)
+	case *ast.IndexAssignmentStatement:
+		return tc.checkIndexAssignmentStatement(s)
+	case *ast.FieldAssignmentStatement:
+		return tc.checkFieldAssignmentStatement(s)
+	case *ast.StructStatement:
+		return tc.checkStructStatement(s)
+	case *ast.EnumStatement:
+		return tc.checkEnumStatement(s)
 	case *ast.BlockStatement:
 		tc.env = env.NewEnvironmentWithParent(tc.env)
+		leave := tc.narrow(nil)
 		for _, s := range s.Statements {
 			tc.checkStatement(s)
 		}
 
+		leave()
 		tc.env = tc.env.Parent()
+	case *ast.FunctionStatement:
+		return tc.checkFunctionStatement(s)
+	case *ast.ReturnStatement:
+		return tc.checkReturnStatement(s)
+	case *ast.WhileStatement:
+		return tc.checkWhileStatement(s)
+	case *ast.ForStatement:
+		return tc.checkForStatement(s)
+	case *ast.ForInStatement:
+		return tc.checkForInStatement(s)
+	case *ast.BreakStatement:
+		return tc.checkLoopControl(s, s.Label)
+	case *ast.ContinueStatement:
+		return tc.checkLoopControl(s, s.Label)
 	}
 
 	return
 }
 
-func (tc *TypeChecker) checkBinaryExpression(expr *ast.BinaryExpression) (t cotypes.Type, err error) {
-	leftType, err := tc.checkExpression(expr.Left)
-	if err != nil {
-		return t, tc.propagateOrWrapError(err, expr, "failed to type check left operand: %s", err.Error())
+func (tc *TypeChecker) enterLoop(stmt ast.Statement, label *ast.IdentifierExpression) error {
+	labelName := ""
+	if label != nil {
+		labelName = label.String()
+		if slices.Contains(tc.loopLabels, labelName) {
+			return tc.addErrorAtNode(stmt, "duplicate loop label: %s", labelName)
+		}
 	}
 
-	rightType, err := tc.checkExpression(expr.Right)
-	if err != nil {
-		return t, tc.propagateOrWrapError(err, expr, "failed to type check right operand: %s", err.Error())
+	tc.loopLabels = append(tc.loopLabels, labelName)
+	return nil
+}
+
+func (tc *TypeChecker) exitLoop() {
+	tc.loopLabels = tc.loopLabels[:len(tc.loopLabels)-1]
+}
+
+func (tc *TypeChecker) checkLoopControl(stmt ast.Statement, label *ast.IdentifierExpression) error {
+	if len(tc.loopLabels) == 0 {
+		return tc.addErrorAtNode(stmt, "%s statement outside of loop", stmt.TokenLiteral())
 	}
 
-	leftTypeCategory := cotypes.GetTypeCategory(leftType)
-	rightTypeCategory := cotypes.GetTypeCategory(rightType)
+	if label != nil && !slices.Contains(tc.loopLabels, label.String()) {
+		return tc.addErrorAtNode(stmt, "unknown loop label: %s", label.String())
+	}
 
-	if leftType == nil || rightType == nil {
-		return t, err
+	return nil
+}
+
+func (tc *TypeChecker) checkWhileStatement(stmt *ast.WhileStatement) error {
+	if err := tc.enterLoop(stmt, stmt.Label); err != nil {
+		return err
 	}
+	defer tc.exitLoop()
 
-	op := expr.Operator.Type
-	isComparisonOperator := op == tokens.LESS_THAN || op == tokens.GREATER_THAN || op == tokens.LESS_THAN_EQUALS || op == tokens.GREATER_THAN_EQUALS || op == tokens.EQUALS || op == tokens.NOT_EQUALS
-
-	// numeric types (int, float)
-	if leftTypeCategory == cotypes.CategoryNumeric && rightTypeCategory == cotypes.CategoryNumeric {
-		// arithmetic operators
-		if op == tokens.PLUS || op == tokens.MINUS || op == tokens.STAR || op == tokens.SLASH || op == tokens.DOUBLE_STAR {
-			// if it is numeric arithmetic and either one of them is float, then result type is float
-			// and the one which is integer is converted to float expression
-			if leftType.Equals(cotypes.FloatType{}) || rightType.Equals(cotypes.FloatType{}) {
-				if leftIntLit, ok := expr.Left.(*ast.IntegerExpression); ok {
-					expr.Left = &ast.FloatExpression{
-						Token: leftIntLit.Token,
-						Value: float64(leftIntLit.Value),
-						Type:  cotypes.FloatType{},
-					}
-				}
+	tc.forgetAssignedIn(stmt.Body)
 
-				if rightIntLit, ok := expr.Right.(*ast.IntegerExpression); ok {
-					expr.Right = &ast.FloatExpression{
-						Token: rightIntLit.Token,
-						Value: float64(rightIntLit.Value),
-						Type:  cotypes.FloatType{},
-					}
-				}
+	conditionType, err := tc.checkExpression(stmt.Condition)
+	if err != nil {
+		return tc.propagateOrWrapError(err, stmt, "failed to type check while loop condition expression: %s", err.Error())
+	}
 
-				return expr.SetType(cotypes.FloatType{}), err
-			} else {
-				return expr.SetType(cotypes.IntType{}), err
-			}
+	if !conditionType.Equals(cotypes.BoolType{}) {
+		return tc.addErrorAtNode(stmt, "non-boolean condition in while loop, got %s", describeType(conditionType))
+	}
+
+	whenTrue, _ := tc.narrowingsOf(stmt.Condition)
+	defer tc.narrow(whenTrue)()
+
+	return tc.checkStatement(stmt.Body)
+}
+
+func (tc *TypeChecker) checkForStatement(stmt *ast.ForStatement) error {
+	if err := tc.enterLoop(stmt, stmt.Label); err != nil {
+		return err
+	}
+	defer tc.exitLoop()
+
+	// variables declared in the initialization statement are scoped to the loop
+	tc.env = env.NewEnvironmentWithParent(tc.env)
+	defer func() {
+		tc.env = tc.env.Parent()
+	}()
+
+	if stmt.Initialization != nil {
+		if !isSimpleStatement(stmt.Initialization) {
+			return tc.addErrorAtNode(stmt, "invalid initialization statement in for loop: %s", stmt.Initialization)
 		}
 
-		// comparison operators
-		if isComparisonOperator {
-			return expr.SetType(cotypes.BoolType{}), err
+		if err := tc.checkStatement(stmt.Initialization); err != nil {
+			return err
 		}
 	}
 
-	// strings
-	if leftType.Equals(cotypes.StringType{}) && rightType.Equals(cotypes.StringType{}) {
-		// string concatenation
-		if op == tokens.PLUS {
-			return expr.SetType(cotypes.StringType{}), err
+	tc.forgetAssignedIn(stmt.Body, stmt.Update)
+
+	if stmt.Condition != nil {
+		conditionType, err := tc.checkExpression(stmt.Condition)
+		if err != nil {
+			return tc.propagateOrWrapError(err, stmt, "failed to type check for loop condition expression: %s", err.Error())
 		}
 
-		// lexicographical comparison
-		if isComparisonOperator {
-			return expr.SetType(cotypes.BoolType{}), err
+		if !conditionType.Equals(cotypes.BoolType{}) {
+			return tc.addErrorAtNode(stmt, "non-boolean condition in for loop, got %s", describeType(conditionType))
 		}
+
+		whenTrue, _ := tc.narrowingsOf(stmt.Condition)
+		defer tc.narrow(whenTrue)()
 	}
 
-	// bools
-	if leftType.Equals(cotypes.BoolType{}) && rightType.Equals(cotypes.BoolType{}) {
-		if isComparisonOperator {
-			return expr.SetType(cotypes.BoolType{}), err
+	if stmt.Update != nil {
+		if isDeclaration(stmt.Update) || !isSimpleStatement(stmt.Update) {
+			return tc.addErrorAtNode(stmt, "invalid update statement in for loop: %s", stmt.Update)
+		}
+
+		if err := tc.checkStatement(stmt.Update); err != nil {
+			return err
 		}
 	}
 
-	err = fmt.Errorf("cannot perform %s operation on %s and %s", op, leftType, rightType)
-	return
+	return tc.checkStatement(stmt.Body)
 }
 
-func (tc *TypeChecker) checkCallExpression(expr *ast.CallExpression) (t cotypes.Type, err error) {
-	if builtin, isBuiltin := tc.builtins[expr.Identifier.String()]; isBuiltin {
-		expr.IsBuiltin = true
-		expr.BuiltinKind = &builtin.kind
-		return builtin.checker(expr)
+func (tc *TypeChecker) checkForInStatement(stmt *ast.ForInStatement) error {
+	iterableType, err := tc.checkExpression(stmt.Iterable)
+	if err != nil {
+		return tc.propagateOrWrapError(err, stmt, "failed to type check iterable of for loop: %s", err.Error())
 	}
 
-	err = fmt.Errorf("cannot call %s identifier", expr.Identifier.String())
-	return
+	var elementType cotypes.Type
+	switch it := iterableType.(type) {
+	case cotypes.ArrayType:
+		elementType = it.Element
+	case cotypes.ListType:
+		elementType = it.Element
+	case cotypes.StringType:
+		elementType = cotypes.CharType{}
+	default:
+		return tc.addErrorAtNode(stmt, "cannot iterate over value of type %s", describeType(iterableType))
+	}
+
+	if err := tc.enterLoop(stmt, stmt.Label); err != nil {
+		return err
+	}
+	defer tc.exitLoop()
+
+	// loop variable holds a copy of the current element and is scoped to the loop
+	tc.env = env.NewEnvironmentWithParent(tc.env)
+	defer func() {
+		tc.env = tc.env.Parent()
+	}()
+
+	stmt.Identifier.SetType(elementType)
+	tc.env.Set(stmt.Identifier.String(), symbol{
+		typ:  elementType,
+		decl: stmt.Identifier,
+	})
+
+	tc.forgetAssignedIn(stmt.Body)
+	return tc.checkStatement(stmt.Body)
 }
 
-func (tc *TypeChecker) checkIfExpression(expr *ast.IfExpression) (t cotypes.Type, err error) {
-	conditionType, err := tc.checkExpression(expr.Condition)
-	if err != nil {
-		return t, tc.propagateOrWrapError(err, expr, "failed to type check if branch condition expression: %s", err.Error())
+func (tc *TypeChecker) checkLetStatement(stmt *ast.LetStatement) error {
+	varName := stmt.Identifier.String()
+	if tc.env.Has(varName) {
+		return tc.addError("cannot redeclare variable: %s", varName)
 	}
 
-	if !conditionType.Equals(cotypes.BoolType{}) {
-		return t, fmt.Errorf("non-boolean condition if if expression")
+	var varType cotypes.Type
+
+	if stmt.Annotation != nil {
+		annotatedType, err := tc.resolveTypeAnnotation(stmt.Annotation)
+		if err != nil {
+			return tc.propagateOrWrapError(err, stmt, "failed to resolve type of variable %s: %s", varName, err.Error())
+		}
+
+		varType = annotatedType
 	}
 
-	tc.checkStatement(expr.Consequence)
+	switch {
+	case stmt.Value == nil && varType == nil:
+		return tc.addErrorAtNode(stmt, "cannot infer type of variable %s without an initializer", varName)
+	case stmt.Value == nil:
+		// zero initialized
+	case varType == nil:
+		valueType, err := tc.checkExpression(stmt.Value)
+		if err != nil {
+			return err
+		}
 
-	if expr.Alternative != nil {
-		tc.checkStatement(expr.Alternative)
+		if valueType.Equals(cotypes.NoneType{}) {
+			return tc.addErrorAtNode(stmt, "cannot infer type of variable %s from none, it needs a type annotation", varName)
+		}
+
+		varType = valueType
+	default:
+		value, err := tc.coerceExpression(stmt.Value, varType)
+		if err != nil {
+			if stmt.Value.GetType() == nil {
+				return err
+			}
+
+			return tc.addErrorAtNode(stmt, "cannot assign %s to variable %s of type %s", stmt.Value.GetType(), varName, varType)
+		}
+
+		stmt.Value = value
 	}
 
-	// TODO: need to proper handle the return type for if-expression after adding support for return statements
-	return cotypes.VoidType{}, nil
+	if varType.Equals(cotypes.VoidType{}) {
+		return tc.addErrorAtNode(stmt, "cannot assign void value to variable: %s", varName)
+	}
+
+	stmt.Identifier.SetType(varType)
+	tc.declareVariable(varName, symbol{
+		typ:  varType,
+		decl: stmt.Identifier,
+	})
+	return nil
 }
 
-func (tc *TypeChecker) checkPrintBuiltin(expr *ast.CallExpression) (t cotypes.Type, err error) {
-	for i, arg := range expr.Arguments {
-		argType, err := tc.checkExpression(arg)
+// declares the variable in the current scope. variables declared at top level are globals, which are visible
+// inside of function bodies as well
+func (tc *TypeChecker) declareVariable(name string, sym symbol) {
+	tc.env.Set(name, sym)
+
+	if tc.env.Parent() == nil && tc.currentFunction == nil {
+		tc.globals.Set(name, sym)
+		tc.globalsOrder[sym.decl] = len(tc.globalsOrder)
+
+		// any call can assign the variable if a function does, so it is never narrowed
+		if tc.functionAssigned[name] {
+			tc.closureAssigned[sym.decl] = true
+		}
+	}
+}
+
+func (tc *TypeChecker) checkDestructuringStatement(stmt *ast.DestructuringStatement) error {
+	seen := map[string]bool{}
+	for _, identifier := range stmt.Identifiers {
+		varName := identifier.String()
+		if varName == "_" {
+			continue
+		}
+
+		if tc.env.Has(varName) || seen[varName] {
+			return tc.addErrorAtNode(stmt, "cannot redeclare variable: %s", varName)
+		}
+		seen[varName] = true
+	}
+
+	var valueType cotypes.Type
+
+	if stmt.Annotation != nil {
+		annotatedType, err := tc.resolveTypeAnnotation(stmt.Annotation)
 		if err != nil {
-			return t, tc.propagateOrWrapError(err, expr, "failed to type check print func arg at %d idx: %s", i, err.Error())
+			return tc.propagateOrWrapError(err, stmt, "failed to resolve type of destructured value: %s", err.Error())
 		}
 
-		arg.SetType(argType)
+		value, err := tc.coerceExpression(stmt.Value, annotatedType)
+		if err != nil {
+			if stmt.Value.GetType() == nil {
+				return err
+			}
 
-		if !argType.Equals(cotypes.IntType{}) && !argType.Equals(cotypes.FloatType{}) && !argType.Equals(cotypes.BoolType{}) {
-			return t, fmt.Errorf("invalid argument at %d idx to print", i)
+			return tc.addErrorAtNode(stmt, "cannot assign %s to variables of type %s", stmt.Value.GetType(), annotatedType)
+		}
+
+		stmt.Value = value
+		valueType = annotatedType
+	} else {
+		typ, err := tc.checkExpression(stmt.Value)
+		if err != nil {
+			return err
 		}
+
+		valueType = typ
 	}
 
-	return cotypes.VoidType{}, nil
+	tupleType, ok := valueType.(cotypes.TupleType)
+	if !ok {
+		return tc.addErrorAtNode(stmt, "cannot destructure value of type %s, only tuples can be destructured", describeType(valueType))
+	}
+
+	if len(tupleType.Elements) != len(stmt.Identifiers) {
+		return tc.addErrorAtNode(stmt, "cannot destructure %s into %d variables", tupleType, len(stmt.Identifiers))
+	}
+
+	for i, identifier := range stmt.Identifiers {
+		if identifier.String() == "_" {
+			continue
+		}
+
+		identifier.SetType(tupleType.Elements[i])
+		tc.declareVariable(identifier.String(), symbol{
+			typ:  tupleType.Elements[i],
+			decl: identifier,
+		})
+	}
+
+	return nil
 }
 
-func (tc *TypeChecker) checkExitBuiltin(expr *ast.CallExpression) (t cotypes.Type, err error) {
-	if len(expr.Arguments) != 1 {
-		return t, fmt.Errorf("too many arguments. expected one argument, got %d arguments", len(expr.Arguments))
+func (tc *TypeChecker) checkConstStatement(stmt *ast.ConstStatement) error {
+	constName := stmt.Identifier.String()
+	if tc.env.Has(constName) {
+		return tc.addError("cannot redeclare variable: %s", constName)
 	}
 
-	exitCode, err := tc.checkExpression(expr.Arguments[0])
+	var constType cotypes.Type
+
+	if stmt.Annotation != nil {
+		annotatedType, err := tc.resolveTypeAnnotation(stmt.Annotation)
+		if err != nil {
+			return tc.propagateOrWrapError(err, stmt, "failed to resolve type of constant %s: %s", constName, err.Error())
+		}
+
+		value, err := tc.coerceExpression(stmt.Value, annotatedType)
+		if err != nil {
+			if stmt.Value.GetType() == nil {
+				return err
+			}
+
+			return tc.addErrorAtNode(stmt, "cannot assign %s to constant %s of type %s", stmt.Value.GetType(), constName, annotatedType)
+		}
+
+		stmt.Value = value
+		constType = annotatedType
+	} else {
+		valueType, err := tc.checkExpression(stmt.Value)
+		if err != nil {
+			return err
+		}
+
+		constType = valueType
+	}
+
+	folded, err := tc.foldConstant(stmt.Value)
 	if err != nil {
-		return t, tc.propagateOrWrapError(err, expr, "failed to type check exit func arg: %s", err.Error())
+		return tc.addErrorAtNode(stmt, "initializer of constant %s is not a compile-time constant: %s", constName, err.Error())
 	}
 
-	if !exitCode.Equals(cotypes.IntType{}) {
-		return t, fmt.Errorf("expected exit code to be of type int, got %s", exitCode.String())
+	stmt.Value = folded
+	stmt.Identifier.SetType(constType)
+
+	sym := symbol{
+		typ:     constType,
+		isConst: true,
+		value:   folded,
 	}
+	tc.env.Set(constName, sym)
 
-	return cotypes.VoidType{}, nil
+	if tc.env.Parent() == nil && tc.currentFunction == nil {
+		tc.globals.Set(constName, sym)
+	}
+
+	return nil
 }
 
-func (tc *TypeChecker) checkIntBuiltin(expr *ast.CallExpression) (t cotypes.Type, err error) {
-	if len(expr.Arguments) != 1 {
-		return t, fmt.Errorf("too many arguments. expected one argument, got %d arguments", len(expr.Arguments))
+func (tc *TypeChecker) checkAssignmentStatement(stmt *ast.AssignmentStatement) error {
+	varName := stmt.Identifier.String()
+	sym, exists := tc.lookupVariable(varName)
+	if !exists {
+		return tc.addError("unknown identifier: %s", varName)
 	}
 
-	valType, err := tc.checkExpression(expr.Arguments[0])
-	if err != nil {
-		return t, tc.propagateOrWrapError(err, expr, "failed to type check int func arg: %s", err.Error())
+	if sym.isConst {
+		return tc.addErrorAtNode(stmt, "cannot assign to constant: %s", varName)
+	}
+
+	// function literals can be called while the variable is narrowed anywhere else
+	if len(tc.closures) > 0 && sym.decl != nil {
+		for _, closure := range tc.closures {
+			if slices.Contains(closure.expr.Captures, varName) {
+				tc.closureAssigned[sym.decl] = true
+				delete(closure.narrowed, sym.decl)
+			}
+		}
+	}
+
+	// a narrowed variable keeps holding a value if it is assigned one, anything else makes it optional again
+	varType, narrowed := sym.typ, tc.narrowed[sym.decl]
+	if narrowed {
+		varType = sym.typ.(cotypes.OptionalType).Inner
 	}
 
-	if cotypes.GetTypeCategory(valType) != cotypes.CategoryNumeric {
-		return t, fmt.Errorf("cannot convert %s to int", valType)
+	// the result of a compound assignment must be of the variable's type, so the value is coerced
+	// to it just like for plain assignments and the variable itself is never widened
+	op, isCompound := stmt.CompoundOperator()
+	isConcat := op == tokens.PLUS && varType.Equals(cotypes.StringType{})
+	if isCompound && !isNumeric(varType) && !isConcat {
+		return tc.addErrorAtNode(stmt, "cannot perform %s operation on %s", stmt.Operator.Literal, describeType(varType))
 	}
 
-	return cotypes.IntType{}, nil
+	value, err := tc.coerceExpression(stmt.Value, varType)
+	if err != nil && narrowed && !isCompound && stmt.Value.GetType() != nil {
+		if value, err = tc.convertExpression(stmt.Value, stmt.Value.GetType(), sym.typ); err == nil {
+			varType, narrowed = sym.typ, false
+			delete(tc.narrowed, sym.decl)
+		}
+	}
+
+	if err != nil {
+		if stmt.Value.GetType() == nil {
+			return err
+		}
+
+		if isCompound {
+			return tc.addErrorAtNode(stmt, "cannot perform %s operation on %s and %s", stmt.Operator.Literal, varType, stmt.Value.GetType())
+		}
+
+		return tc.addErrorAtNode(stmt, "cannot assign %s to variable %s of type %s", stmt.Value.GetType(), varName, sym.typ)
+	}
+
+	stmt.Value = value
+	stmt.Identifier.Narrowed = narrowed
+	stmt.Identifier.SetType(varType)
+	return nil
 }
 
-func (tc *TypeChecker) checkFloatBuiltin(expr *ast.CallExpression) (t cotypes.Type, err error) {
-	if len(expr.Arguments) != 1 {
-		return t, fmt.Errorf("too many arguments. expected one argument, got %d arguments", len(expr.Arguments))
+// type checks the expression and converts it into target type, if it is allowed to be converted implicitly.
+// returns the expression which should replace the original one. if the expression itself fails to type check
+// its type is left unset and the error is already reported, otherwise the mismatch is left to the caller to report
+func (tc *TypeChecker) coerceExpression(expr ast.Expression, target cotypes.Type) (ast.Expression, error) {
+	// literals are converted into the type wrapped by an optional before being wrapped themselves
+	if optionalType, ok := target.(cotypes.OptionalType); ok && isContextuallyTyped(expr) {
+		value, err := tc.coerceExpression(expr, optionalType.Inner)
+		if err != nil {
+			return nil, err
+		}
+
+		return &ast.CastExpression{Expr: value, Type: optionalType}, nil
 	}
 
-	valType, err := tc.checkExpression(expr.Arguments[0])
+	// ok(...) and err(...) create a result of the type they are used as
+	if resultType, ok := target.(cotypes.ResultType); ok {
+		if callExpr, ok := expr.(*ast.CallExpression); ok && isResultConstructor(callExpr) {
+			return tc.checkResultConstructor(callExpr, resultType)
+		}
+	}
+
+	// elements of tuple literals are converted one by one, eg. (1, none) can be used as (float, string?)
+	if tupleExpr, ok := expr.(*ast.TupleExpression); ok {
+		if tupleType, ok := target.(cotypes.TupleType); ok && len(tupleType.Elements) == len(tupleExpr.Elements) {
+			return tc.checkTupleLiteral(tupleExpr, tupleType)
+		}
+	}
+
+	// array literals are turned into lists when a list is expected, which is also how empty lists are created
+	if arrayExpr, ok := expr.(*ast.ArrayExpression); ok {
+		if listType, ok := target.(cotypes.ListType); ok {
+			return tc.checkListLiteral(arrayExpr, listType)
+		}
+
+		// elements are converted one by one when an array of the same size is expected, eg. [1, 2] can be used as [u8; 2]
+		if arrayType, ok := target.(cotypes.ArrayType); ok && arrayType.Size == int64(len(arrayExpr.Elements)) {
+			return tc.checkArrayLiteral(arrayExpr, arrayType)
+		}
+	}
+
+	// same goes for map literals, so that empty maps can be created and values widened
+	if mapExpr, ok := expr.(*ast.MapExpression); ok {
+		if mapType, ok := target.(cotypes.MapType); ok {
+			return tc.checkMapLiteral(mapExpr, mapType)
+		}
+	}
+
+	// the value of each branch of if and match expressions is converted on its own, so that the branches can be
+	// literals of their own, eg. if (c) { none } else { 3 } can be used as int?
+	switch e := expr.(type) {
+	case *ast.IfExpression:
+		return tc.checkBranchesAs(e, target, func() (cotypes.Type, error) { return tc.checkIfExpression(e, target) })
+	case *ast.MatchExpression:
+		return tc.checkBranchesAs(e, target, func() (cotypes.Type, error) { return tc.checkMatchExpression(e, target) })
+	}
+
+	exprType, err := tc.checkExpression(expr)
 	if err != nil {
-		return t, tc.propagateOrWrapError(err, expr, "failed to type check float func arg: %s", err.Error())
+		return nil, err
 	}
 
-	if cotypes.GetTypeCategory(valType) != cotypes.CategoryNumeric {
-		return t, fmt.Errorf("cannot convert %s to float", valType)
+	value, err := tc.convertExpression(expr, exprType, target)
+
+	// constants which do not fit into the expected type are reported right away, and the type of the expression
+	// is unset so that the caller does not report a mismatch as well
+	if overflow, ok := err.(*constantOverflowError); ok {
+		expr.SetType(nil)
+		return nil, tc.addErrorAtNode(expr, "%s", overflow.Error())
 	}
 
-	return cotypes.FloatType{}, nil
+	return value, err
 }
 
-func (tc *TypeChecker) Transform(program *ast.Program) *ast.Program {
-	for _, stmt := range program.Statements {
-		tc.checkStatement(stmt)
+// type checks an if or match expression whose branch values are converted into target type by check, see coerceExpression
+func (tc *TypeChecker) checkBranchesAs(expr ast.Expression, target cotypes.Type, check func() (cotypes.Type, error)) (ast.Expression, error) {
+	if _, err := check(); err != nil {
+		return nil, tc.propagateOrWrapError(err, expr, "%s", err.Error())
+	}
+
+	expr.SetType(target)
+	return expr, nil
+}
+
+// converts the already type checked expression into target type, see coerceExpression
+func (tc *TypeChecker) convertExpression(expr ast.Expression, exprType cotypes.Type, target cotypes.Type) (ast.Expression, error) {
+	if exprType.Equals(target) {
+		return expr, nil
+	}
+
+	// values are wrapped into optionals implicitly, and none takes on the optional type it is used as
+	if optionalType, ok := target.(cotypes.OptionalType); ok {
+		if exprType.Equals(cotypes.NoneType{}) {
+			expr.SetType(optionalType)
+			return expr, nil
+		}
+
+		value, err := tc.convertExpression(expr, exprType, optionalType.Inner)
+		if err == nil {
+			return &ast.CastExpression{Expr: value, Type: optionalType}, nil
+		}
+
+		if _, ok := err.(*constantOverflowError); ok {
+			return nil, err
+		}
+	}
+
+	// int to float widening
+	if exprType.Equals(cotypes.IntType{}) && target.Equals(cotypes.FloatType{}) {
+		return widenToFloat(expr), nil
+	}
+
+	// int and float constants can be used as any numeric type which can hold their value, eg. 255 as u8 or 0.5 as f32
+	if isSizedNumeric(target) && (exprType.Equals(cotypes.IntType{}) || exprType.Equals(cotypes.FloatType{})) {
+		if value, err := tc.foldConstant(expr); err == nil {
+			return convertConstant(value, target)
+		}
+	}
+
+	// the other numeric conversions are implicit only if they do not lose information, eg. u8 to i32 or f32 to float.
+	// narrowing conversions have to be explicit, eg. u8(x)
+	if widensTo(exprType, target) {
+		return &ast.CastExpression{Expr: expr, Type: target}, nil
+	}
+
+	// elements of array literals are widened one by one, eg. [1, 2] can be used as [float; 2]
+	if arrayExpr, ok := expr.(*ast.ArrayExpression); ok && exprType.Equals(cotypes.ArrayType{Element: cotypes.IntType{}, Size: int64(len(arrayExpr.Elements))}) {
+		if targetArray, ok := target.(cotypes.ArrayType); ok && targetArray.Equals(cotypes.ArrayType{Element: cotypes.FloatType{}, Size: int64(len(arrayExpr.Elements))}) {
+			for i, element := range arrayExpr.Elements {
+				arrayExpr.Elements[i] = widenToFloat(element)
+			}
+
+			arrayExpr.SetType(target)
+			return arrayExpr, nil
+		}
 	}
 
+	return nil, fmt.Errorf("cannot use %s as %s", exprType, target)
+}
+
+func (tc *TypeChecker) resolveTypeAnnotation(annotation ast.TypeAnnotation) (t cotypes.Type, err error) {
+	switch a := annotation.(type) {
+	case *ast.NamedTypeAnnotation:
+		if numericType, ok := cotypes.NumericType(a.Name); ok {
+			return numericType, nil
+		}
+
+		switch a.Name {
+		case "bool":
+			return cotypes.BoolType{}, nil
+		case "string":
+			return cotypes.StringType{}, nil
+		case "char":
+			return cotypes.CharType{}, nil
+		case "void":
+			return cotypes.VoidType{}, nil
+		default:
+			if typeParam, ok := tc.typeParams[a.Name]; ok {
+				return typeParam, nil
+			}
+
+			if structType, ok := tc.structs[a.Name]; ok {
+				return structType, nil
+			}
+
+			if enumType, ok := tc.enums[a.Name]; ok {
+				return enumType, nil
+			}
+
+			return t, fmt.Errorf("unknown type: %s", a.Name)
+		}
+	case *ast.ArrayTypeAnnotation:
+		elementType, err := tc.resolveTypeAnnotation(a.Element)
+		if err != nil {
+			return t, err
+		}
+
+		if elementType.Equals(cotypes.VoidType{}) {
+			return t, fmt.Errorf("invalid array element type: %s", elementType)
+		}
+
+		return cotypes.ArrayType{Element: elementType, Size: a.Size}, nil
+	case *ast.ResultTypeAnnotation:
+		valueType, err := tc.resolveTypeAnnotation(a.Value)
+		if err != nil {
+			return t, err
+		}
+
+		errorType, err := tc.resolveTypeAnnotation(a.Error)
+		if err != nil {
+			return t, err
+		}
+
+		if valueType.Equals(cotypes.VoidType{}) || errorType.Equals(cotypes.VoidType{}) {
+			return t, fmt.Errorf("invalid result type: %s", cotypes.ResultType{Value: valueType, Error: errorType})
+		}
+
+		return cotypes.ResultType{Value: valueType, Error: errorType}, nil
+	case *ast.TupleTypeAnnotation:
+		elementTypes := []cotypes.Type{}
+		for _, element := range a.Elements {
+			elementType, err := tc.resolveTypeAnnotation(element)
+			if err != nil {
+				return t, err
+			}
+
+			if elementType.Equals(cotypes.VoidType{}) {
+				return t, fmt.Errorf("invalid tuple element type: %s", elementType)
+			}
+
+			elementTypes = append(elementTypes, elementType)
+		}
+
+		return cotypes.TupleType{Elements: elementTypes}, nil
+	case *ast.OptionalTypeAnnotation:
+		innerType, err := tc.resolveTypeAnnotation(a.Inner)
+		if err != nil {
+			return t, err
+		}
+
+		if innerType.Equals(cotypes.VoidType{}) {
+			return t, fmt.Errorf("invalid optional type: %s?", innerType)
+		}
+
+		return cotypes.OptionalType{Inner: innerType}, nil
+	case *ast.ListTypeAnnotation:
+		elementType, err := tc.resolveTypeAnnotation(a.Element)
+		if err != nil {
+			return t, err
+		}
+
+		if elementType.Equals(cotypes.VoidType{}) {
+			return t, fmt.Errorf("invalid list element type: %s", elementType)
+		}
+
+		return cotypes.ListType{Element: elementType}, nil
+	case *ast.FunctionTypeAnnotation:
+		functionType := cotypes.FunctionType{Params: []cotypes.Type{}, Return: cotypes.VoidType{}}
+		for _, param := range a.Params {
+			paramType, err := tc.resolveTypeAnnotation(param)
+			if err != nil {
+				return t, err
+			}
+
+			if paramType.Equals(cotypes.VoidType{}) {
+				return t, fmt.Errorf("invalid function parameter type: %s", paramType)
+			}
+
+			functionType.Params = append(functionType.Params, paramType)
+		}
+
+		if a.Return != nil {
+			returnType, err := tc.resolveTypeAnnotation(a.Return)
+			if err != nil {
+				return t, err
+			}
+
+			functionType.Return = returnType
+		}
+
+		return functionType, nil
+	case *ast.MapTypeAnnotation:
+		keyType, err := tc.resolveTypeAnnotation(a.Key)
+		if err != nil {
+			return t, err
+		}
+
+		if !cotypes.IsHashable(keyType) {
+			return t, fmt.Errorf("type %s cannot be used as map key", keyType)
+		}
+
+		valueType, err := tc.resolveTypeAnnotation(a.Value)
+		if err != nil {
+			return t, err
+		}
+
+		if valueType.Equals(cotypes.VoidType{}) {
+			return t, fmt.Errorf("invalid map value type: %s", valueType)
+		}
+
+		return cotypes.MapType{Key: keyType, Value: valueType}, nil
+	default:
+		return t, fmt.Errorf("unknown type annotation %T", annotation)
+	}
+}
+
+// resolves the parameter and return types of a function statement and registers its signature,
+// so that functions can be called before they are declared
+func (tc *TypeChecker) declareFunction(stmt *ast.FunctionStatement) error {
+	funcName := stmt.Identifier.String()
+	if _, isBuiltin := tc.builtins[funcName]; isBuiltin {
+		return tc.addErrorAtNode(stmt, "cannot redeclare builtin function: %s", funcName)
+	}
+
+	if _, exists := tc.functions[funcName]; exists {
+		return tc.addErrorAtNode(stmt, "cannot redeclare function: %s", funcName)
+	}
+
+	info := &functionInfo{
+		name:       funcName,
+		params:     []cotypes.Type{},
+		returnType: cotypes.VoidType{},
+	}
+
+	if len(stmt.TypeParameters) > 0 {
+		// copied before anything is resolved, so that every instance starts off from the declaration as written
+		info.generic = ast.Clone(stmt)
+		info.decl = stmt
+
+		defer func() {
+			tc.typeParams = nil
+		}()
+
+		typeParams, err := tc.declareTypeParameters(stmt)
+		if err != nil {
+			return err
+		}
+
+		info.typeParams = typeParams
+	}
+
+	for _, param := range stmt.Function.Parameters {
+		if param.Annotation == nil {
+			return tc.addErrorAtNode(stmt, "missing type annotation for parameter %s of function %s", param.Identifier.String(), funcName)
+		}
+
+		paramType, err := tc.resolveTypeAnnotation(param.Annotation)
+		if err != nil {
+			return tc.propagateOrWrapError(err, stmt, "failed to resolve type of parameter %s: %s", param.Identifier.String(), err.Error())
+		}
+
+		if paramType.Equals(cotypes.VoidType{}) {
+			return tc.addErrorAtNode(stmt, "parameter %s of function %s cannot be of type void", param.Identifier.String(), funcName)
+		}
+
+		param.Identifier.SetType(paramType)
+		info.params = append(info.params, paramType)
+	}
+
+	if stmt.Function.ReturnAnnotation != nil {
+		returnType, err := tc.resolveTypeAnnotation(stmt.Function.ReturnAnnotation)
+		if err != nil {
+			return tc.propagateOrWrapError(err, stmt, "failed to resolve return type of function %s: %s", funcName, err.Error())
+		}
+
+		info.returnType = returnType
+	}
+
+	stmt.Function.ReturnType = info.returnType
+	tc.functions[funcName] = info
+	return nil
+}
+
+// resolves the type parameters of a generic function and brings them into scope, left opaque
+func (tc *TypeChecker) declareTypeParameters(stmt *ast.FunctionStatement) ([]cotypes.TypeParamType, error) {
+	funcName := stmt.Identifier.String()
+	typeParams := []cotypes.TypeParamType{}
+	tc.typeParams = make(map[string]cotypes.Type)
+
+	for _, typeParam := range stmt.TypeParameters {
+		name := typeParam.Identifier.String()
+		if _, exists := tc.typeParams[name]; exists {
+			return nil, tc.addErrorAtNode(stmt, "duplicate type parameter %s in function %s", name, funcName)
+		}
+
+		if _, err := tc.resolveTypeAnnotation(&ast.NamedTypeAnnotation{Name: name}); err == nil {
+			return nil, tc.addErrorAtNode(stmt, "type parameter %s of function %s shadows type %s", name, funcName, name)
+		}
+
+		constraint := cotypes.ConstraintAny
+		if typeParam.Constraint != nil {
+			var ok bool
+			if constraint, ok = resolveConstraint(typeParam.Constraint.String()); !ok {
+				return nil, tc.addErrorAtNode(stmt, "unknown constraint %s of type parameter %s", typeParam.Constraint, name)
+			}
+		}
+
+		param := cotypes.TypeParamType{Name: name, Constraint: constraint}
+		typeParams = append(typeParams, param)
+		tc.typeParams[name] = param
+	}
+
+	return typeParams, nil
+}
+
+func (tc *TypeChecker) checkFunctionStatement(stmt *ast.FunctionStatement) error {
+	funcName := stmt.Identifier.String()
+
+	if tc.currentFunction != nil || tc.env.Parent() != nil {
+		return tc.addErrorAtNode(stmt, "function %s must be declared at top level", funcName)
+	}
+
+	// signature failed to resolve during declaration, error is already reported
+	if stmt.Function.ReturnType == nil {
+		return nil
+	}
+
+	info := tc.functions[funcName]
+
+	// the body of a generic function is checked against its type parameters, its instances are checked separately
+	if len(info.typeParams) > 0 {
+		tc.typeParams = make(map[string]cotypes.Type)
+		for _, typeParam := range info.typeParams {
+			tc.typeParams[typeParam.Name] = typeParam
+		}
+
+		defer func() {
+			tc.typeParams = nil
+		}()
+	}
+
+	// function bodies cannot see variables of the enclosing scope, only top level constants and variables which are
+	// declared before the function
+	previousEnv, previousLoopLabels := tc.env, tc.loopLabels
+	tc.env = env.NewEnvironmentWithParent(tc.globals)
+	tc.currentFunction, tc.enclosingFunction = info, info
+	tc.loopLabels = nil
+
+	defer func() {
+		tc.env, tc.loopLabels = previousEnv, previousLoopLabels
+		tc.currentFunction, tc.enclosingFunction = nil, nil
+	}()
+
+	for _, param := range stmt.Function.Parameters {
+		paramName := param.Identifier.String()
+		if tc.env.Has(paramName) {
+			return tc.addErrorAtNode(stmt, "duplicate parameter %s in function %s", paramName, funcName)
+		}
+
+		tc.env.Set(paramName, symbol{
+			typ:  param.Identifier.GetType(),
+			decl: param.Identifier,
+		})
+	}
+
+	tc.checkStatement(stmt.Function.Body)
+
+	if !info.returnType.Equals(cotypes.VoidType{}) && !alwaysReturns(stmt.Function.Body) {
+		return tc.addErrorAtNode(stmt, "function %s must return a value of type %s on all paths", funcName, info.returnType)
+	}
+
+	return nil
+}
+
+// function literals evaluate to closures. unlike function statements, their bodies can use the variables
+// of the enclosing scopes, which are captured by reference
+func (tc *TypeChecker) checkFunctionExpression(expr *ast.FunctionExpression) (t cotypes.Type, err error) {
+	info := &functionInfo{
+		name:       "literal",
+		params:     []cotypes.Type{},
+		returnType: cotypes.VoidType{},
+	}
+
+	for _, param := range expr.Parameters {
+		if param.Annotation == nil {
+			return t, fmt.Errorf("missing type annotation for parameter %s of function literal", param.Identifier)
+		}
+
+		paramType, err := tc.resolveTypeAnnotation(param.Annotation)
+		if err != nil {
+			return t, fmt.Errorf("failed to resolve type of parameter %s: %s", param.Identifier, err.Error())
+		}
+
+		if paramType.Equals(cotypes.VoidType{}) {
+			return t, fmt.Errorf("parameter %s of function literal cannot be of type void", param.Identifier)
+		}
+
+		param.Identifier.SetType(paramType)
+		info.params = append(info.params, paramType)
+	}
+
+	if expr.ReturnAnnotation != nil {
+		returnType, err := tc.resolveTypeAnnotation(expr.ReturnAnnotation)
+		if err != nil {
+			return t, fmt.Errorf("failed to resolve return type of function literal: %s", err.Error())
+		}
+
+		info.returnType = returnType
+	}
+
+	expr.ReturnType = info.returnType
+	expr.Captures = []string{}
+
+	// narrowing does not carry over into the function literal, which can be called after the variables
+	// are assigned none again
+	previousEnv, previousFunction, previousLoopLabels, previousNarrowed := tc.env, tc.currentFunction, tc.loopLabels, tc.narrowed
+	tc.closures = append(tc.closures, &closureInfo{expr: expr, outer: tc.env, narrowed: tc.narrowed})
+	tc.env = env.NewEnvironmentWithParent(tc.env)
+	tc.currentFunction = info
+	tc.loopLabels = nil
+	tc.narrowed = make(map[*ast.IdentifierExpression]bool)
+
+	defer func() {
+		tc.env, tc.currentFunction, tc.loopLabels, tc.narrowed = previousEnv, previousFunction, previousLoopLabels, previousNarrowed
+		tc.closures = tc.closures[:len(tc.closures)-1]
+	}()
+
+	for _, param := range expr.Parameters {
+		paramName := param.Identifier.String()
+		if tc.env.Has(paramName) {
+			return t, fmt.Errorf("duplicate parameter %s in function literal", paramName)
+		}
+
+		tc.env.Set(paramName, symbol{
+			typ:  param.Identifier.GetType(),
+			decl: param.Identifier,
+		})
+	}
+
+	tc.checkStatement(expr.Body)
+
+	if !info.returnType.Equals(cotypes.VoidType{}) && !alwaysReturns(expr.Body) {
+		return t, fmt.Errorf("function literal must return a value of type %s on all paths", info.returnType)
+	}
+
+	return cotypes.FunctionType{Params: info.params, Return: info.returnType}, nil
+}
+
+func (tc *TypeChecker) checkReturnStatement(stmt *ast.ReturnStatement) error {
+	if tc.currentFunction == nil {
+		return tc.addErrorAtNode(stmt, "return statement outside of function")
+	}
+
+	expectedType := tc.currentFunction.returnType
+
+	if stmt.Expr == nil {
+		if !expectedType.Equals(cotypes.VoidType{}) {
+			return tc.addErrorAtNode(stmt, "function %s must return a value of type %s", tc.currentFunction.name, expectedType)
+		}
+
+		return nil
+	}
+
+	value, err := tc.coerceExpression(stmt.Expr, expectedType)
+	if err != nil {
+		if stmt.Expr.GetType() == nil {
+			return err
+		}
+
+		return tc.addErrorAtNode(stmt, "cannot return %s from function %s with return type %s", stmt.Expr.GetType(), tc.currentFunction.name, expectedType)
+	}
+
+	stmt.Expr = value
+	return nil
+}
+
+// identifiers refer to variables, or to functions which are then used as function values
+func (tc *TypeChecker) checkIdentifierExpression(expr *ast.IdentifierExpression) (t cotypes.Type, err error) {
+	if sym, found := tc.lookupVariable(expr.String()); found {
+		if tc.narrowed[sym.decl] {
+			expr.Narrowed = true
+			return sym.typ.(cotypes.OptionalType).Inner, nil
+		}
+
+		return sym.typ, nil
+	}
+
+	if function, isFunction := tc.functions[expr.String()]; isFunction {
+		if len(function.typeParams) > 0 {
+			return t, fmt.Errorf("generic function %s cannot be used as a value", expr)
+		}
+
+		tc.useFunction(expr, function)
+		return cotypes.FunctionType{Params: function.params, Return: function.returnType}, nil
+	}
+
+	if _, isBuiltin := tc.builtins[expr.String()]; isBuiltin {
+		return t, fmt.Errorf("builtin function %s cannot be used as a value", expr)
+	}
+
+	return t, fmt.Errorf("unknown identifier: %s", expr.String())
+}
+
+// looks up a variable, recording it as captured by the enclosing function literals which it is declared outside of
+func (tc *TypeChecker) lookupVariable(name string) (symbol, bool) {
+	scope := tc.env
+	for scope != nil && !scope.Has(name) {
+		scope = scope.Parent()
+	}
+
+	if scope == nil {
+		return symbol{}, false
+	}
+
+	sym, _ := scope.Get(name)
+	if scope == tc.globals && !sym.isConst && tc.enclosingFunction != nil && !slices.Contains(tc.enclosingFunction.globalsUsed, sym.decl) {
+		tc.enclosingFunction.globalsUsed = append(tc.enclosingFunction.globalsUsed, sym.decl)
+	}
+
+	for _, closure := range tc.closures {
+		if !isScopeOrParent(scope, closure.outer) || slices.Contains(closure.expr.Captures, name) {
+			continue
+		}
+
+		closure.expr.Captures = append(closure.expr.Captures, name)
+		if sym.decl != nil {
+			sym.decl.Captured = true
+		}
+	}
+
+	return sym, true
+}
+
+// embedded expressions of interpolated strings can be of any type which can be printed
+func (tc *TypeChecker) checkInterpolationExpression(expr *ast.InterpolationExpression) (t cotypes.Type, err error) {
+	for _, part := range expr.Parts {
+		partType, err := tc.checkExpression(part)
+		if err != nil {
+			return t, tc.propagateOrWrapError(err, expr, "failed to type check interpolated expression %s: %s", part, err.Error())
+		}
+
+		if !cotypes.ConstraintComparable.SatisfiedBy(partType) {
+			return t, fmt.Errorf("cannot interpolate value of type %s", describeType(partType))
+		}
+	}
+
+	return cotypes.StringType{}, nil
+}
+
+func (tc *TypeChecker) checkUnaryExpression(expr *ast.UnaryExpression) (t cotypes.Type, err error) {
+	operandType, err := tc.checkExpression(expr.Expr)
+	if err != nil {
+		return t, tc.propagateOrWrapError(err, expr, "failed to type check operand: %s", err.Error())
+	}
+
+	op := expr.Token.Type
+
+	switch op {
+	case tokens.MINUS:
+		// unsigned integers cannot be negated
+		if _, signed := cotypes.IntegerLayout(operandType); isNumeric(operandType) && signed {
+			return operandType, nil
+		}
+	case tokens.BANG:
+		if operandType.Equals(cotypes.BoolType{}) {
+			return operandType, nil
+		}
+	case tokens.INCREMENT, tokens.DECREMENT:
+		// increment and decrement write back to the operand, so it must be a mutable variable
+		ident, ok := expr.Expr.(*ast.IdentifierExpression)
+		if !ok {
+			return t, fmt.Errorf("operand of %s operation must be a variable, got %s", op, expr.Expr)
+		}
+
+		if sym, _ := tc.env.Get(ident.String()); sym.isConst {
+			return t, fmt.Errorf("cannot assign to constant: %s", ident.String())
+		}
+
+		if isNumeric(operandType) {
+			return operandType, nil
+		}
+	}
+
+	return t, fmt.Errorf("cannot perform %s operation on %s", op, describeType(operandType))
+}
+
+func (tc *TypeChecker) checkBinaryExpression(expr *ast.BinaryExpression) (t cotypes.Type, err error) {
+	if expr.Operator.Type == tokens.DOUBLE_QUESTION {
+		return tc.checkCoalesceExpression(expr)
+	}
+
+	leftType, err := tc.checkExpression(expr.Left)
+	if err != nil {
+		return t, tc.propagateOrWrapError(err, expr, "failed to type check left operand: %s", err.Error())
+	}
+
+	// the right operand of && is only evaluated if the left one is true, and the one of || if it is false
+	whenTrue, whenFalse := tc.narrowingsOf(expr.Left)
+	leave := func() map[*ast.IdentifierExpression]bool { return nil }
+	switch expr.Operator.Type {
+	case tokens.AND:
+		leave = tc.narrow(whenTrue)
+	case tokens.OR:
+		leave = tc.narrow(whenFalse)
+	}
+
+	rightType, err := tc.checkExpression(expr.Right)
+	leave()
+	if err != nil {
+		return t, tc.propagateOrWrapError(err, expr, "failed to type check right operand: %s", err.Error())
+	}
+
+	leftTypeCategory := cotypes.GetTypeCategory(leftType)
+	rightTypeCategory := cotypes.GetTypeCategory(rightType)
+
+	if leftType == nil || rightType == nil {
+		return t, err
+	}
+
+	op := expr.Operator.Type
+	isEqualityOperator := op == tokens.EQUALS || op == tokens.NOT_EQUALS
+
+	// optionals can be compared against none
+	_, isLeftOptional := leftType.(cotypes.OptionalType)
+	_, isRightOptional := rightType.(cotypes.OptionalType)
+	if isEqualityOperator && ((isLeftOptional && rightType.Equals(cotypes.NoneType{})) || (isRightOptional && leftType.Equals(cotypes.NoneType{}))) {
+		return expr.SetType(cotypes.BoolType{}), err
+	}
+	isComparisonOperator := op == tokens.LESS_THAN || op == tokens.GREATER_THAN || op == tokens.LESS_THAN_EQUALS || op == tokens.GREATER_THAN_EQUALS || isEqualityOperator
+	isArithmeticOperator := op == tokens.PLUS || op == tokens.MINUS || op == tokens.STAR || op == tokens.SLASH || op == tokens.MODULO || op == tokens.DOUBLE_STAR
+
+	// type parameters only support the operations permitted by their constraint, between values of the same type parameter
+	if typeParam, ok := leftType.(cotypes.TypeParamType); ok && leftType.Equals(rightType) {
+		if isArithmeticOperator && typeParam.Constraint >= cotypes.ConstraintNumeric {
+			return expr.SetType(leftType), err
+		}
+
+		if (isEqualityOperator && typeParam.Constraint >= cotypes.ConstraintComparable) || (isComparisonOperator && typeParam.Constraint >= cotypes.ConstraintOrdered) {
+			return expr.SetType(cotypes.BoolType{}), err
+		}
+	}
+
+	// numeric types
+	if leftTypeCategory == cotypes.CategoryNumeric && rightTypeCategory == cotypes.CategoryNumeric && (isArithmeticOperator || isComparisonOperator) {
+		if operandType, ok := tc.unifyNumericOperands(expr); ok {
+			// arithmetic operators
+			if isArithmeticOperator {
+				return expr.SetType(operandType), err
+			}
+
+			// comparison operators
+			return expr.SetType(cotypes.BoolType{}), err
+		}
+	}
+
+	// strings
+	if leftType.Equals(cotypes.StringType{}) && rightType.Equals(cotypes.StringType{}) {
+		// string concatenation
+		if op == tokens.PLUS {
+			return expr.SetType(cotypes.StringType{}), err
+		}
+
+		// lexicographical comparison
+		if isComparisonOperator {
+			return expr.SetType(cotypes.BoolType{}), err
+		}
+	}
+
+	// chars are compared by their code point
+	if leftType.Equals(cotypes.CharType{}) && rightType.Equals(cotypes.CharType{}) && isComparisonOperator {
+		return expr.SetType(cotypes.BoolType{}), err
+	}
+
+	// bools
+	if leftType.Equals(cotypes.BoolType{}) && rightType.Equals(cotypes.BoolType{}) {
+		if op == tokens.EQUALS || op == tokens.NOT_EQUALS || op == tokens.AND || op == tokens.OR {
+			return expr.SetType(cotypes.BoolType{}), err
+		}
+	}
+
+	err = fmt.Errorf("cannot perform %s operation on %s and %s", op, describeType(leftType), describeType(rightType))
+	return
+}
+
+// converts the operands of a numeric binary expression to the same type and returns it, see unifyValues
+func (tc *TypeChecker) unifyNumericOperands(expr *ast.BinaryExpression) (cotypes.Type, bool) {
+	t, err := tc.unifyValues([]*ast.Expression{&expr.Left, &expr.Right}, "operands")
+	return t, err == nil
+}
+
+// converts the already type checked values, eg. elements of an array literal or branches of an if expression, to
+// the same type and returns it. int and float constants take on the type of the other values if they fit into it,
+// so that both [x, 1] and x + 1 are of type u8 if x is. otherwise the values are widened to the widest of their
+// types, eg. u8 and i32 to i32 or int to float. what describes the values in error messages, eg. array elements
+func (tc *TypeChecker) unifyValues(values []*ast.Expression, what string) (t cotypes.Type, err error) {
+	mismatch := func(i int, typ cotypes.Type) error {
+		// types are reported in the order of the values, so the offending one is compared with the first one
+		if i == 0 {
+			return fmt.Errorf("mismatched types of %s: %s and %s", what, typ, t)
+		}
+
+		return fmt.Errorf("mismatched types of %s: %s and %s", what, (*values[0]).GetType(), typ)
+	}
+
+	// widens the type to fit values of typ as well
+	widen := func(typ cotypes.Type) bool {
+		switch {
+		case t == nil || widensImplicitly(t, typ):
+			t = typ
+		case t.Equals(typ) || widensImplicitly(typ, t):
+		default:
+			return false
+		}
+
+		return true
+	}
+
+	isUntypedConstant := func(value ast.Expression) bool {
+		typ := value.GetType()
+		if !typ.Equals(cotypes.IntType{}) && !typ.Equals(cotypes.FloatType{}) {
+			return false
+		}
+
+		_, err := tc.foldConstant(value)
+		return err == nil
+	}
+
+	// the other values decide the type first
+	for i, value := range values {
+		if !isUntypedConstant(*value) && !widen((*value).GetType()) {
+			return nil, mismatch(i, (*value).GetType())
+		}
+	}
+
+	// and constants are widened to it, unless they do not fit, eg. 300 next to an u8 makes both of them int
+	for i, value := range values {
+		if !isUntypedConstant(*value) {
+			continue
+		}
+
+		typ := (*value).GetType()
+		if t != nil && isNumeric(t) {
+			if _, err := tc.convertExpression(*value, typ, t); err == nil {
+				continue
+			}
+		}
+
+		if !widen(typ) {
+			return nil, mismatch(i, typ)
+		}
+	}
+
+	for i, value := range values {
+		typ := (*value).GetType()
+		if typ.Equals(t) {
+			continue
+		}
+
+		converted, err := tc.convertExpression(*value, typ, t)
+		if err != nil {
+			return nil, mismatch(i, typ)
+		}
+		*value = converted
+	}
+
+	return t, nil
+}
+
+// <optional> ?? <default> evaluates to the value of the optional, or to the default if it is none. the default is
+// only evaluated if it is needed and can be an optional itself, in which case the result is optional as well
+func (tc *TypeChecker) checkCoalesceExpression(expr *ast.BinaryExpression) (t cotypes.Type, err error) {
+	leftType, err := tc.checkExpression(expr.Left)
+	if err != nil {
+		return t, tc.propagateOrWrapError(err, expr, "failed to type check left operand: %s", err.Error())
+	}
+
+	optionalType, ok := leftType.(cotypes.OptionalType)
+	if !ok {
+		return t, fmt.Errorf("left operand of ?? must be optional, got %s", leftType)
+	}
+
+	right, err := tc.coerceExpression(expr.Right, optionalType.Inner)
+	if err == nil {
+		expr.Right = right
+		return optionalType.Inner, nil
+	}
+
+	rightType := expr.Right.GetType()
+	switch {
+	case rightType == nil:
+		return t, tc.propagateOrWrapError(err, expr, "failed to type check right operand: %s", err.Error())
+	case rightType.Equals(optionalType):
+		return optionalType, nil
+	case rightType.Equals(cotypes.NoneType{}):
+		expr.Right.SetType(optionalType)
+		return optionalType, nil
+	default:
+		return t, fmt.Errorf("cannot use %s as default value for %s", rightType, optionalType)
+	}
+}
+
+func (tc *TypeChecker) checkCallExpression(expr *ast.CallExpression) (t cotypes.Type, err error) {
+	if expr.Identifier == nil {
+		return tc.checkFunctionValueCall(expr)
+	}
+
+	if builtin, isBuiltin := tc.builtins[expr.Identifier.String()]; isBuiltin {
+		expr.IsBuiltin = true
+		expr.BuiltinKind = &builtin.kind
+		return builtin.checker(expr)
+	}
+
+	// variables holding function values take precedence over functions with the same name
+	if sym, found := tc.env.Get(expr.Identifier.String()); found {
+		if _, ok := sym.typ.(cotypes.FunctionType); ok {
+			expr.Callee = expr.Identifier
+			return tc.checkFunctionValueCall(expr)
+		}
+	}
+
+	if function, isFunction := tc.functions[expr.Identifier.String()]; isFunction {
+		tc.useFunction(expr, function)
+		if len(function.typeParams) > 0 {
+			return tc.checkGenericFunctionCall(expr, function)
+		}
+
+		return tc.checkFunctionCall(expr, function)
+	}
+
+	if sym, found := tc.env.Get(expr.Identifier.String()); found {
+		return t, fmt.Errorf("cannot call value of type %s", sym.typ)
+	}
+
+	err = fmt.Errorf("cannot call %s identifier", expr.Identifier.String())
+	return
+}
+
+// records the use of a function by the function whose body is being checked, or by top level code
+func (tc *TypeChecker) useFunction(node ast.Node, function *functionInfo) {
+	if tc.enclosingFunction == nil {
+		tc.topLevelUses = append(tc.topLevelUses, topLevelFunctionUse{node: node, function: function, initialized: len(tc.globalsOrder)})
+		return
+	}
+
+	if !slices.Contains(tc.enclosingFunction.functionsUsed, function) {
+		tc.enclosingFunction.functionsUsed = append(tc.enclosingFunction.functionsUsed, function)
+	}
+}
+
+// functions can be called before the top level variables they use are initialized, eg. by top level code which
+// precedes the declaration of the variables. any function used in top level code is assumed to be called right
+// away, along with the functions it uses in turn
+func (tc *TypeChecker) checkFunctionUses() {
+	for _, use := range tc.topLevelUses {
+		visited := map[*functionInfo]bool{}
+		pending := []*functionInfo{use.function}
+		for len(pending) > 0 {
+			function := pending[len(pending)-1]
+			pending = pending[:len(pending)-1]
+			if visited[function] {
+				continue
+			}
+			visited[function] = true
+
+			if idx := slices.IndexFunc(function.globalsUsed, func(decl *ast.IdentifierExpression) bool {
+				return tc.globalsOrder[decl] >= use.initialized
+			}); idx >= 0 {
+				tc.addErrorAtNode(use.node, "function %s uses variable %s before it is initialized", use.function.name, function.globalsUsed[idx])
+				break
+			}
+
+			pending = append(pending, function.functionsUsed...)
+		}
+	}
+}
+
+func (tc *TypeChecker) checkFunctionValueCall(expr *ast.CallExpression) (t cotypes.Type, err error) {
+	calleeType, err := tc.checkExpression(expr.Callee)
+	if err != nil {
+		return t, tc.propagateOrWrapError(err, expr, "failed to type check called expression: %s", err.Error())
+	}
+
+	functionType, ok := calleeType.(cotypes.FunctionType)
+	if !ok {
+		return t, fmt.Errorf("cannot call value of type %s", calleeType)
+	}
+
+	return tc.checkFunctionCall(expr, &functionInfo{
+		name:       expr.Callee.String(),
+		params:     functionType.Params,
+		returnType: functionType.Return,
+	})
+}
+
+func (tc *TypeChecker) checkFunctionCall(expr *ast.CallExpression, function *functionInfo) (t cotypes.Type, err error) {
+	if len(expr.Arguments) != len(function.params) {
+		return t, fmt.Errorf("wrong number of arguments to %s. expected %d arguments, got %d arguments", function.name, len(function.params), len(expr.Arguments))
+	}
+
+	for i, arg := range expr.Arguments {
+		value, err := tc.coerceExpression(arg, function.params[i])
+		if err != nil {
+			if arg.GetType() == nil {
+				return t, tc.propagateOrWrapError(err, expr, "failed to type check %s func arg at %d idx: %s", function.name, i, err.Error())
+			}
+
+			return t, fmt.Errorf("invalid argument at %d idx to %s. expected %s, got %s", i, function.name, function.params[i], arg.GetType())
+		}
+
+		expr.Arguments[i] = value
+	}
+
+	return function.returnType, nil
+}
+
+// infers the type arguments of a call to a generic function from the types of the arguments and checks them
+// against the constraints of the type parameters. calls with concrete type arguments instantiate the function
+func (tc *TypeChecker) checkGenericFunctionCall(expr *ast.CallExpression, function *functionInfo) (t cotypes.Type, err error) {
+	if len(expr.Arguments) != len(function.params) {
+		return t, fmt.Errorf("wrong number of arguments to %s. expected %d arguments, got %d arguments", function.name, len(function.params), len(expr.Arguments))
+	}
+
+	typeArgs := make(map[string]cotypes.Type)
+	for i, arg := range expr.Arguments {
+		if !containsTypeParam(function.params[i]) {
+			continue
+		}
+
+		argType, err := tc.checkExpression(arg)
+		if err != nil {
+			return t, tc.propagateOrWrapError(err, expr, "failed to type check %s func arg at %d idx: %s", function.name, i, err.Error())
+		}
+
+		if !inferTypeArguments(function.params[i], argType, typeArgs) {
+			if typeParam, ok := function.params[i].(cotypes.TypeParamType); ok {
+				return t, fmt.Errorf("conflicting types %s and %s for type parameter %s of function %s", typeArgs[typeParam.Name], argType, typeParam.Name, function.name)
+			}
+
+			return t, fmt.Errorf("invalid argument at %d idx to %s. expected %s, got %s", i, function.name, function.params[i], argType)
+		}
+	}
+
+	for _, typeParam := range function.typeParams {
+		typeArg, ok := typeArgs[typeParam.Name]
+		if !ok {
+			return t, fmt.Errorf("cannot infer type parameter %s of function %s", typeParam.Name, function.name)
+		}
+
+		if !typeParam.Constraint.SatisfiedBy(typeArg) {
+			return t, fmt.Errorf("type %s does not satisfy constraint %s of type parameter %s of function %s", typeArg, typeParam.Constraint, typeParam.Name, function.name)
+		}
+
+		expr.TypeArguments = append(expr.TypeArguments, typeArg)
+	}
+
+	// arguments are converted to the parameter types once the type arguments are known. the ones which
+	// were already checked during inference only need converting if they do not match exactly
+	for i, arg := range expr.Arguments {
+		paramType := substituteTypeParams(function.params[i], typeArgs)
+		if arg.GetType() != nil && arg.GetType().Equals(paramType) {
+			continue
+		}
+
+		value, err := tc.coerceExpression(arg, paramType)
+		if err != nil {
+			if arg.GetType() == nil {
+				return t, tc.propagateOrWrapError(err, expr, "failed to type check %s func arg at %d idx: %s", function.name, i, err.Error())
+			}
+
+			return t, fmt.Errorf("invalid argument at %d idx to %s. expected %s, got %s", i, function.name, paramType, arg.GetType())
+		}
+
+		expr.Arguments[i] = value
+	}
+
+	// calls within the body of a generic function may pass along its own type parameters, those are
+	// instantiated once the enclosing function is
+	if !slices.ContainsFunc(expr.TypeArguments, containsTypeParam) {
+		if err := tc.instantiate(function, expr.TypeArguments, typeArgs); err != nil {
+			return t, err
+		}
+	}
+
+	return substituteTypeParams(function.returnType, typeArgs), nil
+}
+
+// creates the instance of a generic function for the type arguments, unless it already exists. the signature of
+// the instance is declared right away, so that it can call itself, while its body is checked after the program
+func (tc *TypeChecker) instantiate(function *functionInfo, typeArgs []cotypes.Type, bindings map[string]cotypes.Type) error {
+	name := ast.InstanceName(function.name, typeArgs)
+	if _, exists := tc.functions[name]; exists {
+		return nil
+	}
+
+	if len(function.decl.Instances) >= maxInstances {
+		return fmt.Errorf("too many instances of generic function %s", function.name)
+	}
+
+	if slices.ContainsFunc(typeArgs, func(t cotypes.Type) bool { return typeSize(t) > maxTypeArgumentSize }) {
+		return fmt.Errorf("type arguments of generic function %s grow too large", function.name)
+	}
+
+	instance := ast.Clone(function.generic)
+	instance.Identifier.Literal = name
+	instance.TypeParameters = nil
+
+	previousTypeParams := tc.typeParams
+	tc.typeParams = bindings
+	defer func() {
+		tc.typeParams = previousTypeParams
+	}()
+
+	if err := tc.declareFunction(instance); err != nil {
+		return err
+	}
+
+	tc.functions[name].typeArgs = bindings
+	function.decl.Instances = append(function.decl.Instances, instance)
+	tc.pendingInstances = append(tc.pendingInstances, instance)
+	return nil
+}
+
+func (tc *TypeChecker) checkIfCondition(expr *ast.IfExpression) error {
+	conditionType, err := tc.checkExpression(expr.Condition)
+	if err != nil {
+		return tc.propagateOrWrapError(err, expr, "failed to type check if branch condition expression: %s", err.Error())
+	}
+
+	if expr.Binding != nil {
+		optionalType, ok := conditionType.(cotypes.OptionalType)
+		if !ok {
+			return tc.addErrorAtNode(expr, "if let requires an optional value, got %s", conditionType)
+		}
+
+		expr.Binding.SetType(optionalType.Inner)
+		return nil
+	}
+
+	if !conditionType.Equals(cotypes.BoolType{}) {
+		if _, ok := conditionType.(cotypes.OptionalType); ok {
+			return tc.addErrorAtNode(expr, "non-boolean condition in if expression, %s", describeType(conditionType))
+		}
+
+		return tc.addErrorAtNode(expr, "non-boolean condition in if expression")
+	}
+
+	return nil
+}
+
+// enters the consequence of an if expression, in which the binding of an if let expression is declared and the
+// variables narrowed by the condition hold a value. the returned function leaves it again
+func (tc *TypeChecker) enterConsequence(expr *ast.IfExpression, whenTrue []*ast.IdentifierExpression) (leave func() map[*ast.IdentifierExpression]bool) {
+	tc.env = env.NewEnvironmentWithParent(tc.env)
+	if expr.Binding != nil && expr.Binding.GetType() != nil {
+		tc.env.Set(expr.Binding.String(), symbol{
+			typ:  expr.Binding.GetType(),
+			decl: expr.Binding,
+		})
+	}
+
+	leaveNarrowing := tc.narrow(whenTrue)
+	return func() map[*ast.IdentifierExpression]bool {
+		tc.env = tc.env.Parent()
+		return leaveNarrowing()
+	}
+}
+
+// if expressions whose value is not used, none of the branches need to produce a value
+func (tc *TypeChecker) checkIfStatement(expr *ast.IfExpression) error {
+	err := tc.checkIfCondition(expr)
+
+	var whenTrue, whenFalse []*ast.IdentifierExpression
+	if err == nil && expr.Binding == nil {
+		whenTrue, whenFalse = tc.narrowingsOf(expr.Condition)
+	}
+
+	leave := tc.enterConsequence(expr, whenTrue)
+	tc.checkStatement(expr.Consequence)
+	consequenceNarrowed := leave()
+
+	leave = tc.narrow(whenFalse)
+	if expr.Alternative != nil {
+		tc.checkStatement(expr.Alternative)
+	}
+	alternativeNarrowed := leave()
+
+	if err != nil {
+		return err
+	}
+
+	// if one of the branches always exits, the code after the if statement is only reached through the other
+	// one, so whatever the condition proves for that branch holds afterwards as long as it was not reassigned
+	consequenceExits := alwaysExits(expr.Consequence)
+	alternativeExits := expr.Alternative != nil && alwaysExits(expr.Alternative)
+	switch {
+	case consequenceExits && !alternativeExits:
+		for _, decl := range whenFalse {
+			if alternativeNarrowed[decl] {
+				tc.narrowed[decl] = true
+			}
+		}
+	case alternativeExits && !consequenceExits:
+		for _, decl := range whenTrue {
+			if consequenceNarrowed[decl] {
+				tc.narrowed[decl] = true
+			}
+		}
+	}
+
+	expr.SetType(cotypes.VoidType{})
+	return nil
+}
+
+// the value of an if expression is the value of the last expression of the branch which is taken,
+// so both branches are required and their values must be of the same type. if the expected type is known,
+// the value of each branch is converted into it instead
+func (tc *TypeChecker) checkIfExpression(expr *ast.IfExpression, expected cotypes.Type) (t cotypes.Type, err error) {
+	if err := tc.checkIfCondition(expr); err != nil {
+		return t, err
+	}
+
+	if expr.Alternative == nil {
+		return t, fmt.Errorf("if expression must have an else branch to produce a value")
+	}
+
+	var whenTrue, whenFalse []*ast.IdentifierExpression
+	if expr.Binding == nil {
+		whenTrue, whenFalse = tc.narrowingsOf(expr.Condition)
+	}
+
+	leave := tc.enterConsequence(expr, whenTrue)
+	consequenceType, consequenceExits, err := tc.checkBranchBlock(expr.Consequence, "if expression branch", expected)
+	leave()
+	if err != nil {
+		return t, tc.propagateOrWrapError(err, expr, "failed to type check if expression branch: %s", err.Error())
+	}
+
+	leave = tc.narrow(whenFalse)
+	alternativeType, alternativeExits, err := tc.checkBranchBlock(expr.Alternative, "if expression branch", expected)
+	leave()
+	if err != nil {
+		return t, tc.propagateOrWrapError(err, expr, "failed to type check else expression branch: %s", err.Error())
+	}
+
+	// branches which always return, break or continue do not produce a value, so the value comes from the other branch
+	switch {
+	case consequenceExits && alternativeExits:
+		return t, fmt.Errorf("if expression does not produce a value, both of its branches exit")
+	case consequenceExits:
+		return alternativeType, nil
+	case alternativeExits:
+		return consequenceType, nil
+	}
+
+	return tc.unifyValues([]*ast.Expression{branchValue(expr.Consequence), branchValue(expr.Alternative)}, "if expression branches")
+}
+
+// type checks a branch of an if expression and returns the type of its last expression. branches which
+// always return, break or continue do not need to end with an expression, exits is set for them instead
+// kind describes the block in error messages, eg. if expression branch. the last expression is converted into the
+// expected type, unless it is nil
+func (tc *TypeChecker) checkBranchBlock(block *ast.BlockStatement, kind string, expected cotypes.Type) (t cotypes.Type, exits bool, err error) {
+	tc.env = env.NewEnvironmentWithParent(tc.env)
+	leave := tc.narrow(nil)
+	defer func() {
+		leave()
+		tc.env = tc.env.Parent()
+	}()
+
+	if len(block.Statements) == 0 {
+		return t, false, fmt.Errorf("%s must end with an expression", kind)
+	}
+
+	for _, stmt := range block.Statements[:len(block.Statements)-1] {
+		tc.checkStatement(stmt)
+	}
+
+	last, ok := block.Statements[len(block.Statements)-1].(*ast.ExpressionStatement)
+	if !ok {
+		tc.checkStatement(block.Statements[len(block.Statements)-1])
+
+		if alwaysExits(block) {
+			return t, true, nil
+		}
+
+		return t, false, fmt.Errorf("%s must end with an expression", kind)
+	}
+
+	if expected != nil {
+		value, err := tc.coerceExpression(last.Expr, expected)
+		if err != nil {
+			if last.Expr.GetType() == nil {
+				return t, false, err
+			}
+
+			if last.Expr.GetType().Equals(cotypes.VoidType{}) {
+				return t, false, fmt.Errorf("%s does not produce a value", kind)
+			}
+
+			return t, false, fmt.Errorf("cannot use %s as value of %s of type %s", last.Expr.GetType(), kind, expected)
+		}
+
+		last.Expr = value
+		return expected, false, nil
+	}
+
+	t, err = tc.checkExpression(last.Expr)
+	if err != nil {
+		return t, false, err
+	}
+
+	if t.Equals(cotypes.VoidType{}) {
+		return t, false, fmt.Errorf("%s does not produce a value", kind)
+	}
+
+	return t, false, nil
+}
+
+// element type is inferred from the elements, which are converted to the same type, eg. ints are widened if any of
+// the elements is a float
+func (tc *TypeChecker) checkArrayExpression(expr *ast.ArrayExpression) (t cotypes.Type, err error) {
+	if len(expr.Elements) == 0 {
+		return t, fmt.Errorf("cannot infer element type of empty array literal")
+	}
+
+	elements := []*ast.Expression{}
+	for i, element := range expr.Elements {
+		typ, err := tc.checkExpression(element)
+		if err != nil {
+			return t, tc.propagateOrWrapError(err, expr, "failed to type check array element at %d idx: %s", i, err.Error())
+		}
+
+		if typ.Equals(cotypes.VoidType{}) {
+			return t, fmt.Errorf("invalid array element at %d idx: void value", i)
+		}
+
+		elements = append(elements, &expr.Elements[i])
+	}
+
+	elementType, err := tc.unifyValues(elements, "array elements")
+	if err != nil {
+		return t, err
+	}
+
+	return cotypes.ArrayType{Element: elementType, Size: int64(len(expr.Elements))}, nil
+}
+
+func (tc *TypeChecker) checkTupleExpression(expr *ast.TupleExpression) (t cotypes.Type, err error) {
+	elementTypes := []cotypes.Type{}
+	for i, element := range expr.Elements {
+		typ, err := tc.checkExpression(element)
+		if err != nil {
+			return t, tc.propagateOrWrapError(err, expr, "failed to type check tuple element at %d idx: %s", i, err.Error())
+		}
+
+		if typ.Equals(cotypes.VoidType{}) {
+			return t, fmt.Errorf("invalid tuple element at %d idx: void value", i)
+		}
+
+		if typ.Equals(cotypes.NoneType{}) {
+			return t, fmt.Errorf("cannot infer type of tuple element at %d idx from none", i)
+		}
+
+		elementTypes = append(elementTypes, typ)
+	}
+
+	return cotypes.TupleType{Elements: elementTypes}, nil
+}
+
+func (tc *TypeChecker) checkTupleLiteral(expr *ast.TupleExpression, tupleType cotypes.TupleType) (ast.Expression, error) {
+	for i, element := range expr.Elements {
+		value, err := tc.coerceExpression(element, tupleType.Elements[i])
+		if err != nil {
+			if element.GetType() == nil {
+				return nil, err
+			}
+
+			return nil, tc.addErrorAtNode(expr, "cannot use %s as element %d of %s", element.GetType(), i, tupleType)
+		}
+
+		expr.Elements[i] = value
+	}
+
+	expr.SetType(tupleType)
+	return expr, nil
+}
+
+func (tc *TypeChecker) checkArrayLiteral(expr *ast.ArrayExpression, arrayType cotypes.ArrayType) (ast.Expression, error) {
+	for i, element := range expr.Elements {
+		value, err := tc.coerceExpression(element, arrayType.Element)
+		if err != nil {
+			if element.GetType() == nil {
+				return nil, err
+			}
+
+			return nil, tc.addErrorAtNode(expr, "cannot use %s as element of %s", element.GetType(), arrayType)
+		}
+
+		expr.Elements[i] = value
+	}
+
+	expr.SetType(arrayType)
+	return expr, nil
+}
+
+func (tc *TypeChecker) checkListLiteral(expr *ast.ArrayExpression, listType cotypes.ListType) (ast.Expression, error) {
+	for i, element := range expr.Elements {
+		value, err := tc.coerceExpression(element, listType.Element)
+		if err != nil {
+			if element.GetType() == nil {
+				return nil, err
+			}
+
+			return nil, tc.addErrorAtNode(expr, "cannot use %s as element of %s", element.GetType(), listType)
+		}
+
+		expr.Elements[i] = value
+	}
+
+	expr.SetType(listType)
+	return expr, nil
+}
+
+// key type is inferred from the first entry, values are converted to the same type like array elements
+func (tc *TypeChecker) checkMapExpression(expr *ast.MapExpression) (t cotypes.Type, err error) {
+	if len(expr.Keys) == 0 {
+		return t, fmt.Errorf("cannot infer key and value types of empty map literal")
+	}
+
+	var keyType cotypes.Type
+	values := []*ast.Expression{}
+	for i := range expr.Keys {
+		typ, err := tc.checkExpression(expr.Keys[i])
+		if err != nil {
+			return t, tc.propagateOrWrapError(err, expr, "failed to type check map key at %d idx: %s", i, err.Error())
+		}
+
+		if keyType == nil {
+			if !cotypes.IsHashable(typ) {
+				return t, fmt.Errorf("type %s cannot be used as map key", typ)
+			}
+
+			keyType = typ
+		} else if !keyType.Equals(typ) {
+			return t, fmt.Errorf("mismatched types of map keys: %s and %s", keyType, typ)
+		}
+
+		typ, err = tc.checkExpression(expr.Values[i])
+		if err != nil {
+			return t, tc.propagateOrWrapError(err, expr, "failed to type check map value at %d idx: %s", i, err.Error())
+		}
+
+		if typ.Equals(cotypes.VoidType{}) {
+			return t, fmt.Errorf("invalid map value at %d idx: void value", i)
+		}
+
+		values = append(values, &expr.Values[i])
+	}
+
+	valueType, err := tc.unifyValues(values, "map values")
+	if err != nil {
+		return t, err
+	}
+
+	return cotypes.MapType{Key: keyType, Value: valueType}, nil
+}
+
+func (tc *TypeChecker) checkMapLiteral(expr *ast.MapExpression, mapType cotypes.MapType) (ast.Expression, error) {
+	for i := range expr.Keys {
+		key, err := tc.coerceExpression(expr.Keys[i], mapType.Key)
+		if err != nil {
+			if expr.Keys[i].GetType() == nil {
+				return nil, err
+			}
+
+			return nil, tc.addErrorAtNode(expr, "cannot use %s as key of %s", expr.Keys[i].GetType(), mapType)
+		}
+		expr.Keys[i] = key
+
+		value, err := tc.coerceExpression(expr.Values[i], mapType.Value)
+		if err != nil {
+			if expr.Values[i].GetType() == nil {
+				return nil, err
+			}
+
+			return nil, tc.addErrorAtNode(expr, "cannot use %s as value of %s", expr.Values[i].GetType(), mapType)
+		}
+		expr.Values[i] = value
+	}
+
+	expr.SetType(mapType)
+	return expr, nil
+}
+
+func (tc *TypeChecker) checkIndexExpression(expr *ast.IndexExpression) (t cotypes.Type, err error) {
+	leftType, err := tc.checkExpression(expr.Left)
+	if err != nil {
+		return t, tc.propagateOrWrapError(err, expr, "failed to type check indexed expression: %s", err.Error())
+	}
+
+	indexType, err := tc.checkExpression(expr.Index)
+	if err != nil {
+		return t, tc.propagateOrWrapError(err, expr, "failed to type check index: %s", err.Error())
+	}
+
+	switch lt := leftType.(type) {
+	case cotypes.ArrayType:
+		if !cotypes.IsInteger(indexType) {
+			return t, fmt.Errorf("array index must be of an integer type, got %s", indexType)
+		}
+
+		// constant indices are checked at compile time, the rest at runtime
+		if index, ok := tc.foldIndex(expr.Index); ok && (index < 0 || index >= lt.Size) {
+			return t, fmt.Errorf("index %d out of bounds for array of length %d", index, lt.Size)
+		}
+
+		return lt.Element, nil
+	case cotypes.ListType:
+		if !cotypes.IsInteger(indexType) {
+			return t, fmt.Errorf("list index must be of an integer type, got %s", indexType)
+		}
+
+		if index, ok := tc.foldIndex(expr.Index); ok && index < 0 {
+			return t, fmt.Errorf("negative index %d into list", index)
+		}
+
+		return lt.Element, nil
+	case cotypes.StringType:
+		if !cotypes.IsInteger(indexType) {
+			return t, fmt.Errorf("string index must be of an integer type, got %s", indexType)
+		}
+
+		if index, ok := tc.foldIndex(expr.Index); ok && index < 0 {
+			return t, fmt.Errorf("negative index %d into string", index)
+		}
+
+		// strings are indexed by byte offset, the char is decoded from the bytes starting at the index
+		return cotypes.CharType{}, nil
+	default:
+		return t, fmt.Errorf("cannot index into value of type %s", describeType(leftType))
+	}
+}
+
+func (tc *TypeChecker) checkIndexAssignmentStatement(stmt *ast.IndexAssignmentStatement) error {
+	_, isCompound := stmt.CompoundOperator()
+	value, err := tc.checkTargetAssignment(stmt, stmt.Target, stmt.Operator, isCompound, stmt.Value, "element")
+	if err != nil {
+		return err
+	}
+
+	stmt.Value = value
+	return nil
+}
+
+func (tc *TypeChecker) checkFieldAssignmentStatement(stmt *ast.FieldAssignmentStatement) error {
+	_, isCompound := stmt.CompoundOperator()
+	value, err := tc.checkTargetAssignment(stmt, stmt.Target, stmt.Operator, isCompound, stmt.Value, "field "+stmt.Target.Field.String())
+	if err != nil {
+		return err
+	}
+
+	stmt.Value = value
+	return nil
+}
+
+// type checks assignment to an element or field of a variable, returns the value converted into the type of the target.
+// what is the kind of the target (eg. element), used in error messages
+func (tc *TypeChecker) checkTargetAssignment(stmt ast.Statement, target ast.Expression, operator tokens.Token, isCompound bool, value ast.Expression, what string) (ast.Expression, error) {
+	root := assignmentRoot(target)
+	if root == nil {
+		return nil, tc.addErrorAtNode(stmt, "cannot assign to %s, it is not a variable", target)
+	}
+
+	if sym, exists := tc.env.Get(root.String()); exists && sym.isConst {
+		return nil, tc.addErrorAtNode(stmt, "cannot assign to constant: %s", root)
+	}
+
+	targetType, err := tc.checkExpression(target)
+	if err != nil {
+		return nil, err
+	}
+
+	if index, ok := target.(*ast.IndexExpression); ok && index.Left.GetType().Equals(cotypes.StringType{}) {
+		return nil, tc.addErrorAtNode(stmt, "cannot assign to char of string %s, strings are immutable", index.Left)
+	}
+
+	if isCompound && !isNumeric(targetType) {
+		return nil, tc.addErrorAtNode(stmt, "cannot perform %s operation on %s", operator.Literal, targetType)
+	}
+
+	newValue, err := tc.coerceExpression(value, targetType)
+	if err != nil {
+		if value.GetType() == nil {
+			return nil, err
+		}
+
+		if isCompound {
+			return nil, tc.addErrorAtNode(stmt, "cannot perform %s operation on %s and %s", operator.Literal, targetType, value.GetType())
+		}
+
+		return nil, tc.addErrorAtNode(stmt, "cannot assign %s to %s of type %s", value.GetType(), what, targetType)
+	}
+
+	return newValue, nil
+}
+
+// registers the name of a top level struct, so that structs can refer to each other regardless of the order
+// they are declared in. fields are resolved afterwards by defineStruct
+func (tc *TypeChecker) declareStruct(stmt *ast.StructStatement) error {
+	structName := stmt.Identifier.String()
+	if _, err := tc.resolveTypeAnnotation(&ast.NamedTypeAnnotation{Name: structName}); err == nil {
+		return tc.addErrorAtNode(stmt, "cannot redeclare type: %s", structName)
+	}
+
+	tc.structs[structName] = &cotypes.StructType{Name: structName}
+	return nil
+}
+
+func (tc *TypeChecker) defineStruct(stmt *ast.StructStatement) error {
+	structName := stmt.Identifier.String()
+	structType := tc.structs[structName]
+
+	fields := []cotypes.StructField{}
+	for _, field := range stmt.Fields {
+		fieldName := field.Identifier.String()
+		if slices.ContainsFunc(fields, func(f cotypes.StructField) bool { return f.Name == fieldName }) {
+			return tc.addErrorAtNode(stmt, "duplicate field %s in struct %s", fieldName, structName)
+		}
+
+		fieldType, err := tc.resolveTypeAnnotation(field.Annotation)
+		if err != nil {
+			return tc.propagateOrWrapError(err, stmt, "failed to resolve type of field %s: %s", fieldName, err.Error())
+		}
+
+		if fieldType.Equals(cotypes.VoidType{}) {
+			return tc.addErrorAtNode(stmt, "field %s of struct %s cannot be of type void", fieldName, structName)
+		}
+
+		fields = append(fields, cotypes.StructField{Name: fieldName, Type: fieldType})
+	}
+
+	structType.Fields = fields
+	return nil
+}
+
+func (tc *TypeChecker) checkStructStatement(stmt *ast.StructStatement) error {
+	structName := stmt.Identifier.String()
+
+	if tc.currentFunction != nil || tc.env.Parent() != nil {
+		return tc.addErrorAtNode(stmt, "struct %s must be declared at top level", structName)
+	}
+
+	// a struct holding itself by value would have infinite size, lists and maps hold their elements out of line
+	if structType, ok := tc.structs[structName]; ok {
+		visited := map[string]bool{structName: true}
+		for _, field := range structType.Fields {
+			if embedsType(field.Type, structName, visited) {
+				return tc.addErrorAtNode(stmt, "struct %s cannot contain itself", structName)
+			}
+		}
+	}
+
+	return nil
+}
+
+// registers the name of a top level enum, payloads are resolved afterwards by defineEnum
+func (tc *TypeChecker) declareEnum(stmt *ast.EnumStatement) error {
+	enumName := stmt.Identifier.String()
+	if _, err := tc.resolveTypeAnnotation(&ast.NamedTypeAnnotation{Name: enumName}); err == nil {
+		return tc.addErrorAtNode(stmt, "cannot redeclare type: %s", enumName)
+	}
+
+	tc.enums[enumName] = &cotypes.EnumType{Name: enumName}
+	return nil
+}
+
+func (tc *TypeChecker) defineEnum(stmt *ast.EnumStatement) error {
+	enumName := stmt.Identifier.String()
+	enumType := tc.enums[enumName]
+
+	variants := []cotypes.EnumVariant{}
+	for _, variant := range stmt.Variants {
+		variantName := variant.Identifier.String()
+		if slices.ContainsFunc(variants, func(v cotypes.EnumVariant) bool { return v.Name == variantName }) {
+			return tc.addErrorAtNode(stmt, "duplicate variant %s in enum %s", variantName, enumName)
+		}
+
+		payload := []cotypes.Type{}
+		for _, annotation := range variant.Payload {
+			payloadType, err := tc.resolveTypeAnnotation(annotation)
+			if err != nil {
+				return tc.propagateOrWrapError(err, stmt, "failed to resolve payload type of variant %s: %s", variantName, err.Error())
+			}
+
+			if payloadType.Equals(cotypes.VoidType{}) {
+				return tc.addErrorAtNode(stmt, "payload of variant %s cannot be of type void", variantName)
+			}
+
+			payload = append(payload, payloadType)
+		}
+
+		variants = append(variants, cotypes.EnumVariant{Name: variantName, Payload: payload})
+	}
+
+	enumType.Variants = variants
+	return nil
+}
+
+func (tc *TypeChecker) checkEnumStatement(stmt *ast.EnumStatement) error {
+	enumName := stmt.Identifier.String()
+
+	if tc.currentFunction != nil || tc.env.Parent() != nil {
+		return tc.addErrorAtNode(stmt, "enum %s must be declared at top level", enumName)
+	}
+
+	if enumType, ok := tc.enums[enumName]; ok {
+		visited := map[string]bool{enumName: true}
+		for _, variant := range enumType.Variants {
+			for _, payloadType := range variant.Payload {
+				if embedsType(payloadType, enumName, visited) {
+					return tc.addErrorAtNode(stmt, "enum %s cannot contain itself", enumName)
+				}
+			}
+		}
+	}
+
+	return nil
+}
+
+func (tc *TypeChecker) checkVariantExpression(expr *ast.VariantExpression) (t cotypes.Type, err error) {
+	enumType, ok := tc.enums[expr.Enum.String()]
+	if !ok {
+		return t, fmt.Errorf("unknown enum type: %s", expr.Enum)
+	}
+
+	variant, _, ok := enumType.Variant(expr.Variant.String())
+	if !ok {
+		return t, fmt.Errorf("enum %s has no variant %s", enumType.Name, expr.Variant)
+	}
+
+	if len(expr.Arguments) != len(variant.Payload) {
+		return t, fmt.Errorf("wrong number of values for variant %s::%s. expected %d values, got %d values", enumType.Name, variant.Name, len(variant.Payload), len(expr.Arguments))
+	}
+
+	for i, arg := range expr.Arguments {
+		value, err := tc.coerceExpression(arg, variant.Payload[i])
+		if err != nil {
+			if arg.GetType() == nil {
+				return t, tc.propagateOrWrapError(err, expr, "failed to type check value at %d idx of variant %s: %s", i, variant.Name, err.Error())
+			}
+
+			return t, fmt.Errorf("invalid value at %d idx to variant %s::%s. expected %s, got %s", i, enumType.Name, variant.Name, variant.Payload[i], arg.GetType())
+		}
+
+		expr.Arguments[i] = value
+	}
+
+	return enumType, nil
+}
+
+// type checks the subject and the patterns of the arms of a match expression, and checks that every variant is matched.
+// checkBody is called for the body of every arm, with the bindings of the arm in scope
+func (tc *TypeChecker) checkMatchArms(expr *ast.MatchExpression, checkBody func(arm *ast.MatchArm) error) error {
+	subjectType, err := tc.checkExpression(expr.Subject)
+	if err != nil {
+		return tc.propagateOrWrapError(err, expr, "failed to type check subject of match expression: %s", err.Error())
+	}
+
+	enumType, ok := cotypes.AsEnum(subjectType)
+	if !ok {
+		return fmt.Errorf("cannot match on value of type %s", describeType(subjectType))
+	}
+
+	matched := map[string]bool{}
+	hasWildcard := false
+	for i, arm := range expr.Arms {
+		if hasWildcard {
+			return fmt.Errorf("unreachable match arm %s, it comes after the wildcard arm", arm.Variant)
+		}
+
+		var payload []cotypes.Type
+		if arm.IsWildcard() {
+			if len(arm.Bindings) > 0 {
+				return fmt.Errorf("wildcard match arm cannot have bindings")
+			}
+
+			hasWildcard = true
+		} else {
+			variant, _, ok := enumType.Variant(arm.Variant.String())
+			if !ok {
+				return fmt.Errorf("enum %s has no variant %s", enumType.Name, arm.Variant)
+			}
+
+			if matched[variant.Name] {
+				return fmt.Errorf("duplicate match arm for variant %s", variant.Name)
+			}
+			matched[variant.Name] = true
+
+			if len(arm.Bindings) != len(variant.Payload) {
+				return fmt.Errorf("wrong number of bindings for variant %s. expected %d bindings, got %d bindings", variant.Name, len(variant.Payload), len(arm.Bindings))
+			}
+
+			payload = variant.Payload
+		}
+
+		// bindings are scoped to the arm, _ ignores the value
+		tc.env = env.NewEnvironmentWithParent(tc.env)
+		for j, binding := range arm.Bindings {
+			binding.SetType(payload[j])
+			if binding.Literal == "_" {
+				continue
+			}
+
+			if tc.env.Has(binding.Literal) {
+				tc.env = tc.env.Parent()
+				return fmt.Errorf("duplicate binding %s in match arm at %d idx", binding, i)
+			}
+
+			tc.env.Set(binding.Literal, symbol{
+				typ:  payload[j],
+				decl: binding,
+			})
+		}
+
+		err := checkBody(arm)
+		tc.env = tc.env.Parent()
+		if err != nil {
+			return err
+		}
+	}
+
+	if !hasWildcard {
+		missing := []string{}
+		for _, variant := range enumType.Variants {
+			if !matched[variant.Name] {
+				missing = append(missing, variant.Name)
+			}
+		}
+
+		if len(missing) > 0 {
+			return fmt.Errorf("non-exhaustive match on %s, missing variants: %s", enumType.Name, strings.Join(missing, ", "))
+		}
+	}
+
+	return nil
+}
+
+// match expressions whose value is not used, none of the arms need to produce a value
+func (tc *TypeChecker) checkMatchStatement(expr *ast.MatchExpression) error {
+	err := tc.checkMatchArms(expr, func(arm *ast.MatchArm) error {
+		tc.checkStatement(arm.Body)
+		return nil
+	})
+	if err != nil {
+		return tc.propagateOrWrapError(err, expr, "%s", err.Error())
+	}
+
+	expr.SetType(cotypes.VoidType{})
+	return nil
+}
+
+// the value of a match expression is the value of the last expression of the arm which is taken,
+// so the values of all of the arms must be of the same type, or of the expected type if it is known
+func (tc *TypeChecker) checkMatchExpression(expr *ast.MatchExpression, expected cotypes.Type) (t cotypes.Type, err error) {
+	armTypes := map[*ast.MatchArm]cotypes.Type{}
+	err = tc.checkMatchArms(expr, func(arm *ast.MatchArm) error {
+		armType, exits, err := tc.checkBranchBlock(arm.Body, "match arm", expected)
+		if err != nil {
+			return tc.propagateOrWrapError(err, expr, "failed to type check match arm %s: %s", arm.Variant, err.Error())
+		}
+
+		// arms which always exit do not produce a value, so the value comes from the other arms
+		if !exits {
+			armTypes[arm] = armType
+		}
+
+		return nil
+	})
+	if err != nil {
+		return t, err
+	}
+
+	if len(armTypes) == 0 {
+		return t, fmt.Errorf("match expression does not produce a value, all of its arms exit")
+	}
+
+	values := []*ast.Expression{}
+	for _, arm := range expr.Arms {
+		if _, ok := armTypes[arm]; ok {
+			values = append(values, branchValue(arm.Body))
+		}
+	}
+
+	return tc.unifyValues(values, "match arms")
+}
+
+func (tc *TypeChecker) checkStructExpression(expr *ast.StructExpression) (t cotypes.Type, err error) {
+	structName := expr.Name.String()
+	structType, ok := tc.structs[structName]
+	if !ok {
+		return t, fmt.Errorf("unknown struct type: %s", structName)
+	}
+
+	seen := map[string]bool{}
+	for _, field := range expr.Fields {
+		fieldName := field.Identifier.String()
+		structField, _, ok := structType.Field(fieldName)
+		if !ok {
+			return t, fmt.Errorf("struct %s has no field %s", structName, fieldName)
+		}
+
+		if seen[fieldName] {
+			return t, fmt.Errorf("duplicate field %s in struct literal", fieldName)
+		}
+		seen[fieldName] = true
+
+		value, err := tc.coerceExpression(field.Value, structField.Type)
+		if err != nil {
+			if field.Value.GetType() == nil {
+				return t, tc.propagateOrWrapError(err, expr, "failed to type check field %s: %s", fieldName, err.Error())
+			}
+
+			return t, fmt.Errorf("cannot use %s as field %s of type %s", field.Value.GetType(), fieldName, structField.Type)
+		}
+
+		field.Value = value
+	}
+
+	for _, structField := range structType.Fields {
+		if !seen[structField.Name] {
+			return t, fmt.Errorf("missing field %s in struct literal of %s", structField.Name, structName)
+		}
+	}
+
+	return structType, nil
+}
+
+func (tc *TypeChecker) checkFieldExpression(expr *ast.FieldExpression) (t cotypes.Type, err error) {
+	leftType, err := tc.checkExpression(expr.Left)
+	if err != nil {
+		return t, tc.propagateOrWrapError(err, expr, "failed to type check accessed expression: %s", err.Error())
+	}
+
+	if tupleType, ok := leftType.(cotypes.TupleType); ok {
+		elementType, _, ok := tupleType.Element(expr.Field.String())
+		if !ok {
+			return t, fmt.Errorf("tuple %s has no element %s", tupleType, expr.Field)
+		}
+
+		return elementType, nil
+	}
+
+	structType, ok := leftType.(*cotypes.StructType)
+	if !ok {
+		return t, fmt.Errorf("cannot access field %s of value of type %s", expr.Field, describeType(leftType))
+	}
+
+	field, _, ok := structType.Field(expr.Field.String())
+	if !ok {
+		return t, fmt.Errorf("struct %s has no field %s", structType.Name, expr.Field)
+	}
+
+	return field.Type, nil
+}
+
+func (tc *TypeChecker) checkPrintBuiltin(expr *ast.CallExpression) (t cotypes.Type, err error) {
+	for i, arg := range expr.Arguments {
+		argType, err := tc.checkExpression(arg)
+		if err != nil {
+			return t, tc.propagateOrWrapError(err, expr, "failed to type check print func arg at %d idx: %s", i, err.Error())
+		}
+
+		arg.SetType(argType)
+
+		// the comparable types are exactly the ones which can be printed, which includes type parameters constrained to them
+		if !cotypes.ConstraintComparable.SatisfiedBy(argType) {
+			if _, ok := argType.(cotypes.OptionalType); ok {
+				return t, fmt.Errorf("invalid argument at %d idx to print, %s", i, describeType(argType))
+			}
+
+			return t, fmt.Errorf("invalid argument at %d idx to print", i)
+		}
+	}
+
+	return cotypes.VoidType{}, nil
+}
+
+func (tc *TypeChecker) checkExitBuiltin(expr *ast.CallExpression) (t cotypes.Type, err error) {
+	if len(expr.Arguments) != 1 {
+		return t, fmt.Errorf("too many arguments. expected one argument, got %d arguments", len(expr.Arguments))
+	}
+
+	exitCode, err := tc.checkExpression(expr.Arguments[0])
+	if err != nil {
+		return t, tc.propagateOrWrapError(err, expr, "failed to type check exit func arg: %s", err.Error())
+	}
+
+	if !exitCode.Equals(cotypes.IntType{}) {
+		return t, fmt.Errorf("expected exit code to be of type int, got %s", exitCode.String())
+	}
+
+	return cotypes.VoidType{}, nil
+}
+
+// explicit conversion between numeric types, eg. u8(x). integers are truncated or extended, floats are
+// rounded towards zero when converted to integers
+func (tc *TypeChecker) checkConversionBuiltin(expr *ast.CallExpression) (t cotypes.Type, err error) {
+	name := expr.Identifier.String()
+	if len(expr.Arguments) != 1 {
+		return t, fmt.Errorf("too many arguments. expected one argument, got %d arguments", len(expr.Arguments))
+	}
+
+	valType, err := tc.checkExpression(expr.Arguments[0])
+	if err != nil {
+		return t, tc.propagateOrWrapError(err, expr, "failed to type check %s func arg: %s", name, err.Error())
+	}
+
+	targetType, ok := cotypes.NumericType(name)
+	if !ok {
+		targetType = cotypes.CharType{}
+	}
+
+	// chars are converted to and from integers holding their code point
+	isChar := func(t cotypes.Type) bool { return t.Equals(cotypes.CharType{}) }
+	switch {
+	case isNumeric(valType) && isNumeric(targetType), isChar(valType) && (cotypes.IsInteger(targetType) || isChar(targetType)):
+	case cotypes.IsInteger(valType) && isChar(targetType):
+	default:
+		return t, fmt.Errorf("cannot convert %s to %s", valType, targetType)
+	}
+
+	// constants are converted at compile time, so they have to fit the type they are converted to, like they do
+	// when they are assigned. floats are truncated at run time instead
+	if value, err := tc.foldConstant(expr.Arguments[0]); err == nil && !cotypes.IsFloat(valType) {
+		if _, err := foldConversion(value, targetType); err != nil {
+			return t, err
+		}
+	}
+
+	return targetType, nil
+}
+
+func (tc *TypeChecker) checkLenBuiltin(expr *ast.CallExpression) (t cotypes.Type, err error) {
+	if len(expr.Arguments) != 1 {
+		return t, fmt.Errorf("wrong number of arguments to len. expected 1 argument, got %d arguments", len(expr.Arguments))
+	}
+
+	valType, err := tc.checkExpression(expr.Arguments[0])
+	if err != nil {
+		return t, tc.propagateOrWrapError(err, expr, "failed to type check len func arg: %s", err.Error())
+	}
+
+	switch valType.(type) {
+	case cotypes.ArrayType, cotypes.ListType, cotypes.MapType, cotypes.StringType:
+		return cotypes.IntType{}, nil
+	default:
+		return t, fmt.Errorf("cannot get length of %s", valType)
+	}
+}
+
+func (tc *TypeChecker) checkPushBuiltin(expr *ast.CallExpression) (t cotypes.Type, err error) {
+	if len(expr.Arguments) != 2 {
+		return t, fmt.Errorf("wrong number of arguments to push. expected 2 arguments, got %d arguments", len(expr.Arguments))
+	}
+
+	listType, err := tc.checkListArgument(expr, "push")
+	if err != nil {
+		return t, err
+	}
+
+	value, err := tc.coerceExpression(expr.Arguments[1], listType.Element)
+	if err != nil {
+		if expr.Arguments[1].GetType() == nil {
+			return t, tc.propagateOrWrapError(err, expr, "failed to type check push func arg: %s", err.Error())
+		}
+
+		return t, fmt.Errorf("cannot push %s to %s", expr.Arguments[1].GetType(), listType)
+	}
+	expr.Arguments[1] = value
+
+	return cotypes.VoidType{}, nil
+}
+
+func (tc *TypeChecker) checkPopBuiltin(expr *ast.CallExpression) (t cotypes.Type, err error) {
+	if len(expr.Arguments) != 1 {
+		return t, fmt.Errorf("wrong number of arguments to pop. expected 1 argument, got %d arguments", len(expr.Arguments))
+	}
+
+	listType, err := tc.checkListArgument(expr, "pop")
+	if err != nil {
+		return t, err
+	}
+
+	return listType.Element, nil
+}
+
+// type checks the first argument of a list builtin, which must be a list
+func (tc *TypeChecker) checkListArgument(expr *ast.CallExpression, funcName string) (t cotypes.ListType, err error) {
+	valType, err := tc.checkExpression(expr.Arguments[0])
+	if err != nil {
+		return t, tc.propagateOrWrapError(err, expr, "failed to type check %s func arg: %s", funcName, err.Error())
+	}
+
+	listType, ok := valType.(cotypes.ListType)
+	if !ok {
+		return t, fmt.Errorf("first argument of %s must be a list, got %s", funcName, valType)
+	}
+
+	return listType, nil
+}
+
+func (tc *TypeChecker) checkGetBuiltin(expr *ast.CallExpression) (t cotypes.Type, err error) {
+	if len(expr.Arguments) != 2 {
+		return t, fmt.Errorf("wrong number of arguments to get. expected 2 arguments, got %d arguments", len(expr.Arguments))
+	}
+
+	mapType, err := tc.checkMapArguments(expr, "get")
+	if err != nil {
+		return t, err
+	}
+
+	return mapType.Value, nil
+}
+
+func (tc *TypeChecker) checkSetBuiltin(expr *ast.CallExpression) (t cotypes.Type, err error) {
+	if len(expr.Arguments) != 3 {
+		return t, fmt.Errorf("wrong number of arguments to set. expected 3 arguments, got %d arguments", len(expr.Arguments))
+	}
+
+	mapType, err := tc.checkMapArguments(expr, "set")
+	if err != nil {
+		return t, err
+	}
+
+	value, err := tc.coerceExpression(expr.Arguments[2], mapType.Value)
+	if err != nil {
+		if expr.Arguments[2].GetType() == nil {
+			return t, tc.propagateOrWrapError(err, expr, "failed to type check set func arg: %s", err.Error())
+		}
+
+		return t, fmt.Errorf("cannot use %s as value of %s", expr.Arguments[2].GetType(), mapType)
+	}
+	expr.Arguments[2] = value
+
+	return cotypes.VoidType{}, nil
+}
+
+func (tc *TypeChecker) checkHasBuiltin(expr *ast.CallExpression) (t cotypes.Type, err error) {
+	if len(expr.Arguments) != 2 {
+		return t, fmt.Errorf("wrong number of arguments to has. expected 2 arguments, got %d arguments", len(expr.Arguments))
+	}
+
+	if _, err := tc.checkMapArguments(expr, "has"); err != nil {
+		return t, err
+	}
+
+	return cotypes.BoolType{}, nil
+}
+
+func (tc *TypeChecker) checkResultBuiltin(expr *ast.CallExpression) (t cotypes.Type, err error) {
+	return t, fmt.Errorf("cannot infer type of result created by %s, it can only be used where a Result type is expected", expr.Identifier)
+}
+
+// ok(<value>) and err(<error>) create a result of the type they are used as, see coerceExpression
+func (tc *TypeChecker) checkResultConstructor(expr *ast.CallExpression, resultType cotypes.ResultType) (ast.Expression, error) {
+	builtin := tc.builtins[expr.Identifier.String()]
+	expr.IsBuiltin = true
+	expr.BuiltinKind = &builtin.kind
+
+	if len(expr.Arguments) != 1 {
+		return nil, tc.addErrorAtNode(expr, "wrong number of arguments to %s. expected 1 arguments, got %d arguments", builtin.name, len(expr.Arguments))
+	}
+
+	payloadType, payloadKind := resultType.Value, "value"
+	if builtin.kind == ast.BuiltinFuncErr {
+		payloadType, payloadKind = resultType.Error, "error"
+	}
+
+	payload, err := tc.coerceExpression(expr.Arguments[0], payloadType)
+	if err != nil {
+		if expr.Arguments[0].GetType() == nil {
+			return nil, tc.propagateOrWrapError(err, expr, "failed to type check %s func arg: %s", builtin.name, err.Error())
+		}
+
+		return nil, tc.addErrorAtNode(expr, "cannot use %s as %s of %s", expr.Arguments[0].GetType(), payloadKind, resultType)
+	}
+
+	expr.Arguments[0] = payload
+	expr.SetType(resultType)
+	return expr, nil
+}
+
+// <result>? unwraps an ok result, an err result is returned from the enclosing function, whose return
+// type must therefore be a result with the same error type
+func (tc *TypeChecker) checkTryExpression(expr *ast.TryExpression) (t cotypes.Type, err error) {
+	operandType, err := tc.checkExpression(expr.Expr)
+	if err != nil {
+		return t, tc.propagateOrWrapError(err, expr, "failed to type check operand of ? operator: %s", err.Error())
+	}
+
+	resultType, ok := operandType.(cotypes.ResultType)
+	if !ok {
+		return t, fmt.Errorf("? operator requires a Result value, got %s", describeType(operandType))
+	}
+
+	if tc.currentFunction == nil {
+		return t, fmt.Errorf("? operator can only be used inside of a function")
+	}
+
+	functionResult, ok := tc.currentFunction.returnType.(cotypes.ResultType)
+	if !ok {
+		return t, fmt.Errorf("? operator can only be used in a function returning a Result, function %s returns %s", tc.currentFunction.name, tc.currentFunction.returnType)
+	}
+
+	if !resultType.Error.Equals(functionResult.Error) {
+		return t, fmt.Errorf("cannot propagate error of type %s from function %s with return type %s", resultType.Error, tc.currentFunction.name, functionResult)
+	}
+
+	expr.FunctionResult = functionResult
+	return resultType.Value, nil
+}
+
+func (tc *TypeChecker) checkDeleteBuiltin(expr *ast.CallExpression) (t cotypes.Type, err error) {
+	if len(expr.Arguments) != 2 {
+		return t, fmt.Errorf("wrong number of arguments to delete. expected 2 arguments, got %d arguments", len(expr.Arguments))
+	}
+
+	if _, err := tc.checkMapArguments(expr, "delete"); err != nil {
+		return t, err
+	}
+
+	return cotypes.VoidType{}, nil
+}
+
+// type checks the first two arguments of a map builtin, which must be a map and one of its keys
+func (tc *TypeChecker) checkMapArguments(expr *ast.CallExpression, funcName string) (t cotypes.MapType, err error) {
+	valType, err := tc.checkExpression(expr.Arguments[0])
+	if err != nil {
+		return t, tc.propagateOrWrapError(err, expr, "failed to type check %s func arg: %s", funcName, err.Error())
+	}
+
+	mapType, ok := valType.(cotypes.MapType)
+	if !ok {
+		return t, fmt.Errorf("first argument of %s must be a map, got %s", funcName, valType)
+	}
+
+	key, err := tc.coerceExpression(expr.Arguments[1], mapType.Key)
+	if err != nil {
+		if expr.Arguments[1].GetType() == nil {
+			return t, tc.propagateOrWrapError(err, expr, "failed to type check %s func arg: %s", funcName, err.Error())
+		}
+
+		return t, fmt.Errorf("cannot use %s as key of %s", expr.Arguments[1].GetType(), mapType)
+	}
+	expr.Arguments[1] = key
+
+	return mapType, nil
+}
+
+// narrows the optional variables until the returned function is called, which returns the variables that were
+// narrowed when leaving. variables which are assigned none in between stay unnarrowed afterwards as well
+func (tc *TypeChecker) narrow(decls []*ast.IdentifierExpression) (leave func() map[*ast.IdentifierExpression]bool) {
+	previous := tc.narrowed
+	tc.narrowed = maps.Clone(previous)
+	for _, decl := range decls {
+		tc.narrowed[decl] = true
+	}
+
+	return func() map[*ast.IdentifierExpression]bool {
+		current := tc.narrowed
+		maps.DeleteFunc(previous, func(decl *ast.IdentifierExpression, _ bool) bool { return !current[decl] })
+		tc.narrowed = previous
+		return current
+	}
+}
+
+// returns the optional variables which the condition proves to hold a value when it is true and when it is false
+func (tc *TypeChecker) narrowingsOf(condition ast.Expression) (whenTrue, whenFalse []*ast.IdentifierExpression) {
+	switch c := condition.(type) {
+	case *ast.GroupedExpression:
+		return tc.narrowingsOf(c.Expr)
+	case *ast.UnaryExpression:
+		if c.Token.Type == tokens.BANG {
+			whenTrue, whenFalse = tc.narrowingsOf(c.Expr)
+			return whenFalse, whenTrue
+		}
+	case *ast.BinaryExpression:
+		switch c.Operator.Type {
+		case tokens.AND:
+			leftTrue, _ := tc.narrowingsOf(c.Left)
+			rightTrue, _ := tc.narrowingsOf(c.Right)
+			return append(leftTrue, rightTrue...), nil
+		case tokens.OR:
+			_, leftFalse := tc.narrowingsOf(c.Left)
+			_, rightFalse := tc.narrowingsOf(c.Right)
+			return nil, append(leftFalse, rightFalse...)
+		case tokens.EQUALS, tokens.NOT_EQUALS:
+			decl := tc.comparedToNone(c)
+			if decl == nil {
+				return nil, nil
+			}
+
+			if c.Operator.Type == tokens.NOT_EQUALS {
+				return []*ast.IdentifierExpression{decl}, nil
+			}
+
+			return nil, []*ast.IdentifierExpression{decl}
+		}
+	}
+
+	return nil, nil
+}
+
+// returns the declaration of the optional variable which is compared in x == none or none == x, nil if the
+// comparison is of any other form or the variable cannot be narrowed
+func (tc *TypeChecker) comparedToNone(expr *ast.BinaryExpression) *ast.IdentifierExpression {
+	operand := expr.Left
+	if _, ok := expr.Left.(*ast.NoneExpression); ok {
+		operand = expr.Right
+	} else if _, ok := expr.Right.(*ast.NoneExpression); !ok {
+		return nil
+	}
+
+	for {
+		grouped, ok := operand.(*ast.GroupedExpression)
+		if !ok {
+			break
+		}
+		operand = grouped.Expr
+	}
+
+	ident, ok := operand.(*ast.IdentifierExpression)
+	if !ok {
+		return nil
+	}
+
+	sym, found := tc.env.Get(ident.String())
+	if !found || sym.decl == nil || tc.closureAssigned[sym.decl] {
+		return nil
+	}
+
+	if _, ok := sym.typ.(cotypes.OptionalType); !ok {
+		return nil
+	}
+
+	return sym.decl
+}
+
+// the body and the condition of a loop run again after the body, so variables which are assigned within the
+// body cannot be assumed to still hold a value. reassigned variables are matched by name, which is conservative
+func (tc *TypeChecker) forgetAssignedIn(nodes ...ast.Node) {
+	assigned := map[string]bool{}
+	for _, node := range nodes {
+		ast.Walk(node, func(n ast.Node) {
+			if stmt, ok := n.(*ast.AssignmentStatement); ok {
+				assigned[stmt.Identifier.String()] = true
+			}
+		})
+	}
+
+	maps.DeleteFunc(tc.narrowed, func(decl *ast.IdentifierExpression, _ bool) bool { return assigned[decl.String()] })
+}
+
+func (tc *TypeChecker) Transform(program *ast.Program) *ast.Program {
+	// structs and enums are declared before their fields and payloads are resolved, so that they can refer to each other
+	declaredTypes := []ast.Statement{}
+	for _, stmt := range program.Statements {
+		switch s := stmt.(type) {
+		case *ast.StructStatement:
+			if tc.declareStruct(s) == nil {
+				declaredTypes = append(declaredTypes, s)
+			}
+		case *ast.EnumStatement:
+			if tc.declareEnum(s) == nil {
+				declaredTypes = append(declaredTypes, s)
+			}
+		}
+	}
+
+	for _, stmt := range declaredTypes {
+		switch s := stmt.(type) {
+		case *ast.StructStatement:
+			tc.defineStruct(s)
+		case *ast.EnumStatement:
+			tc.defineEnum(s)
+		}
+	}
+
+	// top level variables which functions assign are known before any of them is declared, see declareVariable.
+	// assignments are matched by name, which is conservative
+	for _, stmt := range program.Statements {
+		if fnStmt, ok := stmt.(*ast.FunctionStatement); ok {
+			ast.Walk(fnStmt.Function.Body, func(n ast.Node) {
+				if assignStmt, ok := n.(*ast.AssignmentStatement); ok {
+					tc.functionAssigned[assignStmt.Identifier.String()] = true
+				}
+			})
+		}
+	}
+
+	// top level constants are declared and folded before any function body is checked, so that functions can use
+	// constants declared after them. constants can refer to each other in any order, so they are checked as soon as
+	// everything they refer to is declared. the others cannot be folded anyway and are reported where they are
+	hoistedConsts := map[*ast.ConstStatement]bool{}
+	for hoisted := true; hoisted; {
+		hoisted = false
+		for _, stmt := range program.Statements {
+			if constStmt, ok := stmt.(*ast.ConstStatement); ok && !hoistedConsts[constStmt] && tc.refersToConstantsOnly(constStmt.Value) {
+				tc.checkConstStatement(constStmt)
+				hoistedConsts[constStmt] = true
+				hoisted = true
+			}
+		}
+	}
+
+	// functions are hoisted, so that they can be called before their declaration
+	for _, stmt := range program.Statements {
+		if fnStmt, ok := stmt.(*ast.FunctionStatement); ok {
+			tc.declareFunction(fnStmt)
+		}
+	}
+
+	for _, stmt := range program.Statements {
+		if constStmt, ok := stmt.(*ast.ConstStatement); ok && hoistedConsts[constStmt] {
+			continue
+		}
+
+		tc.checkStatement(stmt)
+	}
+
+	// checking an instance of a generic function can instantiate further generic functions
+	for len(tc.pendingInstances) > 0 {
+		instance := tc.pendingInstances[0]
+		tc.pendingInstances = tc.pendingInstances[1:]
+
+		tc.typeParams = tc.functions[instance.Identifier.String()].typeArgs
+		tc.checkStatement(instance)
+		tc.typeParams = nil
+	}
+
+	tc.checkFunctionUses()
+
 	return program
 }
 
@@ -1,5 +1,12 @@
 package cotypes
 
+import (
+	"fmt"
+	"slices"
+	"strconv"
+	"strings"
+)
+
 type TypeCategory int
 
 type Type interface {
@@ -28,6 +35,34 @@ func (f FloatType) Equals(t Type) bool {
 	return ok
 }
 
+// fixed size integer, i8, i16, i32 and u8, u16, u32, u64. the 64 bit signed integer is int, which i64 is
+// another name for
+type SizedIntType struct {
+	Bits   int
+	Signed bool
+}
+
+func (s SizedIntType) String() string {
+	if s.Signed {
+		return "i" + strconv.Itoa(s.Bits)
+	}
+
+	return "u" + strconv.Itoa(s.Bits)
+}
+func (s SizedIntType) Equals(t Type) bool {
+	other, ok := t.(SizedIntType)
+	return ok && s.Bits == other.Bits && s.Signed == other.Signed
+}
+
+// single precision float, f32. float is double precision
+type Float32Type struct{}
+
+func (f Float32Type) String() string { return "f32" }
+func (f Float32Type) Equals(t Type) bool {
+	_, ok := t.(Float32Type)
+	return ok
+}
+
 type BoolType struct{}
 
 func (b BoolType) String() string { return "bool" }
@@ -44,6 +79,15 @@ func (s StringType) Equals(t Type) bool {
 	return ok
 }
 
+// single unicode scalar value, held as its code point
+type CharType struct{}
+
+func (c CharType) String() string { return "char" }
+func (c CharType) Equals(t Type) bool {
+	_, ok := t.(CharType)
+	return ok
+}
+
 type VoidType struct{}
 
 func (v VoidType) String() string { return "void" }
@@ -52,13 +96,343 @@ func (v VoidType) Equals(t Type) bool {
 	return ok
 }
 
+// fixed size array, [<element>; <size>]
+type ArrayType struct {
+	Element Type
+	Size    int64
+}
+
+func (a ArrayType) String() string { return fmt.Sprintf("[%s; %d]", a.Element, a.Size) }
+func (a ArrayType) Equals(t Type) bool {
+	other, ok := t.(ArrayType)
+	return ok && a.Size == other.Size && a.Element.Equals(other.Element)
+}
+
+// heap allocated list which grows as elements are pushed to it, []<element>
+type ListType struct {
+	Element Type
+}
+
+func (l ListType) String() string { return "[]" + l.Element.String() }
+func (l ListType) Equals(t Type) bool {
+	other, ok := t.(ListType)
+	return ok && l.Element.Equals(other.Element)
+}
+
+// heap allocated hash table, map[<key>]<value>
+type MapType struct {
+	Key   Type
+	Value Type
+}
+
+func (m MapType) String() string { return "map[" + m.Key.String() + "]" + m.Value.String() }
+func (m MapType) Equals(t Type) bool {
+	other, ok := t.(MapType)
+	return ok && m.Key.Equals(other.Key) && m.Value.Equals(other.Value)
+}
+
+// function value, fn(<parameter types>): <return type>. function values are closures, which
+// carry the variables they capture along with them
+type FunctionType struct {
+	Params []Type
+	Return Type
+}
+
+func (f FunctionType) String() string {
+	params := []string{}
+	for _, param := range f.Params {
+		params = append(params, param.String())
+	}
+
+	if f.Return.Equals(VoidType{}) {
+		return "fn(" + strings.Join(params, ", ") + ")"
+	}
+
+	return "fn(" + strings.Join(params, ", ") + "): " + f.Return.String()
+}
+func (f FunctionType) Equals(t Type) bool {
+	other, ok := t.(FunctionType)
+	return ok && f.Return.Equals(other.Return) && slices.EqualFunc(f.Params, other.Params, Type.Equals)
+}
+
+// value which may be absent, <type>?. the absence of a value is represented explicitly by a flag
+// stored alongside the value, rather than by a sentinel value of the inner type
+type OptionalType struct {
+	Inner Type
+}
+
+func (o OptionalType) String() string { return o.Inner.String() + "?" }
+func (o OptionalType) Equals(t Type) bool {
+	other, ok := t.(OptionalType)
+	return ok && o.Inner.Equals(other.Inner)
+}
+
+// type of the none literal, which takes on the optional type it is converted to
+type NoneType struct{}
+
+func (n NoneType) String() string { return "none" }
+func (n NoneType) Equals(t Type) bool {
+	_, ok := t.(NoneType)
+	return ok
+}
+
+// outcome of an operation which can fail, Result<<value>, <error>>. results are created with ok(...) and
+// err(...) and behave like an enum with an ok and an err variant, see Enum
+type ResultType struct {
+	Value Type
+	Error Type
+}
+
+func (r ResultType) String() string { return fmt.Sprintf("Result<%s, %s>", r.Value, r.Error) }
+func (r ResultType) Equals(t Type) bool {
+	other, ok := t.(ResultType)
+	return ok && r.Value.Equals(other.Value) && r.Error.Equals(other.Error)
+}
+
+// returns the enum which the result behaves as, it is matched on and lowered just like user defined enums
+func (r ResultType) Enum() *EnumType {
+	return &EnumType{
+		Name: r.String(),
+		Variants: []EnumVariant{
+			{Name: "ok", Payload: []Type{r.Value}},
+			{Name: "err", Payload: []Type{r.Error}},
+		},
+	}
+}
+
+// fixed size group of values of possibly different types, (<type>, <type>, ...). elements are accessed by
+// their position i.e. t.0 and tuples are passed around by value
+type TupleType struct {
+	Elements []Type
+}
+
+func (t TupleType) String() string {
+	elements := []string{}
+	for _, element := range t.Elements {
+		elements = append(elements, element.String())
+	}
+
+	return "(" + strings.Join(elements, ", ") + ")"
+}
+func (t TupleType) Equals(other Type) bool {
+	o, ok := other.(TupleType)
+	return ok && slices.EqualFunc(t.Elements, o.Elements, Type.Equals)
+}
+
+// returns the type and the position of the element accessed by the given name i.e. "0"
+func (t TupleType) Element(name string) (Type, int, bool) {
+	idx, err := strconv.Atoi(name)
+	if err != nil || idx < 0 || idx >= len(t.Elements) {
+		return nil, -1, false
+	}
+
+	return t.Elements[idx], idx, true
+}
+
+type StructField struct {
+	Name string
+	Type Type
+}
+
+// user defined struct, struct <name> { <field>: <type>, ... }. struct types are nominal, so two struct
+// types are equal if they have the same name. they are shared by pointer since the fields are resolved
+// after the type is declared, which allows structs to refer to each other
+type StructType struct {
+	Name   string
+	Fields []StructField
+}
+
+func (s *StructType) String() string { return s.Name }
+func (s *StructType) Equals(t Type) bool {
+	other, ok := t.(*StructType)
+	return ok && s.Name == other.Name
+}
+
+// returns the field with the given name along with its index
+func (s *StructType) Field(name string) (StructField, int, bool) {
+	for i, field := range s.Fields {
+		if field.Name == name {
+			return field, i, true
+		}
+	}
+
+	return StructField{}, -1, false
+}
+
+type EnumVariant struct {
+	Name    string
+	Payload []Type
+}
+
+// user defined tagged union, enum <name> { <variant>(<type>, ...), ... }. like structs, enum types are
+// nominal and shared by pointer
+type EnumType struct {
+	Name     string
+	Variants []EnumVariant
+}
+
+func (e *EnumType) String() string { return e.Name }
+func (e *EnumType) Equals(t Type) bool {
+	other, ok := t.(*EnumType)
+	return ok && e.Name == other.Name
+}
+
+// returns the variant with the given name along with its index, which is also its tag
+func (e *EnumType) Variant(name string) (EnumVariant, int, bool) {
+	for i, variant := range e.Variants {
+		if variant.Name == name {
+			return variant, i, true
+		}
+	}
+
+	return EnumVariant{}, -1, false
+}
+
+// set of types which the type argument of a type parameter must belong to. each constraint is a subset of
+// the ones before it, so a type parameter satisfies its own constraint as well as all the looser ones
+type Constraint int
+
+const (
+	// any type
+	ConstraintAny Constraint = iota
+	// types which support == and != (numeric types, bool, char, string)
+	ConstraintComparable
+	// types which support comparison operators (numeric types, char, string)
+	ConstraintOrdered
+	// types which support arithmetic and comparison operators (int, float and the sized numeric types)
+	ConstraintNumeric
+)
+
+func (c Constraint) String() string {
+	switch c {
+	case ConstraintComparable:
+		return "comparable"
+	case ConstraintOrdered:
+		return "ordered"
+	case ConstraintNumeric:
+		return "numeric"
+	default:
+		return "any"
+	}
+}
+
+// reports whether the type belongs to the set of types described by the constraint
+func (c Constraint) SatisfiedBy(t Type) bool {
+	if typeParam, ok := t.(TypeParamType); ok {
+		return typeParam.Constraint >= c
+	}
+
+	switch c {
+	case ConstraintComparable:
+		return IsInteger(t) || IsFloat(t) || t.Equals(BoolType{}) || t.Equals(CharType{}) || t.Equals(StringType{})
+	case ConstraintOrdered:
+		return IsInteger(t) || IsFloat(t) || t.Equals(CharType{}) || t.Equals(StringType{})
+	case ConstraintNumeric:
+		return IsInteger(t) || IsFloat(t)
+	default:
+		return true
+	}
+}
+
+// type parameter of a generic function. the body of a generic function is type checked once with its
+// type parameters left opaque, so that it only uses the operations permitted by their constraints
+type TypeParamType struct {
+	Name       string
+	Constraint Constraint
+}
+
+func (tp TypeParamType) String() string { return tp.Name }
+func (tp TypeParamType) Equals(t Type) bool {
+	other, ok := t.(TypeParamType)
+	return ok && tp.Name == other.Name
+}
+
+// returns the enum which values of the type behave as, if any
+func AsEnum(t Type) (*EnumType, bool) {
+	switch t := t.(type) {
+	case *EnumType:
+		return t, true
+	case ResultType:
+		return t.Enum(), true
+	default:
+		return nil, false
+	}
+}
+
+// reports whether values of the type can be used as map keys.
+// floats are excluded since NaN is never equal to itself
+func IsHashable(T Type) bool {
+	switch T.(type) {
+	case BoolType, CharType, StringType:
+		return true
+	default:
+		return IsInteger(T)
+	}
+}
+
 func GetTypeCategory(T Type) TypeCategory {
-	switch T {
-	case FloatType{}:
-		return CategoryNumeric
-	case IntType{}:
+	if IsInteger(T) || IsFloat(T) {
 		return CategoryNumeric
+	}
+
+	return CategoryUnknown
+}
+
+// returns the numeric type with the given name, which is also the name of the function converting values to it
+func NumericType(name string) (Type, bool) {
+	switch name {
+	case "int", "i64":
+		return IntType{}, true
+	case "float":
+		return FloatType{}, true
+	case "f32":
+		return Float32Type{}, true
+	case "i8", "i16", "i32":
+		bits, _ := strconv.Atoi(name[1:])
+		return SizedIntType{Bits: bits, Signed: true}, true
+	case "u8", "u16", "u32", "u64":
+		bits, _ := strconv.Atoi(name[1:])
+		return SizedIntType{Bits: bits, Signed: false}, true
 	default:
-		return CategoryUnknown
+		return nil, false
 	}
 }
+
+func IsInteger(t Type) bool {
+	switch t.(type) {
+	case IntType, SizedIntType:
+		return true
+	default:
+		return false
+	}
+}
+
+func IsFloat(t Type) bool {
+	switch t.(type) {
+	case FloatType, Float32Type:
+		return true
+	default:
+		return false
+	}
+}
+
+// returns the size in bits and signedness of the integer type. chars are laid out as the u32 holding their code point
+func IntegerLayout(t Type) (bits int, signed bool) {
+	switch t := t.(type) {
+	case SizedIntType:
+		return t.Bits, t.Signed
+	case CharType:
+		return 32, false
+	default:
+		return 64, true
+	}
+}
+
+// size in bits of the float type
+func FloatBits(t Type) int {
+	if _, ok := t.(Float32Type); ok {
+		return 32
+	}
+
+	return 64
+}